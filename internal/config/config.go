@@ -13,6 +13,23 @@ type Config struct {
 	DBName     string `mapstructure:"DB_NAME"`
 	JWTSecret  string `mapstructure:"JWT_SECRET"`
 	ServerPort string `mapstructure:"SERVER_PORT"`
+
+	ReturnWindowDays   int `mapstructure:"RETURN_WINDOW_DAYS"`
+	JobIntervalSeconds int `mapstructure:"JOB_INTERVAL_SECONDS"`
+
+	CoinRequestTTLHours int `mapstructure:"COIN_REQUEST_TTL_HOURS"`
+	MaxOpenCoinRequests int `mapstructure:"MAX_OPEN_COIN_REQUESTS"`
+
+	TransferAcceptWindowHours int `mapstructure:"TRANSFER_ACCEPT_WINDOW_HOURS"`
+
+	MonthlyGivingAllowance int `mapstructure:"MONTHLY_GIVING_ALLOWANCE"`
+
+	MaxTransferAmount           int `mapstructure:"MAX_TRANSFER_AMOUNT"`
+	MaxTransferDaily            int `mapstructure:"MAX_TRANSFER_DAILY"`
+	MaxTransferRecipientWeekly  int `mapstructure:"MAX_TRANSFER_RECIPIENT_WEEKLY"`
+	MaxTransferRecipientsHourly int `mapstructure:"MAX_TRANSFER_RECIPIENTS_HOURLY"`
+
+	FraudHoldForReview bool `mapstructure:"FRAUD_HOLD_FOR_REVIEW"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -24,6 +41,18 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	viper.SetDefault("RETURN_WINDOW_DAYS", 14)
+	viper.SetDefault("JOB_INTERVAL_SECONDS", 10)
+	viper.SetDefault("COIN_REQUEST_TTL_HOURS", 72)
+	viper.SetDefault("MAX_OPEN_COIN_REQUESTS", 3)
+	viper.SetDefault("TRANSFER_ACCEPT_WINDOW_HOURS", 72)
+	viper.SetDefault("MONTHLY_GIVING_ALLOWANCE", 100)
+	viper.SetDefault("MAX_TRANSFER_AMOUNT", 500)
+	viper.SetDefault("MAX_TRANSFER_DAILY", 1000)
+	viper.SetDefault("MAX_TRANSFER_RECIPIENT_WEEKLY", 1000)
+	viper.SetDefault("MAX_TRANSFER_RECIPIENTS_HOURLY", 10)
+	viper.SetDefault("FRAUD_HOLD_FOR_REVIEW", false)
+
 	viper.AutomaticEnv()
 
 	var config Config
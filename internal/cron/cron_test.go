@@ -0,0 +1,76 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	from := time.Date(2025, time.January, 30, 10, 17, 42, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{
+			name:     "Every minute",
+			expr:     "* * * * *",
+			expected: time.Date(2025, time.January, 30, 10, 18, 0, 0, time.UTC),
+		},
+		{
+			name:     "Every 15 minutes",
+			expr:     "*/15 * * * *",
+			expected: time.Date(2025, time.January, 30, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Monthly on the first at nine",
+			expr:     "0 9 1 * *",
+			expected: time.Date(2025, time.February, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Weekdays during office hours",
+			expr:     "0 9-17 * * 1-5",
+			expected: time.Date(2025, time.January, 30, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Sunday written as seven",
+			expr:     "30 8 * * 7",
+			expected: time.Date(2025, time.February, 2, 8, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "Day of month or day of week",
+			expr:     "0 0 15 * 6",
+			expected: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "Leap day",
+			expr:     "0 0 29 2 *",
+			expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Never matches",
+			expr: "0 0 31 2 *",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, s.Next(from))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	invalid := []string{
+		"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *",
+	}
+	for _, expr := range invalid {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidExpression, expr)
+	}
+}
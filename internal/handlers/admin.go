@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type SetRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type AdminHandler struct {
+	adminService services.AdminService
+}
+
+func NewAdminHandler(adminService services.AdminService) *AdminHandler {
+	return &AdminHandler{adminService: adminService}
+}
+
+func (h *AdminHandler) SetRole(c echo.Context) error {
+	var req SetRoleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if err := h.adminService.SetRole(auditContext(c), c.Param("username"), req.Role); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole):
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "unknown role",
+			})
+		case errors.Is(err, services.ErrUserNotFound):
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "user not found",
+			})
+		}
+
+		c.Logger().Errorf("admin set role error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed updating role",
+		})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *AdminHandler) ArchiveItem(c echo.Context) error {
+	return h.setArchived(c, true)
+}
+
+func (h *AdminHandler) UnarchiveItem(c echo.Context) error {
+	return h.setArchived(c, false)
+}
+
+func (h *AdminHandler) setArchived(c echo.Context, archived bool) error {
+	if err := h.adminService.SetArchived(auditContext(c), c.Param("item"), archived); err != nil {
+		if errors.Is(err, services.ErrItemNotFound) {
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"error": "item not found",
+			})
+		}
+
+		c.Logger().Errorf("admin archive item error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed updating item",
+		})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type CreateAuctionRequest struct {
+	Item            string    `json:"item"`
+	Size            string    `json:"size"`
+	Color           string    `json:"color"`
+	StartPrice      int       `json:"startPrice"`
+	Increment       int       `json:"increment"`
+	ExtendBySeconds *int      `json:"extendBySeconds"`
+	EndsAt          time.Time `json:"endsAt"`
+}
+
+type BidRequest struct {
+	Amount int `json:"amount"`
+}
+
+type AuctionHandler struct {
+	auctionService services.AuctionService
+}
+
+func NewAuctionHandler(auctionService services.AuctionService) *AuctionHandler {
+	return &AuctionHandler{auctionService: auctionService}
+}
+
+func (h *AuctionHandler) Create(c echo.Context) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req CreateAuctionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	auction := &models.Auction{
+		StartPrice:      req.StartPrice,
+		Increment:       req.Increment,
+		ExtendBySeconds: 120,
+		EndsAt:          req.EndsAt,
+	}
+	if req.ExtendBySeconds != nil {
+		auction.ExtendBySeconds = *req.ExtendBySeconds
+	}
+
+	opts := models.BuyOptions{Size: req.Size, Color: req.Color}
+	if err := h.auctionService.Create(c.Request().Context(), adminID, req.Item, opts, auction); err != nil {
+		return auctionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, auction)
+}
+
+func (h *AuctionHandler) List(c echo.Context) error {
+	auctions, err := h.auctionService.GetOpen(c.Request().Context())
+	if err != nil {
+		return auctionError(c, err)
+	}
+	if auctions == nil {
+		auctions = []models.Auction{}
+	}
+
+	return c.JSON(http.StatusOK, auctions)
+}
+
+func (h *AuctionHandler) Bid(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	auctionID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid auction id",
+		})
+	}
+
+	var req BidRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	bid, err := h.auctionService.Bid(c.Request().Context(), userID, auctionID, req.Amount)
+	if err != nil {
+		return auctionError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, bid)
+}
+
+func auctionError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrAuctionNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidAuction),
+		errors.Is(err, services.ErrAuctionItemUnavailable),
+		errors.Is(err, services.ErrAuctionClosed),
+		errors.Is(err, services.ErrBidTooLow),
+		errors.Is(err, services.ErrAlreadyHighestBidder),
+		errors.Is(err, services.ErrVariantNotFound),
+		errors.Is(err, services.ErrVariantRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("auction service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
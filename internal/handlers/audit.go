@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// List filters the audit log by the query parameters action, actor,
+// targetType, targetId, requestId, from and to (RFC 3339), and pages with
+// beforeId and limit.
+func (h *AuditHandler) List(c echo.Context) error {
+	filter := models.AuditFilter{
+		Action:     c.QueryParam("action"),
+		Actor:      c.QueryParam("actor"),
+		TargetType: c.QueryParam("targetType"),
+		TargetID:   c.QueryParam("targetId"),
+		RequestID:  c.QueryParam("requestId"),
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid from"})
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid to"})
+	}
+	if filter.BeforeID, err = queryInt(c, "beforeId"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid beforeId"})
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid limit"})
+	}
+
+	entries, err := h.auditService.List(c.Request().Context(), filter)
+	if err != nil {
+		return auditError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+// Verify checks that no audit entry was changed or removed.
+func (h *AuditHandler) Verify(c echo.Context) error {
+	verification, err := h.auditService.Verify(c.Request().Context())
+	if err != nil {
+		return auditError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, verification)
+}
+
+func queryTime(c echo.Context, name string) (*time.Time, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func queryInt(c echo.Context, name string) (int, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func auditError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrInvalidAuditFilter) {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("audit service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed reading audit log",
+	})
+}
@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"github.com/gratefultolord/merch-store/internal/services"
 	"github.com/labstack/echo/v4"
 	"net/http"
@@ -31,7 +30,7 @@ func (h *AuthHandler) Auth(c echo.Context) error {
 			"error": "username and password are required"})
 	}
 
-	token, err := h.authService.Auth(context.Background(), req.Username, req.Password)
+	token, err := h.authService.Auth(auditContext(c), req.Username, req.Password)
 	if err != nil {
 		c.Logger().Errorf("auth service error: %v, username: %s", err, req.Username)
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
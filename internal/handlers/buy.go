@@ -1,11 +1,12 @@
 package handlers
 
 import (
-	"context"
+	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/gratefultolord/merch-store/internal/services"
 	"github.com/labstack/echo/v4"
 	"net/http"
+	"strconv"
 )
 
 type BuyHandler struct {
@@ -51,7 +52,14 @@ func (h *BuyHandler) Buy(c echo.Context) error {
 		})
 	}
 
-	if err := h.inventoryService.Buy(context.Background(), userID, itemName); err != nil {
+	opts, ok := buyOptions(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid quantity",
+		})
+	}
+
+	if err := h.inventoryService.Buy(c.Request().Context(), userID, itemName, opts); err != nil {
 		c.Logger().Errorf("buy service error: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": err.Error(),
@@ -59,3 +67,79 @@ func (h *BuyHandler) Buy(c echo.Context) error {
 	}
 	return c.NoContent(http.StatusOK)
 }
+
+type GiftRequest struct {
+	ToUser  string `json:"toUser"`
+	Message string `json:"message"`
+}
+
+func (h *BuyHandler) Gift(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in request context",
+		})
+	}
+
+	itemName := c.Param("item")
+	if itemName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "item name is required",
+		})
+	}
+
+	opts, ok := buyOptions(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid quantity",
+		})
+	}
+
+	var req GiftRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	toUser, err := h.userRepo.GetByUsername(c.Request().Context(), req.ToUser)
+	if err != nil {
+		c.Logger().Errorf("gift service error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed getting recipient",
+		})
+	}
+	if toUser == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "recipient not found",
+		})
+	}
+
+	if err := h.inventoryService.Gift(
+		c.Request().Context(), userID, toUser.ID, itemName, opts, req.Message); err != nil {
+		c.Logger().Errorf("gift service error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+// buyOptions reads the variant, quantity and promo code query parameters.
+func buyOptions(c echo.Context) (models.BuyOptions, bool) {
+	opts := models.BuyOptions{
+		Size:      c.QueryParam("size"),
+		Color:     c.QueryParam("color"),
+		PromoCode: c.QueryParam("promo"),
+	}
+
+	if quantityParam := c.QueryParam("quantity"); quantityParam != "" {
+		quantity, err := strconv.Atoi(quantityParam)
+		if err != nil || quantity < 1 {
+			return opts, false
+		}
+		opts.Quantity = quantity
+	}
+
+	return opts, true
+}
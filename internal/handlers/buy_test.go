@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,8 +20,20 @@ type MockInventoryService struct {
 	mock.Mock
 }
 
-func (m *MockInventoryService) Buy(ctx context.Context, userID int, itemName string) error {
-	args := m.Called(ctx, userID, itemName)
+func (m *MockInventoryService) Buy(ctx context.Context, userID int, itemName string, opts models.BuyOptions) error {
+	args := m.Called(ctx, userID, itemName, opts)
+	return args.Error(0)
+}
+
+func (m *MockInventoryService) Gift(
+	ctx context.Context, senderID int, recipientID int, itemName string, opts models.BuyOptions, message string) error {
+	args := m.Called(ctx, senderID, recipientID, itemName, opts, message)
+	return args.Error(0)
+}
+
+func (m *MockInventoryService) TransferItem(
+	ctx context.Context, fromUserID int, toUserID int, itemName string, opts models.BuyOptions) error {
+	args := m.Called(ctx, fromUserID, toUserID, itemName, opts)
 	return args.Error(0)
 }
 
@@ -27,6 +42,7 @@ func TestBuyHandler_Buy(t *testing.T) {
 		name           string
 		userID         interface{}
 		itemName       string
+		query          string
 		mockSetup      func(m *MockInventoryService)
 		expectedStatus int
 		expectedBody   map[string]string
@@ -36,11 +52,36 @@ func TestBuyHandler_Buy(t *testing.T) {
 			userID:   1,
 			itemName: "sword",
 			mockSetup: func(m *MockInventoryService) {
-				m.On("Buy", mock.Anything, 1, "sword").Return(nil)
+				m.On("Buy", mock.Anything, 1, "sword", models.BuyOptions{}).Return(nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedBody:   nil,
 		},
+		{
+			name:     "Successful buy of a variant",
+			userID:   1,
+			itemName: "t-shirt",
+			query:    "?size=XL&color=black&quantity=2",
+			mockSetup: func(m *MockInventoryService) {
+				m.On("Buy", mock.Anything, 1, "t-shirt", models.BuyOptions{
+					Size:     "XL",
+					Color:    "black",
+					Quantity: 2,
+				}).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   nil,
+		},
+		{
+			name:     "Invalid quantity",
+			userID:   1,
+			itemName: "t-shirt",
+			query:    "?quantity=zero",
+			mockSetup: func(m *MockInventoryService) {
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"error": "invalid quantity"},
+		},
 		{
 			name:     "User not found",
 			userID:   nil,
@@ -73,7 +114,7 @@ func TestBuyHandler_Buy(t *testing.T) {
 			userID:   1,
 			itemName: "sword",
 			mockSetup: func(m *MockInventoryService) {
-				m.On("Buy", mock.Anything, 1, "sword").Return(errors.New("services: insufficient balance"))
+				m.On("Buy", mock.Anything, 1, "sword", models.BuyOptions{}).Return(errors.New("services: insufficient balance"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedBody:   map[string]string{"error": "services: insufficient balance"},
@@ -86,7 +127,7 @@ func TestBuyHandler_Buy(t *testing.T) {
 			handler := NewBuyHandler(mockInventoryService, nil, nil)
 
 			e := echo.New()
-			req := httptest.NewRequest(http.MethodPost, "/api/buy/"+tt.itemName, nil)
+			req := httptest.NewRequest(http.MethodPost, "/api/buy/"+tt.itemName+tt.query, nil)
 			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 			rec := httptest.NewRecorder()
 			c := e.NewContext(req, rec)
@@ -117,3 +158,78 @@ func TestBuyHandler_Buy(t *testing.T) {
 		})
 	}
 }
+
+func TestBuyHandler_Gift(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		mockSetup      func(s *MockInventoryService, u *mocks.UserRepo)
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:        "Successful gift",
+			requestBody: `{"toUser": "bob", "message": "thanks!"}`,
+			mockSetup: func(s *MockInventoryService, u *mocks.UserRepo) {
+				u.On("GetByUsername", mock.Anything, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil)
+				s.On("Gift", mock.Anything, 1, 2, "cup", models.BuyOptions{}, "thanks!").Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   nil,
+		},
+		{
+			name:        "Recipient not found",
+			requestBody: `{"toUser": "nobody"}`,
+			mockSetup: func(s *MockInventoryService, u *mocks.UserRepo) {
+				u.On("GetByUsername", mock.Anything, "nobody").Return(nil, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]string{"error": "recipient not found"},
+		},
+		{
+			name:        "Inventory service error",
+			requestBody: `{"toUser": "bob"}`,
+			mockSetup: func(s *MockInventoryService, u *mocks.UserRepo) {
+				u.On("GetByUsername", mock.Anything, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil)
+				s.On("Gift", mock.Anything, 1, 2, "cup", models.BuyOptions{}, "").
+					Return(errors.New("services: insufficient balance"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"error": "services: insufficient balance"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockInventoryService := new(MockInventoryService)
+			mockUserRepo := new(mocks.UserRepo)
+			tt.mockSetup(mockInventoryService, mockUserRepo)
+			handler := NewBuyHandler(mockInventoryService, mockUserRepo, nil)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/gift/cup", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("item")
+			c.SetParamValues("cup")
+			c.Set("userID", 1)
+
+			err := handler.Gift(c)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedBody != nil {
+				var body map[string]string
+				err := json.Unmarshal(rec.Body.Bytes(), &body)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedBody, body)
+			} else {
+				assert.Empty(t, rec.Body.String())
+			}
+
+			mockInventoryService.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type PriceRuleRequest struct {
+	Item            string    `json:"item"`
+	Category        *string   `json:"category"`
+	Price           *int      `json:"price"`
+	DiscountPercent *int      `json:"discountPercent"`
+	StartsAt        time.Time `json:"startsAt"`
+	EndsAt          time.Time `json:"endsAt"`
+}
+
+type StockRequest struct {
+	Size  string `json:"size"`
+	Color string `json:"color"`
+	Stock *int   `json:"stock"`
+}
+
+type PreorderStateRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+type CatalogHandler struct {
+	catalogService services.CatalogService
+}
+
+func NewCatalogHandler(catalogService services.CatalogService) *CatalogHandler {
+	return &CatalogHandler{catalogService: catalogService}
+}
+
+func (h *CatalogHandler) List(c echo.Context) error {
+	catalog, err := h.catalogService.GetCatalog(c.Request().Context())
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, catalog)
+}
+
+func (h *CatalogHandler) CreatePriceRule(c echo.Context) error {
+	var req PriceRuleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	rule := &models.PriceRule{
+		Category:        req.Category,
+		Price:           req.Price,
+		DiscountPercent: req.DiscountPercent,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+	}
+
+	if err := h.catalogService.CreatePriceRule(auditContext(c), req.Item, rule); err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, rule)
+}
+
+func (h *CatalogHandler) PriceRules(c echo.Context) error {
+	rules, err := h.catalogService.GetPriceRules(c.Request().Context())
+	if err != nil {
+		return catalogError(c, err)
+	}
+	if rules == nil {
+		rules = []models.PriceRule{}
+	}
+
+	return c.JSON(http.StatusOK, rules)
+}
+
+func (h *CatalogHandler) DeletePriceRule(c echo.Context) error {
+	ruleID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid price rule id",
+		})
+	}
+
+	if err := h.catalogService.DeletePriceRule(auditContext(c), ruleID); err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *CatalogHandler) PriceHistory(c echo.Context) error {
+	history, err := h.catalogService.GetPriceHistory(c.Request().Context(), c.Param("item"))
+	if err != nil {
+		return catalogError(c, err)
+	}
+	if history == nil {
+		history = []models.PriceHistory{}
+	}
+
+	return c.JSON(http.StatusOK, history)
+}
+
+// SetStock replaces the stock of an item variant. A null stock makes the
+// variant unlimited.
+func (h *CatalogHandler) SetStock(c echo.Context) error {
+	var req StockRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	err := h.catalogService.SetStock(auditContext(c), c.Param("item"), req.Size, req.Color, req.Stock)
+	if err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *CatalogHandler) SetPreorder(c echo.Context) error {
+	var req PreorderStateRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if err := h.catalogService.SetPreorderEnabled(auditContext(c), c.Param("item"), req.Enabled); err != nil {
+		return catalogError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func catalogError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrPriceRuleNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPriceRule),
+		errors.Is(err, services.ErrInvalidStock),
+		errors.Is(err, services.ErrVariantNotFound),
+		errors.Is(err, services.ErrVariantRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("catalog service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing catalog request",
+	})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type CreateCoinRequestRequest struct {
+	FromUser string `json:"fromUser"`
+	Amount   int    `json:"amount"`
+	Reason   string `json:"reason"`
+}
+
+type CoinRequestHandler struct {
+	coinRequestService services.CoinRequestService
+}
+
+func NewCoinRequestHandler(coinRequestService services.CoinRequestService) *CoinRequestHandler {
+	return &CoinRequestHandler{coinRequestService: coinRequestService}
+}
+
+func (h *CoinRequestHandler) Create(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req CreateCoinRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	request, err := h.coinRequestService.Create(c.Request().Context(), userID, req.FromUser, req.Amount, req.Reason)
+	if err != nil {
+		return coinRequestError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, request)
+}
+
+func (h *CoinRequestHandler) List(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	requests, err := h.coinRequestService.List(c.Request().Context(), userID)
+	if err != nil {
+		return coinRequestError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, requests)
+}
+
+func (h *CoinRequestHandler) Accept(c echo.Context) error {
+	return h.answer(c, h.coinRequestService.Accept)
+}
+
+func (h *CoinRequestHandler) Decline(c echo.Context) error {
+	return h.answer(c, h.coinRequestService.Decline)
+}
+
+func (h *CoinRequestHandler) answer(
+	c echo.Context, action func(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error)) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	requestID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid request id",
+		})
+	}
+
+	request, err := action(c.Request().Context(), userID, requestID)
+	if err != nil {
+		return coinRequestError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, request)
+}
+
+func coinRequestError(c echo.Context, err error) error {
+	if ok, resp := transferLimitError(c, err); ok {
+		return resp
+	}
+
+	switch {
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrCoinRequestNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidCoinRequest),
+		errors.Is(err, services.ErrDuplicateCoinRequest),
+		errors.Is(err, services.ErrCoinRequestResolved):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyCoinRequests):
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("coin request service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
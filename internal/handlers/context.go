@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+// currentUserID returns the authenticated user id set by the auth middleware.
+func currentUserID(c echo.Context) (int, bool) {
+	userID, ok := c.Get("userID").(int)
+	return userID, ok
+}
+
+// currentTenant returns the tenant resolved by the tenant middleware.
+func currentTenant(c echo.Context) (*models.Tenant, bool) {
+	tenant, ok := c.Get("tenant").(*models.Tenant)
+	return tenant, ok
+}
+
+// pathID parses a positive integer path parameter.
+func pathID(c echo.Context, name string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(name))
+	if err != nil || id < 1 {
+		return 0, false
+	}
+	return id, true
+}
+
+// transferLimitError answers 429 with the code of the broken limit when err
+// is a transfer limit error. It reports false for any other error.
+func transferLimitError(c echo.Context, err error) (bool, error) {
+	var limitErr *services.TransferLimitError
+	if !errors.As(err, &limitErr) {
+		return false, nil
+	}
+	return true, c.JSON(http.StatusTooManyRequests, map[string]string{
+		"error": err.Error(),
+		"code":  limitErr.Code,
+	})
+}
+
+// auditContext carries the request id and the authenticated user, if any,
+// into the audit entries recorded while serving the request.
+func auditContext(c echo.Context) context.Context {
+	userID, _ := currentUserID(c)
+	return services.WithAudit(c.Request().Context(),
+		c.Response().Header().Get(echo.HeaderXRequestID), userID)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type ReviewFraudFlagRequest struct {
+	Status string `json:"status"`
+}
+
+type FraudHandler struct {
+	fraudService services.FraudService
+}
+
+func NewFraudHandler(fraudService services.FraudService) *FraudHandler {
+	return &FraudHandler{fraudService: fraudService}
+}
+
+func (h *FraudHandler) List(c echo.Context) error {
+	flags, err := h.fraudService.List(c.Request().Context(), c.QueryParam("status"))
+	if err != nil {
+		return fraudError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, flags)
+}
+
+func (h *FraudHandler) Review(c echo.Context) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	flagID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid flag id",
+		})
+	}
+
+	var req ReviewFraudFlagRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	flag, err := h.fraudService.Review(c.Request().Context(), adminID, flagID, req.Status)
+	if err != nil {
+		return fraudError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, flag)
+}
+
+func fraudError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrFraudFlagNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidFraudReview):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrFraudFlagReviewed):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("fraud service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing fraud flags",
+	})
+}
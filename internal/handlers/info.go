@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"github.com/gratefultolord/merch-store/internal/services"
 	"github.com/labstack/echo/v4"
 	"net/http"
@@ -30,7 +29,7 @@ func (h *InfoHandler) Info(c echo.Context) error {
 			"error": "invalid user ID type"})
 	}
 
-	info, err := h.infoService.GetUserInfo(context.Background(), userID)
+	info, err := h.infoService.GetUserInfo(c.Request().Context(), userID)
 	if err != nil {
 		c.Logger().Errorf("info service error: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -41,5 +40,9 @@ func (h *InfoHandler) Info(c echo.Context) error {
 			"error": "user not found"})
 	}
 
+	if tenant, ok := currentTenant(c); ok {
+		info.Currency = tenant.CurrencyName
+	}
+
 	return c.JSON(http.StatusOK, info)
 }
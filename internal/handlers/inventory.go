@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type TransferItemRequest struct {
+	ToUser   string `json:"toUser"`
+	Item     string `json:"item"`
+	Size     string `json:"size"`
+	Color    string `json:"color"`
+	Quantity int    `json:"quantity"`
+}
+
+type InventoryHandler struct {
+	inventoryService services.InventoryService
+	userRepo         repository.UserRepo
+}
+
+func NewInventoryHandler(
+	inventoryService services.InventoryService,
+	userRepo repository.UserRepo,
+) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		userRepo:         userRepo,
+	}
+}
+
+func (h *InventoryHandler) Transfer(c echo.Context) error {
+	fromUserID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req TransferItemRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if req.Item == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "item name is required",
+		})
+	}
+	if req.Quantity < 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid quantity",
+		})
+	}
+
+	toUser, err := h.userRepo.GetByUsername(c.Request().Context(), req.ToUser)
+	if err != nil {
+		c.Logger().Errorf("transfer item error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed getting receiver",
+		})
+	}
+	if toUser == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "receiver not found",
+		})
+	}
+
+	opts := models.BuyOptions{
+		Size:     req.Size,
+		Color:    req.Color,
+		Quantity: req.Quantity,
+	}
+
+	if err := h.inventoryService.TransferItem(
+		c.Request().Context(), fromUserID, toUser.ID, req.Item, opts); err != nil {
+		c.Logger().Errorf("transfer item error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": err.Error(),
+		})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type IssueCoinsRequest struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+type GrantCoinsRequest struct {
+	Amount int    `json:"amount"`
+	Period string `json:"period"`
+	Reason string `json:"reason"`
+}
+
+type IssuanceHandler struct {
+	issuanceService services.IssuanceService
+}
+
+func NewIssuanceHandler(issuanceService services.IssuanceService) *IssuanceHandler {
+	return &IssuanceHandler{issuanceService: issuanceService}
+}
+
+func (h *IssuanceHandler) List(c echo.Context) error {
+	issuances, err := h.issuanceService.List(c.Request().Context())
+	if err != nil {
+		return issuanceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, issuances)
+}
+
+func (h *IssuanceHandler) Issue(c echo.Context) error {
+	return h.issueToUser(c, h.issuanceService.Issue)
+}
+
+func (h *IssuanceHandler) Adjust(c echo.Context) error {
+	return h.issueToUser(c, h.issuanceService.Adjust)
+}
+
+func (h *IssuanceHandler) issueToUser(c echo.Context, action func(
+	ctx context.Context, adminID int, username string, amount int, reason string) (*models.Issuance, error)) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req IssueCoinsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	issuance, err := action(auditContext(c), adminID, req.ToUser, req.Amount, req.Reason)
+	if err != nil {
+		return issuanceError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, issuance)
+}
+
+func (h *IssuanceHandler) Grant(c echo.Context) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req GrantCoinsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	issuance, err := h.issuanceService.Grant(auditContext(c), adminID, req.Amount, req.Period, req.Reason)
+	if err != nil {
+		return issuanceError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, issuance)
+}
+
+func issuanceError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidIssuance), errors.Is(err, services.ErrAdjustmentExceedsBalance):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrGrantAlreadyPaid):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("issuance service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing issuance",
+	})
+}
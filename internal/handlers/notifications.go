@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type NotificationHandler struct {
+	notificationRepo repository.NotificationRepo
+}
+
+func NewNotificationHandler(notificationRepo repository.NotificationRepo) *NotificationHandler {
+	return &NotificationHandler{notificationRepo: notificationRepo}
+}
+
+func (h *NotificationHandler) MarkRead(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	if err := h.notificationRepo.MarkAllRead(c.Request().Context(), userID); err != nil {
+		c.Logger().Errorf("notification repo error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed marking notifications read",
+		})
+	}
+
+	return c.NoContent(http.StatusOK)
+}
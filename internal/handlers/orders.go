@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"strconv"
+)
+
+type OrderStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type OrderHandler struct {
+	orderService services.OrderService
+}
+
+func NewOrderHandler(orderService services.OrderService) *OrderHandler {
+	return &OrderHandler{orderService: orderService}
+}
+
+func (h *OrderHandler) List(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	orders, err := h.orderService.GetUserOrders(c.Request().Context(), userID)
+	if err != nil {
+		return orderError(c, err)
+	}
+	if orders == nil {
+		orders = []models.Order{}
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (h *OrderHandler) SetDelivery(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid order id",
+		})
+	}
+
+	var req models.Delivery
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	order, err := h.orderService.SetDelivery(c.Request().Context(), userID, orderID, req)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// ByStatus lists orders in the given status for merch managers. Defaults to
+// newly placed orders.
+func (h *OrderHandler) ByStatus(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = models.OrderStatusPlaced
+	}
+
+	orders, err := h.orderService.GetByStatus(c.Request().Context(), status)
+	if err != nil {
+		return orderError(c, err)
+	}
+	if orders == nil {
+		orders = []models.Order{}
+	}
+
+	return c.JSON(http.StatusOK, orders)
+}
+
+func (h *OrderHandler) UpdateStatus(c echo.Context) error {
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid order id",
+		})
+	}
+
+	var req OrderStatusRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	order, err := h.orderService.UpdateStatus(c.Request().Context(), orderID, req.Status)
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, order)
+}
+
+// PickList exports approved orders as CSV, grouped by pickup office.
+func (h *OrderHandler) PickList(c echo.Context) error {
+	entries, err := h.orderService.GetPickList(c.Request().Context())
+	if err != nil {
+		return orderError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentType, "text/csv")
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="picklist.csv"`)
+	c.Response().WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(c.Response())
+	w.Write([]string{"office", "order_id", "username", "item", "size", "color", "quantity", "shipping_address"})
+	for _, e := range entries {
+		w.Write([]string{
+			e.Office,
+			strconv.Itoa(e.OrderID),
+			e.Username,
+			e.Item,
+			e.Size,
+			e.Color,
+			strconv.Itoa(e.Quantity),
+			e.ShippingAddress,
+		})
+	}
+	w.Flush()
+
+	return w.Error()
+}
+
+func orderError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidOrderStatus),
+		errors.Is(err, services.ErrInvalidOrderTransition),
+		errors.Is(err, services.ErrInvalidDelivery),
+		errors.Is(err, services.ErrDeliveryLocked),
+		errors.Is(err, services.ErrReturnedItemsNotOwned):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("order service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing order",
+	})
+}
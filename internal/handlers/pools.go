@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type CreatePoolRequest struct {
+	Title    string    `json:"title"`
+	Item     string    `json:"item"`
+	Size     string    `json:"size"`
+	Color    string    `json:"color"`
+	Deadline time.Time `json:"deadline"`
+}
+
+type ContributeRequest struct {
+	Amount int `json:"amount"`
+}
+
+type PoolHandler struct {
+	poolService services.PoolService
+}
+
+func NewPoolHandler(poolService services.PoolService) *PoolHandler {
+	return &PoolHandler{poolService: poolService}
+}
+
+func (h *PoolHandler) Create(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req CreatePoolRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	pool := &models.Pool{Title: req.Title, Deadline: req.Deadline}
+	opts := models.BuyOptions{Size: req.Size, Color: req.Color}
+	if err := h.poolService.Create(c.Request().Context(), userID, pool, req.Item, opts); err != nil {
+		return poolError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, pool)
+}
+
+func (h *PoolHandler) List(c echo.Context) error {
+	pools, err := h.poolService.List(c.Request().Context())
+	if err != nil {
+		return poolError(c, err)
+	}
+	if pools == nil {
+		pools = []models.Pool{}
+	}
+
+	return c.JSON(http.StatusOK, pools)
+}
+
+func (h *PoolHandler) Contribute(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	poolID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid pool id",
+		})
+	}
+
+	var req ContributeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	contribution, err := h.poolService.Contribute(c.Request().Context(), userID, poolID, req.Amount)
+	if err != nil {
+		return poolError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, contribution)
+}
+
+func poolError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrPoolNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidPool),
+		errors.Is(err, services.ErrPoolItemUnavailable),
+		errors.Is(err, services.ErrPoolClosed),
+		errors.Is(err, services.ErrInvalidContribution),
+		errors.Is(err, services.ErrPoolContributionTooHigh),
+		errors.Is(err, services.ErrVariantNotFound),
+		errors.Is(err, services.ErrVariantRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("pool service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
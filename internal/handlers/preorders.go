@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type PreorderHandler struct {
+	preorderService services.PreorderService
+}
+
+func NewPreorderHandler(preorderService services.PreorderService) *PreorderHandler {
+	return &PreorderHandler{preorderService: preorderService}
+}
+
+func (h *PreorderHandler) Place(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	itemName := c.Param("item")
+	if itemName == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "item name is required",
+		})
+	}
+
+	opts, ok := buyOptions(c)
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid quantity",
+		})
+	}
+
+	preorder, err := h.preorderService.Place(c.Request().Context(), userID, itemName, opts)
+	if err != nil {
+		return preorderError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, preorder)
+}
+
+func (h *PreorderHandler) List(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	preorders, err := h.preorderService.List(c.Request().Context(), userID)
+	if err != nil {
+		return preorderError(c, err)
+	}
+	if preorders == nil {
+		preorders = []models.Preorder{}
+	}
+
+	return c.JSON(http.StatusOK, preorders)
+}
+
+func (h *PreorderHandler) Cancel(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	preorderID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid preorder id",
+		})
+	}
+
+	preorder, err := h.preorderService.Cancel(c.Request().Context(), userID, preorderID)
+	if err != nil {
+		return preorderError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, preorder)
+}
+
+func preorderError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrPreorderNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrPreorderNotAllowed),
+		errors.Is(err, services.ErrPreorderItemAvailable),
+		errors.Is(err, services.ErrPreorderResolved),
+		errors.Is(err, services.ErrVariantNotFound),
+		errors.Is(err, services.ErrVariantRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("preorder service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
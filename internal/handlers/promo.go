@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type PromoHandler struct {
+	promoService services.PromoService
+}
+
+func NewPromoHandler(promoService services.PromoService) *PromoHandler {
+	return &PromoHandler{promoService: promoService}
+}
+
+func (h *PromoHandler) Create(c echo.Context) error {
+	var req models.PromoCode
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if err := h.promoService.Create(c.Request().Context(), &req); err != nil {
+		if errors.Is(err, services.ErrInvalidPromoCode) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		c.Logger().Errorf("promo service error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed creating promo code",
+		})
+	}
+
+	return c.JSON(http.StatusCreated, req)
+}
+
+func (h *PromoHandler) List(c echo.Context) error {
+	promos, err := h.promoService.GetAll(c.Request().Context())
+	if err != nil {
+		c.Logger().Errorf("promo service error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed getting promo codes",
+		})
+	}
+	if promos == nil {
+		promos = []models.PromoCode{}
+	}
+
+	return c.JSON(http.StatusOK, promos)
+}
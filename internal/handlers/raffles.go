@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type CreateRaffleRequest struct {
+	Title             string    `json:"title"`
+	TicketPrice       int       `json:"ticketPrice"`
+	MaxTicketsPerUser int       `json:"maxTicketsPerUser"`
+	ClosesAt          time.Time `json:"closesAt"`
+	Item              string    `json:"item"`
+	Size              string    `json:"size"`
+	Color             string    `json:"color"`
+	PrizeCoins        int       `json:"prizeCoins"`
+}
+
+type BuyTicketsRequest struct {
+	Quantity int `json:"quantity"`
+}
+
+type RaffleHandler struct {
+	raffleService services.RaffleService
+}
+
+func NewRaffleHandler(raffleService services.RaffleService) *RaffleHandler {
+	return &RaffleHandler{raffleService: raffleService}
+}
+
+func (h *RaffleHandler) Create(c echo.Context) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req CreateRaffleRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	raffle := &models.Raffle{
+		Title:             req.Title,
+		TicketPrice:       req.TicketPrice,
+		MaxTicketsPerUser: req.MaxTicketsPerUser,
+		ClosesAt:          req.ClosesAt,
+		PrizeCoins:        req.PrizeCoins,
+	}
+
+	opts := models.BuyOptions{Size: req.Size, Color: req.Color}
+	if err := h.raffleService.Create(c.Request().Context(), adminID, raffle, req.Item, opts); err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, raffle)
+}
+
+func (h *RaffleHandler) List(c echo.Context) error {
+	raffles, err := h.raffleService.List(c.Request().Context())
+	if err != nil {
+		return raffleError(c, err)
+	}
+	if raffles == nil {
+		raffles = []models.Raffle{}
+	}
+
+	return c.JSON(http.StatusOK, raffles)
+}
+
+func (h *RaffleHandler) BuyTickets(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	raffleID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid raffle id",
+		})
+	}
+
+	req := BuyTicketsRequest{Quantity: 1}
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	ticket, err := h.raffleService.BuyTickets(c.Request().Context(), userID, raffleID, req.Quantity)
+	if err != nil {
+		return raffleError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, ticket)
+}
+
+func raffleError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrItemNotFound), errors.Is(err, services.ErrRaffleNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRaffle),
+		errors.Is(err, services.ErrRaffleItemUnavailable),
+		errors.Is(err, services.ErrRaffleClosed),
+		errors.Is(err, services.ErrInvalidTicketQuantity),
+		errors.Is(err, services.ErrRaffleTicketLimit),
+		errors.Is(err, services.ErrVariantNotFound),
+		errors.Is(err, services.ErrVariantRequired):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("raffle service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": err.Error(),
+	})
+}
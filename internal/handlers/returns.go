@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type ReturnRequest struct {
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+type ReturnHandler struct {
+	returnService services.ReturnService
+}
+
+func NewReturnHandler(returnService services.ReturnService) *ReturnHandler {
+	return &ReturnHandler{returnService: returnService}
+}
+
+func (h *ReturnHandler) RequestReturn(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	orderID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid order id",
+		})
+	}
+
+	var req ReturnRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	ret, err := h.returnService.RequestReturn(c.Request().Context(), userID, orderID, req.Quantity, req.Reason)
+	if err != nil {
+		return returnError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, ret)
+}
+
+func (h *ReturnHandler) Pending(c echo.Context) error {
+	returns, err := h.returnService.GetPendingReturns(c.Request().Context())
+	if err != nil {
+		return returnError(c, err)
+	}
+	if returns == nil {
+		returns = []models.Return{}
+	}
+
+	return c.JSON(http.StatusOK, returns)
+}
+
+func (h *ReturnHandler) Approve(c echo.Context) error {
+	return h.review(c, h.returnService.Approve)
+}
+
+func (h *ReturnHandler) Reject(c echo.Context) error {
+	return h.review(c, h.returnService.Reject)
+}
+
+func (h *ReturnHandler) review(
+	c echo.Context, action func(ctx context.Context, reviewerID int, returnID int) (*models.Return, error)) error {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	returnID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid return id",
+		})
+	}
+
+	ret, err := action(c.Request().Context(), reviewerID, returnID)
+	if err != nil {
+		return returnError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, ret)
+}
+
+func returnError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrOrderNotFound), errors.Is(err, services.ErrReturnNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrReturnWindowExpired),
+		errors.Is(err, services.ErrInvalidReturnQuantity),
+		errors.Is(err, services.ErrReturnAlreadyReviewed),
+		errors.Is(err, services.ErrReturnedItemsNotOwned),
+		errors.Is(err, services.ErrGiftNotReturnable):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("return service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing return",
+	})
+}
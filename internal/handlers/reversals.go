@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type ReverseTransactionRequest struct {
+	Reason string `json:"reason"`
+	// AllowNegative lets the reversal take the receiver's balance below
+	// zero once another admin approves it.
+	AllowNegative bool `json:"allowNegative"`
+}
+
+type ReversalHandler struct {
+	reversalService services.ReversalService
+}
+
+func NewReversalHandler(reversalService services.ReversalService) *ReversalHandler {
+	return &ReversalHandler{reversalService: reversalService}
+}
+
+func (h *ReversalHandler) List(c echo.Context) error {
+	reversals, err := h.reversalService.List(c.Request().Context())
+	if err != nil {
+		return reversalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reversals)
+}
+
+func (h *ReversalHandler) Reverse(c echo.Context) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	transactionID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid transaction id",
+		})
+	}
+
+	var req ReverseTransactionRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	reversal, err := h.reversalService.Reverse(
+		auditContext(c), adminID, transactionID, req.Reason, req.AllowNegative)
+	if err != nil {
+		return reversalError(c, err)
+	}
+
+	if reversal.Status == models.ReversalStatusPending {
+		return c.JSON(http.StatusAccepted, reversal)
+	}
+
+	return c.JSON(http.StatusOK, reversal)
+}
+
+func (h *ReversalHandler) Approve(c echo.Context) error {
+	return h.resolve(c, h.reversalService.Approve)
+}
+
+func (h *ReversalHandler) Reject(c echo.Context) error {
+	return h.resolve(c, h.reversalService.Reject)
+}
+
+func (h *ReversalHandler) resolve(c echo.Context,
+	action func(ctx context.Context, adminID int, reversalID int) (*models.Reversal, error)) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	reversalID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid reversal id",
+		})
+	}
+
+	reversal, err := action(auditContext(c), adminID, reversalID)
+	if err != nil {
+		return reversalError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, reversal)
+}
+
+func reversalError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTransactionNotFound), errors.Is(err, services.ErrReversalNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidReversal), errors.Is(err, services.ErrNotReversible):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyReversed), errors.Is(err, services.ErrReversalPending),
+		errors.Is(err, services.ErrReversalInsufficientFunds), errors.Is(err, services.ErrReversalResolved):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrReversalSelfApproval):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("reversal service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing reversal",
+	})
+}
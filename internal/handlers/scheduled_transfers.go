@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"time"
+)
+
+type CreateScheduledTransferRequest struct {
+	ToUser  string     `json:"toUser"`
+	Amount  int        `json:"amount"`
+	RunAt   *time.Time `json:"runAt"`
+	Cron    *string    `json:"cron"`
+	CatchUp string     `json:"catchUp"`
+}
+
+type ScheduledTransferHandler struct {
+	scheduledTransferService services.ScheduledTransferService
+}
+
+func NewScheduledTransferHandler(scheduledTransferService services.ScheduledTransferService) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{scheduledTransferService: scheduledTransferService}
+}
+
+func (h *ScheduledTransferHandler) Create(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req CreateScheduledTransferRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	schedule := &models.ScheduledTransfer{
+		Amount:    req.Amount,
+		Cron:      req.Cron,
+		CatchUp:   req.CatchUp,
+		NextRunAt: req.RunAt,
+	}
+
+	if err := h.scheduledTransferService.Create(c.Request().Context(), userID, schedule, req.ToUser); err != nil {
+		return scheduledTransferError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *ScheduledTransferHandler) List(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	schedules, err := h.scheduledTransferService.List(c.Request().Context(), userID)
+	if err != nil {
+		return scheduledTransferError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, schedules)
+}
+
+func (h *ScheduledTransferHandler) Runs(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	scheduleID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid scheduled transfer id",
+		})
+	}
+
+	runs, err := h.scheduledTransferService.GetRuns(c.Request().Context(), userID, scheduleID)
+	if err != nil {
+		return scheduledTransferError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, runs)
+}
+
+func (h *ScheduledTransferHandler) Cancel(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	scheduleID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid scheduled transfer id",
+		})
+	}
+
+	if err := h.scheduledTransferService.Cancel(c.Request().Context(), userID, scheduleID); err != nil {
+		return scheduledTransferError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func scheduledTransferError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrScheduleNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidSchedule):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("scheduled transfer service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing scheduled transfer",
+	})
+}
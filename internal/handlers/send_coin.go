@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/gratefultolord/merch-store/internal/services"
 	"github.com/labstack/echo/v4"
@@ -12,20 +13,30 @@ import (
 type SendCoinRequest struct {
 	ToUser string `json:"toUser" validate:"required"`
 	Amount int    `json:"amount" validate:"required,gte=1"`
+	Hold   bool   `json:"hold"`
+	// FromAllowance pays the transfer from the giving allowance instead of
+	// the balance.
+	FromAllowance bool `json:"fromAllowance"`
 }
 
 type SendCoinHandler struct {
-	coinService services.CoinService
-	userRepo    repository.UserRepo
+	transferService  services.TransferService
+	allowanceService services.AllowanceService
+	teamService      services.TeamService
+	userRepo         repository.UserRepo
 }
 
 func NewSendCoinHandler(
-	coinService services.CoinService,
+	transferService services.TransferService,
+	allowanceService services.AllowanceService,
+	teamService services.TeamService,
 	userRepo repository.UserRepo,
 ) *SendCoinHandler {
 	return &SendCoinHandler{
-		coinService: coinService,
-		userRepo:    userRepo,
+		transferService:  transferService,
+		allowanceService: allowanceService,
+		teamService:      teamService,
+		userRepo:         userRepo,
 	}
 }
 
@@ -52,7 +63,7 @@ func (h *SendCoinHandler) SendCoin(c echo.Context) error {
 		})
 	}
 
-	toUser, err := h.userRepo.GetByUsername(context.Background(), req.ToUser)
+	toUser, err := h.userRepo.GetByUsername(c.Request().Context(), req.ToUser)
 	if err != nil {
 		c.Logger().Errorf("send coin service error: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -66,12 +77,114 @@ func (h *SendCoinHandler) SendCoin(c echo.Context) error {
 		})
 	}
 
-	if err := h.coinService.Send(context.Background(), fromUserID, toUser.ID, req.Amount); err != nil {
+	if req.FromAllowance {
+		if req.Hold {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"error": "allowance transfers cannot be held",
+			})
+		}
+
+		err := h.allowanceService.Give(c.Request().Context(), fromUserID, toUser.ID, req.Amount)
+		if err != nil {
+			if ok, resp := transferLimitError(c, err); ok {
+				return resp
+			}
+			if errors.Is(err, services.ErrInvalidTransfer) || errors.Is(err, services.ErrInsufficientAllowance) ||
+				errors.Is(err, services.ErrAllowanceGiftFlagged) {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+			}
+			c.Logger().Errorf("send coin service error: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"error": "failed sending coin",
+			})
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+
+	pending, err := h.transferService.Send(c.Request().Context(), fromUserID, toUser.ID, req.Amount, req.Hold)
+	if err != nil {
+		if ok, resp := transferLimitError(c, err); ok {
+			return resp
+		}
+		if errors.Is(err, services.ErrInvalidTransfer) {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
 		c.Logger().Errorf("send coin service error: %v", err)
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			"error": "failed sending coin",
 		})
 	}
 
+	if pending != nil {
+		return c.JSON(http.StatusAccepted, pending)
+	}
+
 	return c.NoContent(http.StatusOK)
 }
+
+type SendCoinBatchRequest struct {
+	Recipients []models.BatchTransfer `json:"recipients"`
+	// TeamID adds every member of the team, other than the sender and the
+	// listed recipients, with TeamAmount coins each.
+	TeamID     *int `json:"teamId"`
+	TeamAmount int  `json:"teamAmount"`
+}
+
+type SendCoinBatchError struct {
+	Error   string                       `json:"error"`
+	Results []models.BatchTransferResult `json:"results,omitempty"`
+}
+
+func (h *SendCoinHandler) SendCoinBatch(c echo.Context) error {
+	fromUserID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req SendCoinBatchRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if req.TeamID != nil {
+		transfers, err := h.teamService.BatchTransfers(c.Request().Context(), fromUserID, *req.TeamID, req.TeamAmount)
+		if err != nil {
+			return teamError(c, err)
+		}
+
+		listed := make(map[string]bool, len(req.Recipients))
+		for _, t := range req.Recipients {
+			listed[t.ToUser] = true
+		}
+		for _, t := range transfers {
+			if !listed[t.ToUser] {
+				req.Recipients = append(req.Recipients, t)
+			}
+		}
+	}
+
+	result, err := h.transferService.SendBatch(c.Request().Context(), fromUserID, req.Recipients)
+	if err != nil {
+		if ok, resp := transferLimitError(c, err); ok {
+			return resp
+		}
+		if errors.Is(err, services.ErrInvalidBatch) || errors.Is(err, services.ErrBatchInsufficientFunds) {
+			resp := SendCoinBatchError{Error: err.Error()}
+			if result != nil {
+				resp.Results = result.Results
+			}
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+		c.Logger().Errorf("send coin batch error: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "failed sending coin",
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
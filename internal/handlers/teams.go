@@ -0,0 +1,297 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type CreateTeamRequest struct {
+	Name       string `json:"name"`
+	Department string `json:"department"`
+	Budget     int    `json:"budget"`
+}
+
+type SetTeamBudgetRequest struct {
+	Budget int `json:"budget"`
+}
+
+type SetTeamMemberRequest struct {
+	Role string `json:"role"`
+}
+
+type SendToTeamRequest struct {
+	Amount int `json:"amount"`
+	// Mode is split to divide the coins between the members, or pot to put
+	// them into the team pot.
+	Mode string `json:"mode"`
+}
+
+type TeamPayoutRequest struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
+
+type TeamHandler struct {
+	teamService services.TeamService
+}
+
+func NewTeamHandler(teamService services.TeamService) *TeamHandler {
+	return &TeamHandler{teamService: teamService}
+}
+
+func (h *TeamHandler) List(c echo.Context) error {
+	teams, err := h.teamService.List(c.Request().Context())
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, teams)
+}
+
+func (h *TeamHandler) Get(c echo.Context) error {
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	team, err := h.teamService.Get(c.Request().Context(), teamID)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) Create(c echo.Context) error {
+	var req CreateTeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	team, err := h.teamService.Create(c.Request().Context(), req.Name, req.Department, req.Budget)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, team)
+}
+
+func (h *TeamHandler) SetBudget(c echo.Context) error {
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	var req SetTeamBudgetRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	team, err := h.teamService.SetBudget(c.Request().Context(), teamID, req.Budget)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, team)
+}
+
+func (h *TeamHandler) SetMember(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	var req SetTeamMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	member, err := h.teamService.SetMember(c.Request().Context(), userID, teamID, c.Param("username"), req.Role)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, member)
+}
+
+func (h *TeamHandler) RemoveMember(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	if err := h.teamService.RemoveMember(c.Request().Context(), userID, teamID, c.Param("username")); err != nil {
+		return teamError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Send splits coins between the members of the team or puts them into the
+// team pot.
+func (h *TeamHandler) Send(c echo.Context) error {
+	fromUserID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	var req SendToTeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	switch req.Mode {
+	case models.TeamSendSplit:
+		result, err := h.teamService.SendSplit(c.Request().Context(), fromUserID, teamID, req.Amount)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidBatch) || errors.Is(err, services.ErrBatchInsufficientFunds) {
+				resp := SendCoinBatchError{Error: err.Error()}
+				if result != nil {
+					resp.Results = result.Results
+				}
+				return c.JSON(http.StatusBadRequest, resp)
+			}
+			return teamError(c, err)
+		}
+		return c.JSON(http.StatusOK, result)
+	case models.TeamSendPot:
+		team, err := h.teamService.SendToPot(c.Request().Context(), fromUserID, teamID, req.Amount)
+		if err != nil {
+			return teamError(c, err)
+		}
+		return c.JSON(http.StatusOK, team)
+	}
+
+	return c.JSON(http.StatusBadRequest, map[string]string{
+		"error": "mode must be split or pot",
+	})
+}
+
+func (h *TeamHandler) PayOut(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	var req TeamPayoutRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	budget, err := h.teamService.PayOut(c.Request().Context(), userID, teamID, req.ToUser, req.Amount)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, budget)
+}
+
+func (h *TeamHandler) Budget(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	teamID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid team id",
+		})
+	}
+
+	budget, err := h.teamService.Budget(c.Request().Context(), userID, teamID)
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, budget)
+}
+
+func (h *TeamHandler) Leaderboard(c echo.Context) error {
+	standings, err := h.teamService.Leaderboard(c.Request().Context(), c.QueryParam("period"))
+	if err != nil {
+		return teamError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, standings)
+}
+
+func teamError(c echo.Context, err error) error {
+	if ok, resp := transferLimitError(c, err); ok {
+		return resp
+	}
+
+	switch {
+	case errors.Is(err, services.ErrTeamNotFound), errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrNotTeamMember):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTeam), errors.Is(err, services.ErrInvalidTeamTransfer),
+		errors.Is(err, services.ErrNoTeamRecipients), errors.Is(err, services.ErrTeamInsufficientFunds),
+		errors.Is(err, services.ErrTeamPotInsufficient):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrTeamExists), errors.Is(err, services.ErrTeamBudgetExceeded):
+		return c.JSON(http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrTeamForbidden):
+		return c.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("team service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing team",
+	})
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type TenantHandler struct{}
+
+func NewTenantHandler() *TenantHandler {
+	return &TenantHandler{}
+}
+
+// Current describes the store serving the request, so the sign-in page can
+// show its name and currency before the user has a token.
+func (h *TenantHandler) Current(c echo.Context) error {
+	tenant, ok := currentTenant(c)
+	if !ok {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			"error": "tenant not found in context",
+		})
+	}
+
+	return c.JSON(http.StatusOK, tenant)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type TransferLimitHandler struct {
+	transferLimitService services.TransferLimitService
+}
+
+func NewTransferLimitHandler(transferLimitService services.TransferLimitService) *TransferLimitHandler {
+	return &TransferLimitHandler{transferLimitService: transferLimitService}
+}
+
+func (h *TransferLimitHandler) List(c echo.Context) error {
+	limits, err := h.transferLimitService.List(c.Request().Context())
+	if err != nil {
+		return transferLimitOverrideError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, limits)
+}
+
+func (h *TransferLimitHandler) SetForUser(c echo.Context) error {
+	return h.set(c, h.transferLimitService.SetForUser, c.Param("username"))
+}
+
+func (h *TransferLimitHandler) SetForRole(c echo.Context) error {
+	return h.set(c, h.transferLimitService.SetForRole, c.Param("role"))
+}
+
+func (h *TransferLimitHandler) set(c echo.Context,
+	action func(ctx context.Context, target string, override *models.TransferLimitOverride) error, target string) error {
+	var override models.TransferLimitOverride
+	if err := c.Bind(&override); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if err := action(c.Request().Context(), target, &override); err != nil {
+		return transferLimitOverrideError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, override)
+}
+
+func (h *TransferLimitHandler) Delete(c echo.Context) error {
+	overrideID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid override id",
+		})
+	}
+
+	if err := h.transferLimitService.Delete(c.Request().Context(), overrideID); err != nil {
+		return transferLimitOverrideError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func transferLimitOverrideError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrTransferLimitNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidTransferLimit):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("transfer limit service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing transfer limits",
+	})
+}
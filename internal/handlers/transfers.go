@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type TransferSettingsRequest struct {
+	AutoAccept bool `json:"autoAccept"`
+}
+
+type TransferHandler struct {
+	transferService services.TransferService
+}
+
+func NewTransferHandler(transferService services.TransferService) *TransferHandler {
+	return &TransferHandler{transferService: transferService}
+}
+
+func (h *TransferHandler) Pending(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	transfers, err := h.transferService.List(c.Request().Context(), userID)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, transfers)
+}
+
+func (h *TransferHandler) Accept(c echo.Context) error {
+	return h.answer(c, h.transferService.Accept)
+}
+
+func (h *TransferHandler) Decline(c echo.Context) error {
+	return h.answer(c, h.transferService.Decline)
+}
+
+func (h *TransferHandler) answer(
+	c echo.Context, action func(ctx context.Context, userID int, transferID int) (*models.PendingTransfer, error)) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	transferID, ok := pathID(c, "id")
+	if !ok {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "invalid transfer id",
+		})
+	}
+
+	transfer, err := action(c.Request().Context(), userID, transferID)
+	if err != nil {
+		return transferError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, transfer)
+}
+
+func (h *TransferHandler) SetSettings(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req TransferSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "failed to parse body",
+		})
+	}
+
+	if err := h.transferService.SetAutoAccept(c.Request().Context(), userID, req.AutoAccept); err != nil {
+		return transferError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, req)
+}
+
+func transferError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, services.ErrPendingTransferNotFound):
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, services.ErrTransferResolved):
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("transfer service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing transfer",
+	})
+}
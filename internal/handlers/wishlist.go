@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/services"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+type WishlistRequest struct {
+	Item string `json:"item"`
+}
+
+type WishlistHandler struct {
+	wishlistService services.WishlistService
+}
+
+func NewWishlistHandler(wishlistService services.WishlistService) *WishlistHandler {
+	return &WishlistHandler{wishlistService: wishlistService}
+}
+
+func (h *WishlistHandler) List(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	entries, err := h.wishlistService.List(c.Request().Context(), userID)
+	if err != nil {
+		return wishlistError(c, err)
+	}
+	if entries == nil {
+		entries = []models.WishlistEntry{}
+	}
+
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *WishlistHandler) Add(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	var req WishlistRequest
+	if err := c.Bind(&req); err != nil || req.Item == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"error": "item name is required",
+		})
+	}
+
+	if err := h.wishlistService.Add(c.Request().Context(), userID, req.Item); err != nil {
+		return wishlistError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *WishlistHandler) Remove(c echo.Context) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.JSON(http.StatusUnauthorized, map[string]string{
+			"error": "user ID not found in context",
+		})
+	}
+
+	if err := h.wishlistService.Remove(c.Request().Context(), userID, c.Param("item")); err != nil {
+		return wishlistError(c, err)
+	}
+
+	return c.NoContent(http.StatusOK)
+}
+
+func wishlistError(c echo.Context, err error) error {
+	if errors.Is(err, services.ErrItemNotFound) || errors.Is(err, services.ErrWishlistEntryNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+
+	c.Logger().Errorf("wishlist service error: %v", err)
+	return c.JSON(http.StatusInternalServerError, map[string]string{
+		"error": "failed processing wishlist",
+	})
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a periodic background task. It returns the number of records it
+// processed.
+type Job func(ctx context.Context) (int, error)
+
+// Every runs the job on every tick of interval until ctx is cancelled.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			processed, err := job(ctx)
+			if err != nil {
+				log.Printf("job %s failed: %v", name, err)
+			}
+			if processed > 0 {
+				log.Printf("job %s processed %d records", name, processed)
+			}
+		}
+	}
+}
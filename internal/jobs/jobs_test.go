@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery_RunsUntilCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var runs int32
+	done := make(chan struct{})
+	go func() {
+		Every(ctx, "test", time.Millisecond, func(ctx context.Context) (int, error) {
+			if atomic.AddInt32(&runs, 1) == 3 {
+				cancel()
+			}
+			return 0, errors.New("keeps running after errors")
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("job runner did not stop after cancellation")
+	}
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&runs), int32(3))
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"strings"
@@ -49,7 +48,7 @@ func NewAuthMiddleware(userRepo repository.UserRepo, secret string) echo.Middlew
 				}
 				userIDInt := int(userID)
 
-				user, err := userRepo.GetByID(context.Background(), userIDInt)
+				user, err := userRepo.GetByID(c.Request().Context(), userIDInt)
 				if err != nil {
 					return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get user by ID"})
 				}
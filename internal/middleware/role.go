@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// NewRoleMiddleware lets the request through only for users holding one of
+// the given roles. It expects userID to be set by the auth middleware.
+func NewRoleMiddleware(userRepo repository.UserRepo, roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("userID").(int)
+			if !ok {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "user ID not found in context"})
+			}
+
+			role, err := userRepo.GetRole(c.Request().Context(), userID)
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to get user role"})
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					c.Set("userRole", role)
+					return next(c)
+				}
+			}
+
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient permissions"})
+		}
+	}
+}
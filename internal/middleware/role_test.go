@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gratefultolord/merch-store/mocks"
+)
+
+func TestNewRoleMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         interface{}
+		mockSetup      func(m *mocks.UserRepo)
+		expectedStatus int
+		expectedBody   map[string]string
+	}{
+		{
+			name:   "Allowed role",
+			userID: 1,
+			mockSetup: func(m *mocks.UserRepo) {
+				m.On("GetRole", mock.Anything, 1).Return(models.RoleMerchManager, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   nil,
+		},
+		{
+			name:   "Forbidden role",
+			userID: 1,
+			mockSetup: func(m *mocks.UserRepo) {
+				m.On("GetRole", mock.Anything, 1).Return(models.RoleEmployee, nil).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"error": "insufficient permissions"},
+		},
+		{
+			name:           "Missing user ID",
+			userID:         nil,
+			mockSetup:      func(m *mocks.UserRepo) {},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   map[string]string{"error": "user ID not found in context"},
+		},
+		{
+			name:   "Database error during GetRole",
+			userID: 1,
+			mockSetup: func(m *mocks.UserRepo) {
+				m.On("GetRole", mock.Anything, 1).Return("", errors.New("database error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   map[string]string{"error": "failed to get user role"},
+		},
+	}
+
+	e := echo.New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepo)
+			tt.mockSetup(mockUserRepo)
+
+			roleMiddleware := NewRoleMiddleware(mockUserRepo, models.RoleMerchManager, models.RoleAdmin)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/returns", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tt.userID != nil {
+				c.Set("userID", tt.userID)
+			}
+
+			err := roleMiddleware(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedBody != nil {
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("failed to unmarshal response body: %v", err)
+				}
+				assert.Equal(t, tt.expectedBody, body)
+			}
+
+			mockUserRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+// NewTenantMiddleware resolves the tenant of the request from the host
+// header and the tenant claim of the bearer token, and scopes the request
+// context to it. A request naming neither belongs to the default tenant.
+// It only reads the token; checking it is left to the auth middleware.
+func NewTenantMiddleware(tenantRepo repository.TenantRepo, secret string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			tenant, err := tenantRepo.GetByHost(ctx, hostname(c.Request().Host))
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve tenant"})
+			}
+
+			if claimID, ok := tokenTenant(c.Request().Header.Get("Authorization"), secret); ok {
+				if tenant != nil && tenant.ID != claimID {
+					return c.JSON(http.StatusForbidden, map[string]string{"error": "token belongs to another tenant"})
+				}
+				if tenant == nil {
+					tenant, err = tenantRepo.GetByID(ctx, claimID)
+					if err != nil {
+						return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve tenant"})
+					}
+					if tenant == nil {
+						return c.JSON(http.StatusUnauthorized, map[string]string{"error": "unknown tenant"})
+					}
+				}
+			}
+
+			if tenant == nil {
+				tenant, err = tenantRepo.GetByID(ctx, models.DefaultTenantID)
+				if err != nil || tenant == nil {
+					return c.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to resolve tenant"})
+				}
+			}
+
+			c.Set("tenant", tenant)
+			c.SetRequest(c.Request().WithContext(repository.WithTenant(ctx, tenant.ID)))
+
+			return next(c)
+		}
+	}
+}
+
+// hostname strips the port from the host header.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// tokenTenant returns the tenant claim of a valid bearer token.
+func tokenTenant(authHeader string, secret string) (int, bool) {
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return 0, false
+	}
+
+	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return 0, false
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return 0, false
+	}
+	tenantID, ok := claims["tenant"].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(tenantID), true
+}
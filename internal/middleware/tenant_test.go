@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/gratefultolord/merch-store/mocks"
+)
+
+func TestNewTenantMiddleware(t *testing.T) {
+	defaultTenant := &models.Tenant{ID: models.DefaultTenantID, Slug: "default"}
+	outlet := &models.Tenant{ID: 2, Slug: "outlet"}
+
+	tests := []struct {
+		name           string
+		host           string
+		tokenTenant    *int
+		mockSetup      func(m *mocks.TenantRepo)
+		expectedStatus int
+		expectedTenant int
+		expectedBody   map[string]string
+	}{
+		{
+			name: "Default tenant",
+			host: "localhost:8080",
+			mockSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByHost", mock.Anything, "localhost").Return(nil, nil).Once()
+				m.On("GetByID", mock.Anything, models.DefaultTenantID).Return(defaultTenant, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedTenant: models.DefaultTenantID,
+		},
+		{
+			name: "Tenant from host",
+			host: "Outlet.Example.com",
+			mockSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByHost", mock.Anything, "outlet.example.com").Return(outlet, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedTenant: 2,
+		},
+		{
+			name:        "Tenant from token",
+			host:        "localhost",
+			tokenTenant: intPtr(2),
+			mockSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByHost", mock.Anything, "localhost").Return(nil, nil).Once()
+				m.On("GetByID", mock.Anything, 2).Return(outlet, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedTenant: 2,
+		},
+		{
+			name:        "Token of another tenant than the host",
+			host:        "outlet.example.com",
+			tokenTenant: intPtr(models.DefaultTenantID),
+			mockSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByHost", mock.Anything, "outlet.example.com").Return(outlet, nil).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   map[string]string{"error": "token belongs to another tenant"},
+		},
+		{
+			name:        "Unknown tenant in token",
+			host:        "localhost",
+			tokenTenant: intPtr(9),
+			mockSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByHost", mock.Anything, "localhost").Return(nil, nil).Once()
+				m.On("GetByID", mock.Anything, 9).Return(nil, nil).Once()
+			},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   map[string]string{"error": "unknown tenant"},
+		},
+	}
+
+	secret := "your-secret-key"
+	e := echo.New()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTenantRepo := new(mocks.TenantRepo)
+			tt.mockSetup(mockTenantRepo)
+
+			tenantMiddleware := NewTenantMiddleware(mockTenantRepo, secret)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/info", nil)
+			req.Host = tt.host
+			if tt.tokenTenant != nil {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": 1, "tenant": *tt.tokenTenant})
+				tokenString, err := token.SignedString([]byte(secret))
+				if err != nil {
+					t.Fatalf("failed to sign token: %v", err)
+				}
+				req.Header.Set("Authorization", "Bearer "+tokenString)
+			}
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			nextHandler := func(c echo.Context) error {
+				return c.String(http.StatusOK, strconv.Itoa(repository.TenantFromContext(c.Request().Context())))
+			}
+
+			err := tenantMiddleware(nextHandler)(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedBody == nil {
+				assert.Equal(t, strconv.Itoa(tt.expectedTenant), rec.Body.String())
+			} else {
+				var body map[string]string
+				if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+					t.Fatalf("failed to unmarshal response body: %v", err)
+				}
+				assert.Equal(t, tt.expectedBody, body)
+			}
+
+			mockTenantRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
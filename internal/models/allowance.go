@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Allowance is the giving wallet: coins a user can only give away. It is
+// refilled every month and whatever is left expires at ExpiresAt.
+type Allowance struct {
+	Amount    int       `json:"amount"`
+	Monthly   int       `json:"monthly"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	AuctionStatusOpen   = "open"
+	AuctionStatusClosed = "closed"
+)
+
+const (
+	BidStatusActive = "active"
+	BidStatusOutbid = "outbid"
+	BidStatusWon    = "won"
+)
+
+type Auction struct {
+	ID              int        `db:"id" json:"id"`
+	ItemID          int        `db:"item_id" json:"-"`
+	VariantID       int        `db:"variant_id" json:"-"`
+	Item            string     `db:"item" json:"item"`
+	Size            string     `db:"size" json:"size,omitempty"`
+	Color           string     `db:"color" json:"color,omitempty"`
+	StartPrice      int        `db:"start_price" json:"startPrice"`
+	Increment       int        `db:"increment" json:"increment"`
+	ExtendBySeconds int        `db:"extend_by_seconds" json:"extendBySeconds"`
+	EndsAt          time.Time  `db:"ends_at" json:"endsAt"`
+	Status          string     `db:"status" json:"status"`
+	HighestBid      *int       `db:"highest_bid" json:"highestBid,omitempty"`
+	HighestBidderID *int       `db:"highest_bidder_id" json:"-"`
+	WinnerID        *int       `db:"winner_id" json:"-"`
+	OrderID         *int       `db:"order_id" json:"-"`
+	CreatedBy       *int       `db:"created_by" json:"-"`
+	CreatedAt       time.Time  `db:"created_at" json:"createdAt"`
+	ClosedAt        *time.Time `db:"closed_at" json:"closedAt,omitempty"`
+}
+
+// MinimumBid returns the lowest amount the next bid may have.
+func (a Auction) MinimumBid() int {
+	if a.HighestBid == nil {
+		return a.StartPrice
+	}
+	return *a.HighestBid + a.Increment
+}
+
+// Extend pushes the end time back when a bid lands in the final
+// ExtendBySeconds of the auction, so that last-second bids can be answered.
+func (a *Auction) Extend(now time.Time) bool {
+	window := time.Duration(a.ExtendBySeconds) * time.Second
+	if window <= 0 || a.EndsAt.Sub(now) >= window {
+		return false
+	}
+	a.EndsAt = now.Add(window)
+	return true
+}
+
+type Bid struct {
+	ID        int       `db:"id" json:"id"`
+	AuctionID int       `db:"auction_id" json:"auctionId"`
+	UserID    int       `db:"user_id" json:"-"`
+	Amount    int       `db:"amount" json:"amount"`
+	Status    string    `db:"status" json:"status"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditActionLogin             = "auth.login"
+	AuditActionLoginFailed       = "auth.login_failed"
+	AuditActionSignup            = "auth.signup"
+	AuditActionRoleChanged       = "user.role_changed"
+	AuditActionItemArchived      = "catalog.item_archived"
+	AuditActionItemUnarchived    = "catalog.item_unarchived"
+	AuditActionStockChanged      = "catalog.stock_changed"
+	AuditActionPreorderChanged   = "catalog.preorder_changed"
+	AuditActionPriceRuleCreated  = "catalog.price_rule_created"
+	AuditActionPriceRuleDeleted  = "catalog.price_rule_deleted"
+	AuditActionCoinsIssued       = "balance.issued"
+	AuditActionBalanceAdjusted   = "balance.adjusted"
+	AuditActionGrantPaid         = "balance.grant_paid"
+	AuditActionReversalRequested = "reversal.requested"
+	AuditActionReversalCompleted = "reversal.completed"
+	AuditActionReversalRejected  = "reversal.rejected"
+)
+
+// Types of the objects an audit entry is about.
+const (
+	AuditTargetUser        = "user"
+	AuditTargetItem        = "item"
+	AuditTargetVariant     = "item_variant"
+	AuditTargetPriceRule   = "price_rule"
+	AuditTargetGrant       = "grant"
+	AuditTargetTransaction = "transaction"
+)
+
+// AuditEntry is one record of the append-only audit log. Every entry holds
+// the hash of the entry before it, so editing or removing an entry breaks
+// the chain. ActorName is kept for actors without an account, such as a
+// failed login under an unknown username.
+type AuditEntry struct {
+	ID         int             `db:"id" json:"id"`
+	Action     string          `db:"action" json:"action"`
+	ActorID    *int            `db:"actor_id" json:"-"`
+	ActorName  *string         `db:"actor_name" json:"-"`
+	Actor      *string         `db:"actor" json:"actor,omitempty"`
+	TargetType string          `db:"target_type" json:"targetType,omitempty"`
+	TargetID   string          `db:"target_id" json:"targetId,omitempty"`
+	Before     json.RawMessage `db:"before" json:"before,omitempty"`
+	After      json.RawMessage `db:"after" json:"after,omitempty"`
+	RequestID  string          `db:"request_id" json:"requestId,omitempty"`
+	PrevHash   string          `db:"prev_hash" json:"prevHash"`
+	Hash       string          `db:"hash" json:"hash"`
+	CreatedAt  time.Time       `db:"created_at" json:"createdAt"`
+}
+
+// ComputeHash hashes the entry's content together with the previous hash.
+func (e *AuditEntry) ComputeHash() string {
+	actorID := ""
+	if e.ActorID != nil {
+		actorID = strconv.Itoa(*e.ActorID)
+	}
+	actorName := ""
+	if e.ActorName != nil {
+		actorName = *e.ActorName
+	}
+
+	content := strings.Join([]string{
+		e.PrevHash, e.Action, actorID, actorName, e.TargetType, e.TargetID,
+		string(e.Before), string(e.After), e.RequestID, e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	}, "\x1f")
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditState encodes the before or after state of an audit entry.
+func AuditState(state any) json.RawMessage {
+	if state == nil {
+		return nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// AuditFilter narrows the audit log query. Empty fields match everything.
+type AuditFilter struct {
+	Action     string
+	Actor      string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   int
+	Limit      int
+}
+
+// AuditVerification is the result of walking the hash chain. BrokenAt is
+// the first entry whose hashes do not match.
+type AuditVerification struct {
+	Valid    bool `json:"valid"`
+	Checked  int  `json:"checked"`
+	BrokenAt *int `json:"brokenAt,omitempty"`
+}
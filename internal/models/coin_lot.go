@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// ForfeitureAccountID is the system account expired coins are moved to.
+const ForfeitureAccountID = -3
+
+// Coin sources say how the coins of a lot were earned.
+const (
+	CoinSourceOpening     = "opening"
+	CoinSourceSignup      = TransactionTypeSignup
+	CoinSourceTransfer    = "transfer"
+	CoinSourceRefund      = "refund"
+	CoinSourceRafflePrize = "raffle_prize"
+	CoinSourceAllowance   = "allowance"
+	CoinSourceIssuance    = TransactionTypeIssuance
+	CoinSourceGrant       = TransactionTypeGrant
+	CoinSourceAdjustment  = TransactionTypeAdjustment
+	CoinSourceReversal    = TransactionTypeReversal
+	CoinSourceTeamPayout  = TransactionTypeTeamPayout
+	// CoinSourceRelease is used when held coins, such as a bid, a preorder
+	// or an escrowed transfer, go back to the user and the lots they were
+	// taken from are not known.
+	CoinSourceRelease = "release"
+)
+
+// Coin hold types say what coins taken by a hold are kept for. Auction
+// holds are keyed by the auction, every other hold by its own row.
+const (
+	CoinHoldAuction          = "auction"
+	CoinHoldPreorder         = "preorder"
+	CoinHoldPendingTransfer  = "pending_transfer"
+	CoinHoldTransferBatch    = "transfer_batch"
+	CoinHoldPoolContribution = "pool_contribution"
+	CoinHoldOrder            = "order"
+)
+
+// CoinHold names the hold coins were taken for, so that releasing it puts
+// them back into the lots they came from.
+type CoinHold struct {
+	Type string
+	ID   int
+}
+
+// CoinLotHold is the part of a lot taken by a hold.
+type CoinLotHold struct {
+	ID     int `db:"id"`
+	LotID  int `db:"lot_id"`
+	Amount int `db:"amount"`
+}
+
+// CoinLot is a batch of coins earned at the same time. Coins are spent
+// from the oldest lot first and expire at ExpiresAt.
+type CoinLot struct {
+	ID        int       `db:"id" json:"-"`
+	UserID    int       `db:"user_id" json:"-"`
+	Amount    int       `db:"amount" json:"-"`
+	Remaining int       `db:"remaining" json:"amount"`
+	Source    string    `db:"source" json:"source"`
+	EarnedAt  time.Time `db:"earned_at" json:"earnedAt"`
+	ExpiresAt time.Time `db:"expires_at" json:"expiresAt"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	CoinRequestStatusPending  = "pending"
+	CoinRequestStatusAccepted = "accepted"
+	CoinRequestStatusDeclined = "declined"
+	CoinRequestStatusExpired  = "expired"
+)
+
+// CoinRequest is a request from one user asking another to send coins.
+type CoinRequest struct {
+	ID          int        `db:"id" json:"id"`
+	RequesterID int        `db:"requester_id" json:"-"`
+	Requester   string     `db:"requester" json:"fromUser"`
+	PayerID     int        `db:"payer_id" json:"-"`
+	Payer       string     `db:"payer" json:"toUser"`
+	Amount      int        `db:"amount" json:"amount"`
+	Reason      string     `db:"reason" json:"reason"`
+	Status      string     `db:"status" json:"status"`
+	ExpiresAt   time.Time  `db:"expires_at" json:"expiresAt"`
+	CreatedAt   time.Time  `db:"created_at" json:"createdAt"`
+	ResolvedAt  *time.Time `db:"resolved_at" json:"resolvedAt,omitempty"`
+}
+
+type CoinRequests struct {
+	Incoming []CoinRequest `json:"incoming"`
+	Outgoing []CoinRequest `json:"outgoing"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Rules that flag suspicious coin flows.
+const (
+	FraudRuleCircularFlow      = "circular_flow"
+	FraudRuleNewAccountForward = "new_account_forward"
+	FraudRuleVolumeSpike       = "volume_spike"
+)
+
+const (
+	FraudFlagStatusOpen      = "open"
+	FraudFlagStatusConfirmed = "confirmed"
+	FraudFlagStatusDismissed = "dismissed"
+)
+
+// FraudFlag is a suspicious coin flow waiting in the admin review queue.
+// Flags raised while sending a transfer point to the transfer held for the
+// review.
+type FraudFlag struct {
+	ID                int        `db:"id" json:"id"`
+	Rule              string     `db:"rule" json:"rule"`
+	UserID            int        `db:"user_id" json:"-"`
+	Username          string     `db:"username" json:"user"`
+	CounterpartyID    *int       `db:"counterparty_id" json:"-"`
+	Counterparty      *string    `db:"counterparty" json:"counterparty,omitempty"`
+	Amount            int        `db:"amount" json:"amount"`
+	Details           string     `db:"details" json:"details"`
+	PendingTransferID *int       `db:"pending_transfer_id" json:"pendingTransferId,omitempty"`
+	Status            string     `db:"status" json:"status"`
+	ReviewedBy        *int       `db:"reviewed_by" json:"-"`
+	CreatedAt         time.Time  `db:"created_at" json:"createdAt"`
+	ReviewedAt        *time.Time `db:"reviewed_at" json:"reviewedAt,omitempty"`
+}
+
+// SenderActivity sums the recent transfers around a new transfer, to screen
+// it before the coins move.
+type SenderActivity struct {
+	CreatedAt            time.Time `db:"created_at"`
+	SentSinceSignup      int       `db:"sent_since_signup"`
+	ReceivedFromReceiver int       `db:"received_from_receiver"`
+	SentLastDay          int       `db:"sent_last_day"`
+	SentBefore           int       `db:"sent_before"`
+}
@@ -1,18 +1,27 @@
 package models
 
 type InfoResponse struct {
-	Coins       int                         `json:"coins"`
-	Inventory   []UserInventoryItemResponse `json:"inventory"`
-	CoinHistory CoinHistory                 `json:"history"`
+	Coins         int                         `json:"coins"`
+	Currency      string                      `json:"currency,omitempty"`
+	Reserved      int                         `json:"reserved"`
+	Allowance     Allowance                   `json:"allowance"`
+	ExpiringSoon  []CoinLot                   `json:"expiringSoon,omitempty"`
+	Inventory     []UserInventoryItemResponse `json:"inventory"`
+	CoinHistory   CoinHistory                 `json:"history"`
+	Notifications []Notification              `json:"notifications,omitempty"`
+	WishlistHints []string                    `json:"wishlistHints,omitempty"`
 }
 
 type UserInventoryItem struct {
-	Type     Item `json:"type"`
-	Quantity int  `json:"quantity"`
+	Type     Item        `json:"type"`
+	Variant  ItemVariant `json:"variant"`
+	Quantity int         `json:"quantity"`
 }
 
 type UserInventoryItemResponse struct {
 	Type     string `json:"type"`
+	Size     string `json:"size,omitempty"`
+	Color    string `json:"color,omitempty"`
 	Quantity int    `json:"quantity"`
 }
 
@@ -22,7 +31,15 @@ type CoinHistory struct {
 }
 
 type TransactionSummary struct {
+	ID       int    `json:"id"`
 	FromUser string `json:"fromUser,omitempty"`
 	ToUser   string `json:"toUser,omitempty"`
 	Amount   int    `json:"amount"`
+	Type     string `json:"type,omitempty"`
+	Item     string `json:"item,omitempty"`
+	Quantity int    `json:"quantity,omitempty"`
+	BatchID  *int   `json:"batchId,omitempty"`
+	// ReversalOf and ReversedBy link a transaction and its reversal.
+	ReversalOf *int `json:"reversalOf,omitempty"`
+	ReversedBy *int `json:"reversedBy,omitempty"`
 }
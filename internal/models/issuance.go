@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// IssuanceAccountID is the system account new coins are issued from, so
+// every coin that enters circulation has a ledger entry.
+const IssuanceAccountID = -2
+
+// Issuance kinds match the type of the transactions they create.
+const (
+	IssuanceKindIssuance   = TransactionTypeIssuance
+	IssuanceKindGrant      = TransactionTypeGrant
+	IssuanceKindAdjustment = TransactionTypeAdjustment
+	IssuanceKindAllowance  = TransactionTypeAllowance
+	IssuanceKindSignup     = TransactionTypeSignup
+)
+
+// Issuance records who issued or withdrew coins and why. Grants set Period
+// to the month they were paid for, allowance gifts to the month of the
+// allowance they were given from, in YYYY-MM form.
+type Issuance struct {
+	ID         int       `db:"id" json:"id"`
+	Kind       string    `db:"kind" json:"kind"`
+	Reason     string    `db:"reason" json:"reason"`
+	AdminID    *int      `db:"admin_id" json:"-"`
+	Admin      string    `db:"admin" json:"admin"`
+	Period     *string   `db:"period" json:"period,omitempty"`
+	Total      int       `db:"total" json:"total"`
+	Recipients int       `db:"recipients" json:"recipients"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
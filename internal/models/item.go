@@ -1,7 +1,36 @@
 package models
 
+import "time"
+
 type Item struct {
-	ID    int    `db:"id"`
-	Name  string `db:"name"`
-	Price int    `db:"price"`
+	ID              int        `db:"id"`
+	Name            string     `db:"name"`
+	Price           int        `db:"price"`
+	Category        string     `db:"category"`
+	PreorderEnabled bool       `db:"preorder_enabled"`
+	ArchivedAt      *time.Time `db:"archived_at"`
+}
+
+type ItemVariant struct {
+	ID     int    `db:"id"`
+	ItemID int    `db:"item_id"`
+	Size   string `db:"size"`
+	Color  string `db:"color"`
+	Price  *int   `db:"price"`
+	Stock  *int   `db:"stock"`
+}
+
+// UnitPrice returns the variant price override if set, otherwise the item price.
+func (v ItemVariant) UnitPrice(item Item) int {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return item.Price
+}
+
+type BuyOptions struct {
+	Size      string
+	Color     string
+	Quantity  int
+	PromoCode string
 }
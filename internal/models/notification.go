@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	NotificationTypeGiftReceived      = "gift_received"
+	NotificationTypeItemReceived      = "item_received"
+	NotificationTypeOrderStatus       = "order_status"
+	NotificationTypeRestock           = "restock"
+	NotificationTypePreorderFulfilled = "preorder_fulfilled"
+	NotificationTypeOutbid            = "outbid"
+	NotificationTypeAuctionWon        = "auction_won"
+	NotificationTypeRaffleWon         = "raffle_won"
+	NotificationTypePoolFunded        = "pool_funded"
+	NotificationTypePoolExpired       = "pool_expired"
+	NotificationTypeCoinRequest       = "coin_request"
+	NotificationTypeCoinRequestAnswer = "coin_request_answer"
+	NotificationTypeTransferPending   = "transfer_pending"
+	NotificationTypeTransferReturned  = "transfer_returned"
+	NotificationTypeScheduleFailed    = "scheduled_transfer_failed"
+	NotificationTypeCoinsIssued       = "coins_issued"
+	NotificationTypeCoinsExpired      = "coins_expired"
+	NotificationTypeTransferHeld      = "transfer_held"
+	NotificationTypeTransferReviewed  = "transfer_reviewed"
+	NotificationTypeTransferReversed  = "transfer_reversed"
+	NotificationTypeTeamPayout        = "team_payout"
+)
+
+type Notification struct {
+	ID        int       `db:"id" json:"id"`
+	UserID    int       `db:"user_id" json:"-"`
+	Type      string    `db:"type" json:"type"`
+	Message   string    `db:"message" json:"message"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
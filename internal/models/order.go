@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+const (
+	OrderStatusPlaced         = "placed"
+	OrderStatusApproved       = "approved"
+	OrderStatusPacked         = "packed"
+	OrderStatusReadyForPickup = "ready_for_pickup"
+	OrderStatusShipped        = "shipped"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+)
+
+const (
+	DeliveryMethodPickup   = "pickup"
+	DeliveryMethodShipping = "shipping"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+var orderTransitions = map[string][]string{
+	OrderStatusPlaced:         {OrderStatusApproved, OrderStatusCancelled},
+	OrderStatusApproved:       {OrderStatusPacked, OrderStatusCancelled},
+	OrderStatusPacked:         {OrderStatusReadyForPickup, OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusReadyForPickup: {OrderStatusDelivered},
+	OrderStatusShipped:        {OrderStatusDelivered},
+}
+
+type Order struct {
+	ID               int       `db:"id" json:"id"`
+	UserID           int       `db:"user_id" json:"userId"`
+	RecipientID      int       `db:"recipient_id" json:"recipientId"`
+	ItemID           int       `db:"item_id" json:"itemId"`
+	VariantID        int       `db:"variant_id" json:"variantId"`
+	TransactionID    int       `db:"transaction_id" json:"-"`
+	Quantity         int       `db:"quantity" json:"quantity"`
+	RefundedQuantity int       `db:"refunded_quantity" json:"refundedQuantity"`
+	UnitPrice        int       `db:"unit_price" json:"unitPrice"`
+	ListTotal        int       `db:"list_total" json:"listTotal"`
+	Discount         int       `db:"discount" json:"discount"`
+	Total            int       `db:"total" json:"total"`
+	PromoCodeID      *int      `db:"promo_code_id" json:"promoCodeId,omitempty"`
+	Message          string    `db:"message" json:"message,omitempty"`
+	Status           string    `db:"status" json:"status"`
+	DeliveryMethod   string    `db:"delivery_method" json:"deliveryMethod"`
+	Office           string    `db:"office" json:"office,omitempty"`
+	ShippingAddress  string    `db:"shipping_address" json:"shippingAddress,omitempty"`
+	CreatedAt        time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func IsValidOrderStatus(status string) bool {
+	switch status {
+	case OrderStatusPlaced, OrderStatusApproved, OrderStatusPacked, OrderStatusReadyForPickup,
+		OrderStatusShipped, OrderStatusDelivered, OrderStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// RefundFor returns the paid amount attributable to the next quantity units
+// to be refunded. Discounts are spread across units so that refunding the
+// whole order gives back exactly what was paid.
+func (o Order) RefundFor(quantity int) int {
+	if o.Quantity == 0 {
+		return 0
+	}
+	return o.Total*(o.RefundedQuantity+quantity)/o.Quantity - o.Total*o.RefundedQuantity/o.Quantity
+}
+
+// IsGift reports whether the order was bought for someone else.
+func (o Order) IsGift() bool {
+	return o.RecipientID != o.UserID
+}
+
+// CoinHold returns the hold of the coins paid for the order, which a refund
+// gives back.
+func (o Order) CoinHold() CoinHold {
+	return CoinHold{Type: CoinHoldOrder, ID: o.ID}
+}
+
+// CanTransitionTo reports whether the order may move to the given status.
+func (o Order) CanTransitionTo(status string) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+type Delivery struct {
+	Method          string `json:"method"`
+	Office          string `json:"office"`
+	ShippingAddress string `json:"shippingAddress"`
+}
+
+type PickListEntry struct {
+	Office          string `db:"office"`
+	OrderID         int    `db:"order_id"`
+	Username        string `db:"username"`
+	Item            string `db:"item"`
+	Size            string `db:"size"`
+	Color           string `db:"color"`
+	Quantity        int    `db:"quantity"`
+	ShippingAddress string `db:"shipping_address"`
+}
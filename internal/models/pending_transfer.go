@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+const (
+	PendingTransferStatusPending  = "pending"
+	PendingTransferStatusAccepted = "accepted"
+	PendingTransferStatusDeclined = "declined"
+	PendingTransferStatusReturned = "returned"
+	PendingTransferStatusReview   = "review"
+	PendingTransferStatusRejected = "rejected"
+)
+
+// PendingTransfer is a coin transfer held in escrow until the receiver
+// accepts it. Declined and timed out transfers go back to the sender.
+// Transfers flagged as suspicious wait in review until an admin releases or
+// rejects them.
+type PendingTransfer struct {
+	ID            int        `db:"id" json:"id"`
+	SenderID      int        `db:"sender_id" json:"-"`
+	Sender        string     `db:"sender" json:"fromUser"`
+	ReceiverID    int        `db:"receiver_id" json:"-"`
+	Receiver      string     `db:"receiver" json:"toUser"`
+	Amount        int        `db:"amount" json:"amount"`
+	Status        string     `db:"status" json:"status"`
+	ExpiresAt     time.Time  `db:"expires_at" json:"expiresAt"`
+	TransactionID *int       `db:"transaction_id" json:"-"`
+	BatchID       *int       `db:"batch_id" json:"batchId,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	ResolvedAt    *time.Time `db:"resolved_at" json:"resolvedAt,omitempty"`
+}
+
+// CoinHold returns the hold of the escrowed coins. Transfers sent in a
+// batch share the hold of the batch, which took the coins for all of them.
+func (t PendingTransfer) CoinHold() CoinHold {
+	if t.BatchID != nil {
+		return CoinHold{Type: CoinHoldTransferBatch, ID: *t.BatchID}
+	}
+	return CoinHold{Type: CoinHoldPendingTransfer, ID: t.ID}
+}
+
+type PendingTransfers struct {
+	Incoming []PendingTransfer `json:"incoming"`
+	Outgoing []PendingTransfer `json:"outgoing"`
+}
@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+const (
+	PoolStatusOpen    = "open"
+	PoolStatusFunded  = "funded"
+	PoolStatusExpired = "expired"
+)
+
+const (
+	ContributionStatusHeld     = "held"
+	ContributionStatusSpent    = "spent"
+	ContributionStatusRefunded = "refunded"
+)
+
+// Pool collects coins from several users towards one unit of an item. The
+// unit is delivered to the pool creator once Raised reaches Target.
+type Pool struct {
+	ID        int        `db:"id" json:"id"`
+	Title     string     `db:"title" json:"title"`
+	ItemID    int        `db:"item_id" json:"-"`
+	VariantID int        `db:"variant_id" json:"-"`
+	Item      string     `db:"item" json:"item"`
+	Size      string     `db:"size" json:"size,omitempty"`
+	Color     string     `db:"color" json:"color,omitempty"`
+	Target    int        `db:"target" json:"target"`
+	Raised    int        `db:"raised" json:"raised"`
+	Deadline  time.Time  `db:"deadline" json:"deadline"`
+	Status    string     `db:"status" json:"status"`
+	CreatorID int        `db:"creator_id" json:"-"`
+	Creator   string     `db:"creator" json:"creator"`
+	OrderID   *int       `db:"order_id" json:"-"`
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+	ClosedAt  *time.Time `db:"closed_at" json:"closedAt,omitempty"`
+}
+
+// Remaining returns the coins still needed to reach the target.
+func (p Pool) Remaining() int {
+	return p.Target - p.Raised
+}
+
+type PoolContribution struct {
+	ID            int       `db:"id" json:"id"`
+	PoolID        int       `db:"pool_id" json:"poolId"`
+	UserID        int       `db:"user_id" json:"-"`
+	Amount        int       `db:"amount" json:"amount"`
+	Status        string    `db:"status" json:"status"`
+	TransactionID *int      `db:"transaction_id" json:"-"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// CoinHold returns the hold of the contributed coins. It outlives the
+// contribution being spent, so a refund of the pool order gives the
+// contributors back the coins they put in.
+func (c PoolContribution) CoinHold() CoinHold {
+	return CoinHold{Type: CoinHoldPoolContribution, ID: c.ID}
+}
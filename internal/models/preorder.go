@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	PreorderStatusPlaced    = "placed"
+	PreorderStatusFulfilled = "fulfilled"
+	PreorderStatusCancelled = "cancelled"
+)
+
+// Preorder holds coins in escrow until the item becomes available. The held
+// amount has already been taken from the user's balance.
+type Preorder struct {
+	ID            int        `db:"id" json:"id"`
+	UserID        int        `db:"user_id" json:"-"`
+	ItemID        int        `db:"item_id" json:"-"`
+	VariantID     int        `db:"variant_id" json:"-"`
+	Item          string     `db:"item" json:"item"`
+	Size          string     `db:"size" json:"size,omitempty"`
+	Color         string     `db:"color" json:"color,omitempty"`
+	Quantity      int        `db:"quantity" json:"quantity"`
+	OriginalPrice int        `db:"original_price" json:"-"`
+	UnitPrice     int        `db:"unit_price" json:"unitPrice"`
+	Amount        int        `db:"amount" json:"amount"`
+	Status        string     `db:"status" json:"status"`
+	OrderID       *int       `db:"order_id" json:"orderId,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	ResolvedAt    *time.Time `db:"resolved_at" json:"resolvedAt,omitempty"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// PriceRule is a time-boxed price change for a single item or a whole
+// category. It either sets a fixed unit price or takes a percentage off.
+type PriceRule struct {
+	ID              int       `db:"id" json:"id"`
+	ItemID          *int      `db:"item_id" json:"itemId,omitempty"`
+	Category        *string   `db:"category" json:"category,omitempty"`
+	Price           *int      `db:"price" json:"price,omitempty"`
+	DiscountPercent *int      `db:"discount_percent" json:"discountPercent,omitempty"`
+	StartsAt        time.Time `db:"starts_at" json:"startsAt"`
+	EndsAt          time.Time `db:"ends_at" json:"endsAt"`
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
+}
+
+func (r PriceRule) AppliesTo(item Item) bool {
+	if r.ItemID != nil {
+		return *r.ItemID == item.ID
+	}
+	return r.Category != nil && *r.Category == item.Category
+}
+
+// Apply returns the price after the rule. A rule never raises the price.
+func (r PriceRule) Apply(price int) int {
+	sale := price
+	if r.Price != nil {
+		sale = *r.Price
+	} else if r.DiscountPercent != nil {
+		sale = price * (100 - *r.DiscountPercent) / 100
+	}
+	if sale > price {
+		return price
+	}
+	return sale
+}
+
+// SalePrice picks the lowest price produced by the active rules that apply to
+// the item. It returns the original price and a nil rule when nothing applies.
+func SalePrice(item Item, price int, rules []PriceRule) (int, *PriceRule) {
+	best := price
+	var applied *PriceRule
+	for i := range rules {
+		if !rules[i].AppliesTo(item) {
+			continue
+		}
+		if sale := rules[i].Apply(price); sale < best {
+			best = sale
+			applied = &rules[i]
+		}
+	}
+	return best, applied
+}
+
+type PriceHistory struct {
+	ID            int       `db:"id" json:"id"`
+	OrderID       int       `db:"order_id" json:"orderId"`
+	UserID        int       `db:"user_id" json:"userId"`
+	ItemID        int       `db:"item_id" json:"itemId"`
+	VariantID     int       `db:"variant_id" json:"variantId"`
+	OriginalPrice int       `db:"original_price" json:"originalPrice"`
+	SalePrice     int       `db:"sale_price" json:"salePrice"`
+	PriceRuleID   *int      `db:"price_rule_id" json:"priceRuleId,omitempty"`
+	PromoDiscount int       `db:"promo_discount" json:"promoDiscount"`
+	Charged       int       `db:"charged" json:"charged"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+type CatalogItem struct {
+	Name          string           `json:"name"`
+	Category      string           `json:"category,omitempty"`
+	Price         int              `json:"price"`
+	OriginalPrice int              `json:"originalPrice"`
+	SaleEndsAt    *time.Time       `json:"saleEndsAt,omitempty"`
+	Variants      []CatalogVariant `json:"variants"`
+}
+
+type CatalogVariant struct {
+	Size          string     `json:"size,omitempty"`
+	Color         string     `json:"color,omitempty"`
+	Price         int        `json:"price"`
+	OriginalPrice int        `json:"originalPrice"`
+	SaleEndsAt    *time.Time `json:"saleEndsAt,omitempty"`
+	Stock         *int       `json:"stock,omitempty"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	DiscountTypePercent = "percent"
+	DiscountTypeFixed   = "fixed"
+)
+
+type PromoCode struct {
+	ID            int            `db:"id" json:"id"`
+	Code          string         `db:"code" json:"code"`
+	DiscountType  string         `db:"discount_type" json:"discountType"`
+	DiscountValue int            `db:"discount_value" json:"discountValue"`
+	ItemIDs       pq.Int64Array  `db:"item_ids" json:"itemIds"`
+	Categories    pq.StringArray `db:"categories" json:"categories"`
+	MaxUses       *int           `db:"max_uses" json:"maxUses,omitempty"`
+	PerUserLimit  *int           `db:"per_user_limit" json:"perUserLimit,omitempty"`
+	UsedCount     int            `db:"used_count" json:"usedCount"`
+	StartsAt      *time.Time     `db:"starts_at" json:"startsAt,omitempty"`
+	EndsAt        *time.Time     `db:"ends_at" json:"endsAt,omitempty"`
+	CreatedAt     time.Time      `db:"created_at" json:"createdAt"`
+}
+
+// IsActive reports whether the code is inside its validity window.
+func (p PromoCode) IsActive(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// AppliesTo reports whether the item is covered by the code. A code without
+// an item or category whitelist applies to the whole catalog.
+func (p PromoCode) AppliesTo(item Item) bool {
+	if len(p.ItemIDs) == 0 && len(p.Categories) == 0 {
+		return true
+	}
+	for _, id := range p.ItemIDs {
+		if int(id) == item.ID {
+			return true
+		}
+	}
+	for _, category := range p.Categories {
+		if category == item.Category {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns the number of coins taken off the given total.
+func (p PromoCode) Discount(total int) int {
+	var discount int
+	switch p.DiscountType {
+	case DiscountTypePercent:
+		discount = total * p.DiscountValue / 100
+	case DiscountTypeFixed:
+		discount = p.DiscountValue
+	}
+	if discount > total {
+		return total
+	}
+	return discount
+}
+
+type PromoRedemption struct {
+	ID          int       `db:"id"`
+	PromoCodeID int       `db:"promo_code_id"`
+	UserID      int       `db:"user_id"`
+	OrderID     int       `db:"order_id"`
+	Discount    int       `db:"discount"`
+	CreatedAt   time.Time `db:"created_at"`
+}
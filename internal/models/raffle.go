@@ -0,0 +1,80 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+const (
+	RaffleStatusOpen  = "open"
+	RaffleStatusDrawn = "drawn"
+)
+
+// Raffle sells numbered tickets until ClosesAt and then draws one winner.
+//
+// The draw is committed up front: SeedHash is the hex SHA-256 of Seed and is
+// published when the raffle is created, while Seed itself is only revealed
+// after the draw. Tickets are numbered from 1 in purchase order and the
+// winning number is derived with DrawTicket, so anyone can check the result
+// once the seed is known.
+type Raffle struct {
+	ID                int        `db:"id" json:"id"`
+	Title             string     `db:"title" json:"title"`
+	TicketPrice       int        `db:"ticket_price" json:"ticketPrice"`
+	MaxTicketsPerUser int        `db:"max_tickets_per_user" json:"maxTicketsPerUser,omitempty"`
+	ClosesAt          time.Time  `db:"closes_at" json:"closesAt"`
+	PrizeItemID       *int       `db:"prize_item_id" json:"-"`
+	PrizeVariantID    *int       `db:"prize_variant_id" json:"-"`
+	PrizeItem         *string    `db:"prize_item" json:"prizeItem,omitempty"`
+	PrizeSize         *string    `db:"prize_size" json:"prizeSize,omitempty"`
+	PrizeColor        *string    `db:"prize_color" json:"prizeColor,omitempty"`
+	PrizeCoins        int        `db:"prize_coins" json:"prizeCoins,omitempty"`
+	SeedHash          string     `db:"seed_hash" json:"seedHash"`
+	Seed              string     `db:"seed" json:"seed,omitempty"`
+	Status            string     `db:"status" json:"status"`
+	TicketsSold       int        `db:"tickets_sold" json:"ticketsSold"`
+	WinningTicket     *int       `db:"winning_ticket" json:"winningTicket,omitempty"`
+	WinnerID          *int       `db:"winner_id" json:"-"`
+	Winner            *string    `db:"winner" json:"winner,omitempty"`
+	CreatedBy         *int       `db:"created_by" json:"-"`
+	CreatedAt         time.Time  `db:"created_at" json:"createdAt"`
+	DrawnAt           *time.Time `db:"drawn_at" json:"drawnAt,omitempty"`
+}
+
+type RaffleTicket struct {
+	ID            int       `db:"id" json:"id"`
+	RaffleID      int       `db:"raffle_id" json:"raffleId"`
+	UserID        int       `db:"user_id" json:"-"`
+	Quantity      int       `db:"quantity" json:"quantity"`
+	TransactionID int       `db:"transaction_id" json:"-"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// CommitSeed returns the published commitment for a raffle seed.
+func CommitSeed(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// DrawTicket returns the winning ticket number in [1, tickets]. It hashes the
+// seed together with the number of tickets sold, so the result cannot be
+// known before sales close.
+func DrawTicket(seed string, tickets int) int {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", seed, tickets)))
+	return int(binary.BigEndian.Uint64(sum[:8])%uint64(tickets)) + 1
+}
+
+// TicketHolder returns the user holding the given ticket number. Tickets must
+// be ordered by purchase.
+func TicketHolder(tickets []RaffleTicket, number int) (int, bool) {
+	for _, t := range tickets {
+		if number <= t.Quantity {
+			return t.UserID, true
+		}
+		number -= t.Quantity
+	}
+	return 0, false
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	ReturnStatusRequested = "requested"
+	ReturnStatusApproved  = "approved"
+	ReturnStatusRejected  = "rejected"
+)
+
+type Return struct {
+	ID            int        `db:"id" json:"id"`
+	OrderID       int        `db:"order_id" json:"orderId"`
+	UserID        int        `db:"user_id" json:"userId"`
+	Quantity      int        `db:"quantity" json:"quantity"`
+	Reason        string     `db:"reason" json:"reason"`
+	Status        string     `db:"status" json:"status"`
+	ReviewerID    *int       `db:"reviewer_id" json:"reviewerId,omitempty"`
+	RefundAmount  int        `db:"refund_amount" json:"refundAmount"`
+	TransactionID *int       `db:"transaction_id" json:"-"`
+	CreatedAt     time.Time  `db:"created_at" json:"createdAt"`
+	ReviewedAt    *time.Time `db:"reviewed_at" json:"reviewedAt,omitempty"`
+}
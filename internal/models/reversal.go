@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	ReversalStatusPending   = "pending"
+	ReversalStatusCompleted = "completed"
+	ReversalStatusRejected  = "rejected"
+)
+
+// Reversal undoes a transfer with a compensating transaction. A reversal
+// that may take the receiver's balance below zero waits as pending until a
+// second admin approves it.
+type Reversal struct {
+	ID                    int        `db:"id" json:"id"`
+	TransactionID         int        `db:"transaction_id" json:"transactionId"`
+	Reason                string     `db:"reason" json:"reason"`
+	AllowNegative         bool       `db:"allow_negative" json:"allowNegative"`
+	Status                string     `db:"status" json:"status"`
+	RequestedBy           *int       `db:"requested_by" json:"-"`
+	RequestedByName       *string    `db:"requested_by_name" json:"requestedBy,omitempty"`
+	ApprovedBy            *int       `db:"approved_by" json:"-"`
+	ApprovedByName        *string    `db:"approved_by_name" json:"approvedBy,omitempty"`
+	ReversalTransactionID *int       `db:"reversal_transaction_id" json:"reversalTransactionId,omitempty"`
+	FromUser              string     `db:"from_user" json:"fromUser"`
+	ToUser                string     `db:"to_user" json:"toUser"`
+	Amount                int        `db:"amount" json:"amount"`
+	CreatedAt             time.Time  `db:"created_at" json:"createdAt"`
+	ResolvedAt            *time.Time `db:"resolved_at" json:"resolvedAt,omitempty"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusPaused    = "paused"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusFailed    = "failed"
+	ScheduleStatusCancelled = "cancelled"
+)
+
+// Catch-up rules decide what happens to runs that were missed, for example
+// while the server was down.
+const (
+	// CatchUpLatest makes a single transfer for all missed runs.
+	CatchUpLatest = "latest"
+	// CatchUpAll makes one transfer per missed run.
+	CatchUpAll = "all"
+	// CatchUpSkip drops runs that are too late.
+	CatchUpSkip = "skip"
+)
+
+const (
+	ScheduleRunStatusSucceeded = "succeeded"
+	ScheduleRunStatusFailed    = "failed"
+	ScheduleRunStatusSkipped   = "skipped"
+)
+
+// IsValidCatchUp reports whether rule is a known catch-up rule.
+func IsValidCatchUp(rule string) bool {
+	switch rule {
+	case CatchUpLatest, CatchUpAll, CatchUpSkip:
+		return true
+	}
+	return false
+}
+
+// ScheduledTransfer sends coins once at NextRunAt when Cron is nil, or
+// repeatedly following the cron expression.
+type ScheduledTransfer struct {
+	ID         int        `db:"id" json:"id"`
+	SenderID   int        `db:"sender_id" json:"-"`
+	ReceiverID int        `db:"receiver_id" json:"-"`
+	Receiver   string     `db:"receiver" json:"toUser"`
+	Amount     int        `db:"amount" json:"amount"`
+	Cron       *string    `db:"cron" json:"cron,omitempty"`
+	CatchUp    string     `db:"catch_up" json:"catchUp"`
+	NextRunAt  *time.Time `db:"next_run_at" json:"nextRunAt,omitempty"`
+	Status     string     `db:"status" json:"status"`
+	Failures   int        `db:"failures" json:"failures"`
+	LastRunAt  *time.Time `db:"last_run_at" json:"lastRunAt,omitempty"`
+	LastError  *string    `db:"last_error" json:"lastError,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+type ScheduledTransferRun struct {
+	ID           int       `db:"id" json:"id"`
+	ScheduleID   int       `db:"schedule_id" json:"scheduleId"`
+	ScheduledFor time.Time `db:"scheduled_for" json:"scheduledFor"`
+	Status       string    `db:"status" json:"status"`
+	Error        *string   `db:"error" json:"error,omitempty"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// TeamPotAccountID is the system account coins in team pots are held by.
+const TeamPotAccountID = -4
+
+const (
+	TeamRoleMember  = "member"
+	TeamRoleManager = "manager"
+)
+
+// Ways to send coins to a team: split evenly between the members, or into
+// the team pot for the managers to pay out.
+const (
+	TeamSendSplit = "split"
+	TeamSendPot   = "pot"
+)
+
+// Team is a group of employees, such as a team inside a department. Pot
+// holds the coins sent to the team as a whole. Budget caps how much the
+// managers pay out of the pot in a month, 0 means no cap.
+type Team struct {
+	ID         int          `db:"id" json:"id"`
+	Name       string       `db:"name" json:"name"`
+	Department string       `db:"department" json:"department,omitempty"`
+	Pot        int          `db:"pot" json:"pot"`
+	Budget     int          `db:"budget" json:"budget"`
+	Members    int          `db:"members" json:"members"`
+	CreatedAt  time.Time    `db:"created_at" json:"createdAt"`
+	MemberList []TeamMember `db:"-" json:"memberList,omitempty"`
+}
+
+type TeamMember struct {
+	TeamID   int       `db:"team_id" json:"-"`
+	UserID   int       `db:"user_id" json:"-"`
+	Username string    `db:"username" json:"username"`
+	Role     string    `db:"role" json:"role"`
+	JoinedAt time.Time `db:"joined_at" json:"joinedAt"`
+}
+
+func IsValidTeamRole(role string) bool {
+	switch role {
+	case TeamRoleMember, TeamRoleManager:
+		return true
+	}
+	return false
+}
+
+// TeamBudget is what is left of a team's monthly payout budget. Remaining
+// is nil when the team has no cap.
+type TeamBudget struct {
+	TeamID    int    `db:"team_id" json:"teamId"`
+	Period    string `db:"-" json:"period"`
+	Pot       int    `db:"pot" json:"pot"`
+	Budget    int    `db:"budget" json:"budget"`
+	PaidOut   int    `db:"paid_out" json:"paidOut"`
+	Remaining *int   `db:"-" json:"remaining,omitempty"`
+}
+
+// TeamStanding is a team's place on the leaderboard: the coins its members
+// received and sent in the period.
+type TeamStanding struct {
+	Rank       int    `db:"-" json:"rank"`
+	TeamID     int    `db:"team_id" json:"teamId"`
+	Name       string `db:"name" json:"name"`
+	Department string `db:"department" json:"department,omitempty"`
+	Members    int    `db:"members" json:"members"`
+	Received   int    `db:"received" json:"received"`
+	Sent       int    `db:"sent" json:"sent"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// DefaultTenantID is the tenant requests belong to when neither the host
+// nor the token names one. It owns all the data from before tenants.
+const DefaultTenantID = 1
+
+// Tenant is a separate store sharing the deployment, with its own users,
+// catalog and coins. Host maps a domain to the tenant.
+type Tenant struct {
+	ID              int       `db:"id" json:"id"`
+	Slug            string    `db:"slug" json:"slug"`
+	Name            string    `db:"name" json:"name"`
+	Host            *string   `db:"host" json:"host,omitempty"`
+	CurrencyName    string    `db:"currency_name" json:"currency"`
+	StartingBalance int       `db:"starting_balance" json:"startingBalance"`
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
+}
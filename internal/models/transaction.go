@@ -1,8 +1,95 @@
 package models
 
+const (
+	TransactionTypeTransfer = "transfer"
+	TransactionTypePurchase = "purchase"
+	TransactionTypeRefund   = "refund"
+	TransactionTypeGift     = "gift"
+
+	TransactionTypeRaffleTicket = "raffle_ticket"
+	TransactionTypeRafflePrize  = "raffle_prize"
+
+	TransactionTypePoolPurchase = "pool_purchase"
+
+	TransactionTypeItemTransfer = "item_transfer"
+
+	TransactionTypeIssuance   = "issuance"
+	TransactionTypeGrant      = "grant"
+	TransactionTypeAdjustment = "adjustment"
+	TransactionTypeSignup     = "signup"
+
+	TransactionTypeAllowance = "allowance"
+
+	TransactionTypeExpiry = "expiry"
+
+	TransactionTypeReversal = "reversal"
+
+	TransactionTypeTeamPot    = "team_pot"
+	TransactionTypeTeamPayout = "team_payout"
+)
+
 type Transaction struct {
+	ID          int    `db:"id"`
+	SenderID    int    `db:"sender_id"`
+	ReceiverID  int    `db:"receiver_id"`
+	Amount      int    `db:"amount"`
+	Type        string `db:"type"`
+	ReferenceID *int   `db:"reference_id"`
+	BatchID     *int   `db:"batch_id"`
+	IssuanceID  *int   `db:"issuance_id"`
+	// ReversalOf links a reversal to the transaction it undoes, ReversedBy
+	// links the other way and is only filled when loading.
+	ReversalOf *int `db:"reversal_of"`
+	ReversedBy *int `db:"reversed_by"`
+	// TeamID is set on coins sent into or paid out of a team pot.
+	TeamID *int `db:"team_id"`
+
+	// ItemName and ItemQuantity are filled for purchases, gifts and item
+	// transfers when loading history.
+	ItemName     *string `db:"item_name"`
+	ItemQuantity *int    `db:"item_quantity"`
+}
+
+type ItemTransfer struct {
+	ID            int `db:"id"`
+	TransactionID int `db:"transaction_id"`
+	SenderID      int `db:"sender_id"`
+	ReceiverID    int `db:"receiver_id"`
+	ItemID        int `db:"item_id"`
+	VariantID     int `db:"variant_id"`
+	Quantity      int `db:"quantity"`
+}
+
+// TransferBatch groups the transfers made by one batch send.
+type TransferBatch struct {
 	ID         int `db:"id"`
 	SenderID   int `db:"sender_id"`
-	ReceiverID int `db:"receiver_id"`
-	Amount     int `db:"amount"`
+	Total      int `db:"total"`
+	Recipients int `db:"recipients"`
+}
+
+const (
+	BatchTransferStatusSent    = "sent"
+	BatchTransferStatusPending = "pending"
+	BatchTransferStatusReview  = "review"
+	BatchTransferStatusInvalid = "invalid"
+	BatchTransferStatusNotSent = "not_sent"
+)
+
+type BatchTransfer struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+}
+
+type BatchTransferResult struct {
+	ToUser string `json:"toUser"`
+	Amount int    `json:"amount"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	BatchID int                   `json:"batchId,omitempty"`
+	Total   int                   `json:"total"`
+	Results []BatchTransferResult `json:"results"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Codes of the transfer limits, returned to clients when a transfer is
+// rejected.
+const (
+	TransferLimitPerTransfer      = "limit_per_transfer"
+	TransferLimitPerDay           = "limit_per_day"
+	TransferLimitPerRecipientWeek = "limit_per_recipient_week"
+	TransferLimitRecipientsHour   = "limit_recipients_per_hour"
+)
+
+// TransferLimits caps how many coins a user may send. Zero means no limit.
+type TransferLimits struct {
+	MaxPerTransfer       int `json:"maxPerTransfer"`
+	MaxPerDay            int `json:"maxPerDay"`
+	MaxPerRecipientWeek  int `json:"maxPerRecipientWeek"`
+	MaxRecipientsPerHour int `json:"maxRecipientsPerHour"`
+}
+
+// TransferLimitOverride replaces the default limits for one user or for a
+// role. A nil field keeps the limit it overrides.
+type TransferLimitOverride struct {
+	ID                   int       `db:"id" json:"id"`
+	UserID               *int      `db:"user_id" json:"-"`
+	Username             *string   `db:"username" json:"username,omitempty"`
+	Role                 *string   `db:"role" json:"role,omitempty"`
+	MaxPerTransfer       *int      `db:"max_per_transfer" json:"maxPerTransfer"`
+	MaxPerDay            *int      `db:"max_per_day" json:"maxPerDay"`
+	MaxPerRecipientWeek  *int      `db:"max_per_recipient_week" json:"maxPerRecipientWeek"`
+	MaxRecipientsPerHour *int      `db:"max_recipients_per_hour" json:"maxRecipientsPerHour"`
+	CreatedAt            time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Apply returns the limits with the fields set in the override replaced.
+func (o TransferLimitOverride) Apply(limits TransferLimits) TransferLimits {
+	if o.MaxPerTransfer != nil {
+		limits.MaxPerTransfer = *o.MaxPerTransfer
+	}
+	if o.MaxPerDay != nil {
+		limits.MaxPerDay = *o.MaxPerDay
+	}
+	if o.MaxPerRecipientWeek != nil {
+		limits.MaxPerRecipientWeek = *o.MaxPerRecipientWeek
+	}
+	if o.MaxRecipientsPerHour != nil {
+		limits.MaxRecipientsPerHour = *o.MaxRecipientsPerHour
+	}
+	return limits
+}
+
+// TransferUsage is what a sender has already sent within the limit windows.
+type TransferUsage struct {
+	SentLastDay         int  `db:"sent_last_day"`
+	SentToReceiverWeek  int  `db:"sent_to_receiver_week"`
+	OtherRecipientsHour int  `db:"other_recipients_hour"`
+	ReceiverLastHour    bool `db:"receiver_last_hour"`
+}
+
+type TransferLimitsInfo struct {
+	Defaults  TransferLimits          `json:"defaults"`
+	Overrides []TransferLimitOverride `json:"overrides"`
+}
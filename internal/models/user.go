@@ -1,9 +1,24 @@
 package models
 
+const (
+	RoleEmployee     = "employee"
+	RoleMerchManager = "merch-manager"
+	RoleAdmin        = "admin"
+)
+
 type User struct {
 	ID           int                 `db:"id"`
 	Username     string              `db:"username"`
 	PasswordHash string              `db:"password_hash"`
 	Balance      int                 `db:"balance"`
+	Role         string              `db:"role"`
 	Inventory    []UserInventoryItem `db:"-"`
 }
+
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleEmployee, RoleMerchManager, RoleAdmin:
+		return true
+	}
+	return false
+}
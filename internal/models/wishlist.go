@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type WishlistEntry struct {
+	ItemID     int       `db:"item_id" json:"-"`
+	Item       string    `db:"item" json:"item"`
+	Category   string    `db:"category" json:"-"`
+	Price      int       `db:"price" json:"price"`
+	InStock    bool      `db:"in_stock" json:"inStock"`
+	Affordable bool      `db:"-" json:"affordable"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
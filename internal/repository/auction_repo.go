@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type AuctionRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error
+	GetOpen(ctx context.Context) ([]models.Auction, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, auctionID int) (*models.Auction, error)
+	GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error)
+	UpdateHighestBid(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error
+	Close(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error
+	CreateBid(ctx context.Context, tx *sqlx.Tx, bid *models.Bid) error
+	SetActiveBidStatus(ctx context.Context, tx *sqlx.Tx, auctionID int, status string) error
+	GetReservedAmount(ctx context.Context, userID int) (int, error)
+}
+
+type auctionRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuctionRepo(db *sqlx.DB) AuctionRepo {
+	return &auctionRepo{db: db}
+}
+
+const auctionColumns = `a.id, a.item_id, a.variant_id, i.name AS item, v.size, v.color, a.start_price,
+		       a.increment, a.extend_by_seconds, a.ends_at, a.status, a.highest_bid, a.highest_bidder_id,
+		       a.winner_id, a.order_id, a.created_by, a.created_at, a.closed_at`
+
+func (r *auctionRepo) Create(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error {
+	query := `
+		INSERT INTO auctions (item_id, variant_id, start_price, increment, extend_by_seconds,
+		                      ends_at, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		auction.ItemID, auction.VariantID, auction.StartPrice, auction.Increment, auction.ExtendBySeconds,
+		auction.EndsAt, auction.Status, auction.CreatedBy).Scan(&auction.ID, &auction.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create auction: %w", err)
+	}
+	return nil
+}
+
+func (r *auctionRepo) GetOpen(ctx context.Context) ([]models.Auction, error) {
+	var auctions []models.Auction
+	query := `
+		SELECT ` + auctionColumns + `
+		  FROM auctions a
+		  JOIN items i ON i.id = a.item_id
+		  JOIN item_variants v ON v.id = a.variant_id
+		 WHERE a.status = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		 ORDER BY a.ends_at, a.id
+		`
+	err := r.db.SelectContext(ctx, &auctions, query, models.AuctionStatusOpen, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get open auctions: %w", err)
+	}
+	return auctions, nil
+}
+
+func (r *auctionRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, auctionID int) (*models.Auction, error) {
+	var auction models.Auction
+	query := `
+		SELECT ` + auctionColumns + `
+		  FROM auctions a
+		  JOIN items i ON i.id = a.item_id
+		  JOIN item_variants v ON v.id = a.variant_id
+		 WHERE a.id = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		   FOR UPDATE OF a
+		`
+	err := tx.GetContext(ctx, &auction, query, auctionID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock auction: %w", err)
+	}
+	return &auction, nil
+}
+
+func (r *auctionRepo) GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT id
+		  FROM auctions
+		 WHERE status = $1 AND ends_at <= $2
+		   AND item_id IN (SELECT id FROM items WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY ends_at, id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, models.AuctionStatusOpen, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get expired auctions: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *auctionRepo) UpdateHighestBid(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error {
+	query := `UPDATE auctions SET highest_bid = $1, highest_bidder_id = $2, ends_at = $3 WHERE id = $4`
+	_, err := tx.ExecContext(ctx, query, auction.HighestBid, auction.HighestBidderID, auction.EndsAt, auction.ID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update auction bid: %w", err)
+	}
+	return nil
+}
+
+func (r *auctionRepo) Close(ctx context.Context, tx *sqlx.Tx, auction *models.Auction) error {
+	query := `
+		UPDATE auctions
+		   SET status = $1, winner_id = $2, order_id = $3, closed_at = CURRENT_TIMESTAMP
+		 WHERE id = $4
+		RETURNING closed_at
+		`
+	err := tx.QueryRowContext(ctx, query, auction.Status, auction.WinnerID, auction.OrderID, auction.ID).
+		Scan(&auction.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot close auction: %w", err)
+	}
+	return nil
+}
+
+func (r *auctionRepo) CreateBid(ctx context.Context, tx *sqlx.Tx, bid *models.Bid) error {
+	query := `
+		INSERT INTO bids (auction_id, user_id, amount, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query, bid.AuctionID, bid.UserID, bid.Amount, bid.Status).
+		Scan(&bid.ID, &bid.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create bid: %w", err)
+	}
+	return nil
+}
+
+func (r *auctionRepo) SetActiveBidStatus(ctx context.Context, tx *sqlx.Tx, auctionID int, status string) error {
+	query := `UPDATE bids SET status = $1 WHERE auction_id = $2 AND status = $3`
+	_, err := tx.ExecContext(ctx, query, status, auctionID, models.BidStatusActive)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update bids: %w", err)
+	}
+	return nil
+}
+
+// GetReservedAmount returns the coins the user has held in leading bids.
+func (r *auctionRepo) GetReservedAmount(ctx context.Context, userID int) (int, error) {
+	var amount int
+	query := `SELECT COALESCE(SUM(amount), 0) FROM bids WHERE user_id = $1 AND status = $2`
+	err := r.db.GetContext(ctx, &amount, query, userID, models.BidStatusActive)
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get reserved bid amount: %w", err)
+	}
+	return amount, nil
+}
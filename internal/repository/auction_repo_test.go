@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuctionRepo_GetOpen_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM auctions a JOIN items i ON i\.id = a\.item_id JOIN item_variants v ON v\.id = a\.variant_id `+
+		`WHERE a\.status = \$1 AND \(\$2 = 0 OR i\.tenant_id = \$2\)`).
+		WithArgs(models.AuctionStatusOpen, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewAuctionRepo(sqlxDB)
+	auctions, err := repo.GetOpen(WithTenant(context.Background(), 2))
+	assert.NoError(t, err)
+	assert.Empty(t, auctions)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestAuctionRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE a\.id = \$1 AND \(\$2 = 0 OR i\.tenant_id = \$2\) FOR UPDATE OF a`).
+		WithArgs(7, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewAuctionRepo(sqlxDB)
+	auction, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 7)
+	assert.NoError(t, err)
+	assert.Nil(t, auction)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+	"strings"
+)
+
+type AuditRepo interface {
+	LockChain(ctx context.Context, tx *sqlx.Tx) (string, error)
+	Create(ctx context.Context, tx *sqlx.Tx, entry *models.AuditEntry) error
+	GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	GetChain(ctx context.Context, afterID int, limit int) ([]models.AuditEntry, error)
+}
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepo(db *sqlx.DB) AuditRepo {
+	return &auditRepo{db: db}
+}
+
+// auditChainLock is the advisory lock key that serializes appends to the
+// audit log, so no two entries link to the same previous hash.
+const auditChainLock = 7_316_001
+
+const auditColumns = `a.id, a.action, a.actor_id, a.actor_name, COALESCE(u.username, a.actor_name) AS actor,
+		       a.target_type, a.target_id, a.before, a.after, a.request_id, a.prev_hash, a.hash, a.created_at`
+
+// LockChain takes the append lock until the transaction ends and returns
+// the hash of the last entry, empty for the first one.
+func (r *auditRepo) LockChain(ctx context.Context, tx *sqlx.Tx) (string, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditChainLock); err != nil {
+		return "", fmt.Errorf("repository: cannot lock audit log: %w", err)
+	}
+
+	var hash string
+	query := `SELECT COALESCE((SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1), '')`
+	if err := tx.GetContext(ctx, &hash, query); err != nil {
+		return "", fmt.Errorf("repository: cannot get last audit hash: %w", err)
+	}
+	return hash, nil
+}
+
+// Create appends the entry to the log of the context's tenant. The hash
+// chain still runs through the entries of every tenant.
+func (r *auditRepo) Create(ctx context.Context, tx *sqlx.Tx, entry *models.AuditEntry) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot create audit entry: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO audit_log (action, actor_id, actor_name, target_type, target_id, before, after,
+		                       request_id, prev_hash, hash, created_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+		`
+	err := tx.QueryRowContext(ctx, query, entry.Action, entry.ActorID, entry.ActorName, entry.TargetType,
+		entry.TargetID, nullJSON(entry.Before), nullJSON(entry.After), entry.RequestID, entry.PrevHash,
+		entry.Hash, entry.CreatedAt, tenantID).
+		Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create audit entry: %w", err)
+	}
+	return nil
+}
+
+func nullJSON(value []byte) any {
+	if len(value) == 0 {
+		return nil
+	}
+	return string(value)
+}
+
+// GetAll returns the newest entries matching the filter first. BeforeID
+// pages back through older entries.
+func (r *auditRepo) GetAll(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var conditions []string
+	var args []any
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+
+	if tenantID := TenantFromContext(ctx); tenantID != allTenants {
+		where("a.tenant_id = ?", tenantID)
+	}
+	if filter.Action != "" {
+		where("a.action = ?", filter.Action)
+	}
+	if filter.Actor != "" {
+		where("COALESCE(u.username, a.actor_name) = ?", filter.Actor)
+	}
+	if filter.TargetType != "" {
+		where("a.target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		where("a.target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		where("a.request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		where("a.created_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		where("a.created_at < ?", filter.To.UTC())
+	}
+	if filter.BeforeID > 0 {
+		where("a.id < ?", filter.BeforeID)
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		  FROM audit_log a
+		  LEFT JOIN users u ON u.id = a.actor_id`
+	if len(conditions) > 0 {
+		query += `
+		 WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += `
+		 ORDER BY a.id DESC
+		 LIMIT $` + strconv.Itoa(len(args))
+
+	var entries []models.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("repository: cannot get audit entries: %w", err)
+	}
+	return entries, nil
+}
+
+// GetChain returns entries in chain order, starting after the given id.
+func (r *auditRepo) GetChain(ctx context.Context, afterID int, limit int) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	query := `
+		SELECT ` + auditColumns + `
+		  FROM audit_log a
+		  LEFT JOIN users u ON u.id = a.actor_id
+		 WHERE a.id > $1
+		 ORDER BY a.id
+		 LIMIT $2
+		`
+	if err := r.db.SelectContext(ctx, &entries, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("repository: cannot get audit chain: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRepo_GetAll_Tenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		mockSetup func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name: "Tenant admin",
+			ctx:  WithTenant(context.Background(), 2),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM audit_log a LEFT JOIN users u ON u\.id = a\.actor_id `+
+					`WHERE a\.tenant_id = \$1 AND a\.action = \$2 ORDER BY a\.id DESC LIMIT \$3`).
+					WithArgs(2, models.AuditActionRoleChanged, 50).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+		{
+			name: "Every tenant",
+			ctx:  WithAllTenants(context.Background()),
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`FROM audit_log a LEFT JOIN users u ON u\.id = a\.actor_id `+
+					`WHERE a\.action = \$1 ORDER BY a\.id DESC LIMIT \$2`).
+					WithArgs(models.AuditActionRoleChanged, 50).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup(mock)
+
+			repo := NewAuditRepo(sqlxDB)
+			entries, err := repo.GetAll(tt.ctx, models.AuditFilter{Action: models.AuditActionRoleChanged, Limit: 50})
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestAuditRepo_Create_AllTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewAuditRepo(sqlxDB)
+	err = repo.Create(WithAllTenants(context.Background()), tx, &models.AuditEntry{Action: models.AuditActionRoleChanged})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type CoinLotRepo interface {
+	GetExpiring(ctx context.Context, userID int, before time.Time) ([]models.CoinLot, error)
+	GetExpiredUserIDs(ctx context.Context, at time.Time) ([]int, error)
+	Expire(ctx context.Context, tx *sqlx.Tx, userID int, at time.Time) (int, error)
+}
+
+type coinLotRepo struct {
+	db *sqlx.DB
+}
+
+func NewCoinLotRepo(db *sqlx.DB) CoinLotRepo {
+	return &coinLotRepo{db: db}
+}
+
+// GetExpiring returns the user's unspent lots that expire before the given
+// time, soonest first.
+func (r *coinLotRepo) GetExpiring(ctx context.Context, userID int, before time.Time) ([]models.CoinLot, error) {
+	var lots []models.CoinLot
+	query := `
+		SELECT id, user_id, amount, remaining, source, earned_at, expires_at
+		  FROM coin_lots
+		 WHERE user_id = $1 AND remaining > 0 AND expires_at < $2
+		   AND user_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY expires_at, id
+		`
+	err := r.db.SelectContext(ctx, &lots, query, userID, before, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get expiring coin lots: %w", err)
+	}
+	return lots, nil
+}
+
+func (r *coinLotRepo) GetExpiredUserIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT DISTINCT user_id
+		  FROM coin_lots
+		 WHERE remaining > 0 AND expires_at <= $1
+		   AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY user_id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get users with expired coins: %w", err)
+	}
+	return ids, nil
+}
+
+// Expire empties the user's lots that expired by the given time and
+// returns how many coins they still held.
+func (r *coinLotRepo) Expire(ctx context.Context, tx *sqlx.Tx, userID int, at time.Time) (int, error) {
+	var expired int
+	query := `
+		WITH expired AS (
+			SELECT id, remaining
+			  FROM coin_lots
+			 WHERE user_id = $1 AND remaining > 0 AND expires_at <= $2
+			   AND user_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+			   FOR UPDATE
+		), emptied AS (
+			UPDATE coin_lots c
+			   SET remaining = 0
+			  FROM expired e
+			 WHERE c.id = e.id
+		)
+		SELECT COALESCE(SUM(remaining), 0) FROM expired
+		`
+	err := tx.GetContext(ctx, &expired, query, userID, at, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot expire coin lots: %w", err)
+	}
+	return expired, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinLotRepo_GetExpiring_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	before := time.Now()
+
+	mock.ExpectQuery(`AND user_id IN \(SELECT id FROM users WHERE \$3 = 0 OR tenant_id = \$3\)`).
+		WithArgs(5, before, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "amount", "remaining", "source",
+			"earned_at", "expires_at"}))
+
+	repo := NewCoinLotRepo(sqlxDB)
+	lots, err := repo.GetExpiring(WithTenant(context.Background(), 2), 5, before)
+	assert.NoError(t, err)
+	assert.Empty(t, lots)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCoinLotRepo_Expire_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	at := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`AND user_id IN \(SELECT id FROM users WHERE \$3 = 0 OR tenant_id = \$3\) FOR UPDATE`).
+		WithArgs(5, at, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewCoinLotRepo(sqlxDB)
+	expired, err := repo.Expire(WithTenant(context.Background(), 2), tx, 5, at)
+	assert.NoError(t, err)
+	assert.Zero(t, expired)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
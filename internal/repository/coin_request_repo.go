@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type CoinRequestRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, request *models.CoinRequest) error
+	GetIncoming(ctx context.Context, payerID int) ([]models.CoinRequest, error)
+	GetOutgoing(ctx context.Context, requesterID int) ([]models.CoinRequest, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, requestID int) (*models.CoinRequest, error)
+	GetOpenByPair(ctx context.Context, tx *sqlx.Tx, requesterID int, payerID int, at time.Time) ([]models.CoinRequest, error)
+	Resolve(ctx context.Context, tx *sqlx.Tx, request *models.CoinRequest) error
+	ExpirePending(ctx context.Context, at time.Time) (int, error)
+}
+
+type coinRequestRepo struct {
+	db *sqlx.DB
+}
+
+func NewCoinRequestRepo(db *sqlx.DB) CoinRequestRepo {
+	return &coinRequestRepo{db: db}
+}
+
+const coinRequestColumns = `cr.id, cr.requester_id, ru.username AS requester, cr.payer_id, pu.username AS payer,
+		       cr.amount, cr.reason, cr.status, cr.expires_at, cr.created_at, cr.resolved_at`
+
+const coinRequestJoins = `
+		  JOIN users ru ON ru.id = cr.requester_id
+		  JOIN users pu ON pu.id = cr.payer_id`
+
+func (r *coinRequestRepo) Create(ctx context.Context, tx *sqlx.Tx, request *models.CoinRequest) error {
+	query := `
+		INSERT INTO coin_requests (requester_id, payer_id, amount, reason, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		request.RequesterID, request.PayerID, request.Amount, request.Reason, request.Status, request.ExpiresAt).
+		Scan(&request.ID, &request.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create coin request: %w", err)
+	}
+	return nil
+}
+
+// GetIncoming returns the pending requests the user is asked to pay.
+func (r *coinRequestRepo) GetIncoming(ctx context.Context, payerID int) ([]models.CoinRequest, error) {
+	var requests []models.CoinRequest
+	query := `
+		SELECT ` + coinRequestColumns + `
+		  FROM coin_requests cr` + coinRequestJoins + `
+		 WHERE cr.payer_id = $1 AND cr.status = $2 AND ($3 = 0 OR pu.tenant_id = $3)
+		 ORDER BY cr.id
+		`
+	err := r.db.SelectContext(ctx, &requests, query, payerID, models.CoinRequestStatusPending, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get incoming coin requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *coinRequestRepo) GetOutgoing(ctx context.Context, requesterID int) ([]models.CoinRequest, error) {
+	var requests []models.CoinRequest
+	query := `
+		SELECT ` + coinRequestColumns + `
+		  FROM coin_requests cr` + coinRequestJoins + `
+		 WHERE cr.requester_id = $1 AND ($2 = 0 OR ru.tenant_id = $2)
+		 ORDER BY cr.id DESC
+		`
+	err := r.db.SelectContext(ctx, &requests, query, requesterID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get outgoing coin requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *coinRequestRepo) GetByIDForUpdate(
+	ctx context.Context, tx *sqlx.Tx, requestID int) (*models.CoinRequest, error) {
+	var request models.CoinRequest
+	query := `
+		SELECT ` + coinRequestColumns + `
+		  FROM coin_requests cr` + coinRequestJoins + `
+		 WHERE cr.id = $1 AND ($2 = 0 OR ru.tenant_id = $2)
+		   FOR UPDATE OF cr
+		`
+	err := tx.GetContext(ctx, &request, query, requestID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock coin request: %w", err)
+	}
+	return &request, nil
+}
+
+// GetOpenByPair returns the pending, unexpired requests from requester to
+// payer. The payer row is locked so concurrent requests to the same payer
+// are counted one after another.
+func (r *coinRequestRepo) GetOpenByPair(
+	ctx context.Context, tx *sqlx.Tx, requesterID int, payerID int, at time.Time) ([]models.CoinRequest, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, payerID); err != nil {
+		return nil, fmt.Errorf("repository: cannot lock payer: %w", err)
+	}
+
+	var requests []models.CoinRequest
+	query := `
+		SELECT ` + coinRequestColumns + `
+		  FROM coin_requests cr` + coinRequestJoins + `
+		 WHERE cr.requester_id = $1 AND cr.payer_id = $2 AND cr.status = $3 AND cr.expires_at > $4
+		   AND ($5 = 0 OR ru.tenant_id = $5)
+		 ORDER BY cr.id
+		`
+	err := tx.SelectContext(ctx, &requests, query,
+		requesterID, payerID, models.CoinRequestStatusPending, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get open coin requests: %w", err)
+	}
+	return requests, nil
+}
+
+func (r *coinRequestRepo) Resolve(ctx context.Context, tx *sqlx.Tx, request *models.CoinRequest) error {
+	query := `
+		UPDATE coin_requests
+		   SET status = $1, resolved_at = CURRENT_TIMESTAMP
+		 WHERE id = $2
+		RETURNING resolved_at
+		`
+	err := tx.QueryRowContext(ctx, query, request.Status, request.ID).Scan(&request.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot resolve coin request: %w", err)
+	}
+	return nil
+}
+
+// ExpirePending marks pending requests past their expiry as expired and
+// returns how many were updated.
+func (r *coinRequestRepo) ExpirePending(ctx context.Context, at time.Time) (int, error) {
+	query := `
+		UPDATE coin_requests
+		   SET status = $1, resolved_at = CURRENT_TIMESTAMP
+		 WHERE status = $2 AND expires_at <= $3
+		   AND requester_id IN (SELECT id FROM users WHERE $4 = 0 OR tenant_id = $4)
+		`
+	res, err := r.db.ExecContext(ctx, query,
+		models.CoinRequestStatusExpired, models.CoinRequestStatusPending, at, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot expire coin requests: %w", err)
+	}
+
+	expired, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot expire coin requests: %w", err)
+	}
+	return int(expired), nil
+}
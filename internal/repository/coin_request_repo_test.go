@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoinRequestRepo_GetIncoming_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`WHERE cr\.payer_id = \$1 AND cr\.status = \$2 AND \(\$3 = 0 OR pu\.tenant_id = \$3\)`).
+		WithArgs(5, models.CoinRequestStatusPending, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "requester_id", "payer_id"}))
+
+	repo := NewCoinRequestRepo(sqlxDB)
+	requests, err := repo.GetIncoming(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCoinRequestRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE cr\.id = \$1 AND \(\$2 = 0 OR ru\.tenant_id = \$2\) FOR UPDATE OF cr`).
+		WithArgs(7, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "requester_id", "payer_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewCoinRequestRepo(sqlxDB)
+	request, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 7)
+	assert.NoError(t, err)
+	assert.Nil(t, request)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
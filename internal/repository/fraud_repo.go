@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type FraudRepo interface {
+	FindCircularFlows(ctx context.Context, since time.Time, minAmount int) ([]models.FraudFlag, error)
+	FindNewAccountForwards(ctx context.Context, createdSince time.Time, minAmount int) ([]models.FraudFlag, error)
+	FindVolumeSpikes(ctx context.Context, daySince time.Time, baselineSince time.Time, baselineDays int,
+		factor int, minAmount int) ([]models.FraudFlag, error)
+	GetSenderActivity(ctx context.Context, senderID int, receiverID int, windowSince time.Time,
+		daySince time.Time, baselineSince time.Time) (*models.SenderActivity, error)
+	Create(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error
+	CreateIfNew(ctx context.Context, flag *models.FraudFlag, since time.Time) (bool, error)
+	GetAll(ctx context.Context, status string) ([]models.FraudFlag, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, flagID int) (*models.FraudFlag, error)
+	Review(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error
+}
+
+type fraudRepo struct {
+	db *sqlx.DB
+}
+
+func NewFraudRepo(db *sqlx.DB) FraudRepo {
+	return &fraudRepo{db: db}
+}
+
+const fraudFlagColumns = `f.id, f.rule, f.user_id, u.username, f.counterparty_id, cu.username AS counterparty,
+		       f.amount, f.details, f.pending_transfer_id, f.status, f.reviewed_by, f.created_at,
+		       f.reviewed_at`
+
+const fraudFlagJoins = `
+		  JOIN users u ON u.id = f.user_id
+		  LEFT JOIN users cu ON cu.id = f.counterparty_id`
+
+// FindCircularFlows finds pairs of users who both sent each other at least
+// minAmount coins since the given time. Amount is the smaller of the two
+// flows.
+func (r *fraudRepo) FindCircularFlows(
+	ctx context.Context, since time.Time, minAmount int) ([]models.FraudFlag, error) {
+	var flags []models.FraudFlag
+	query := `
+		WITH flows AS (
+		        SELECT sender_id, receiver_id, SUM(amount) AS amount
+		          FROM transactions
+		         WHERE type = $1 AND timestamp > $2
+		         GROUP BY sender_id, receiver_id
+		       )
+		SELECT a.sender_id AS user_id, a.receiver_id AS counterparty_id, LEAST(a.amount, b.amount) AS amount
+		  FROM flows a
+		  JOIN flows b ON b.sender_id = a.receiver_id AND b.receiver_id = a.sender_id
+		 WHERE a.sender_id < a.receiver_id AND a.amount >= $3 AND b.amount >= $3
+		 ORDER BY a.sender_id, a.receiver_id
+		`
+	err := r.db.SelectContext(ctx, &flags, query, models.TransactionTypeTransfer, since, minAmount)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot find circular flows: %w", err)
+	}
+	return flags, nil
+}
+
+// FindNewAccountForwards finds accounts created since the given time that
+// already sent at least minAmount coins away. The counterparty is the
+// receiver of the largest transfer.
+func (r *fraudRepo) FindNewAccountForwards(
+	ctx context.Context, createdSince time.Time, minAmount int) ([]models.FraudFlag, error) {
+	var flags []models.FraudFlag
+	query := `
+		SELECT u.id AS user_id, SUM(t.amount) AS amount,
+		       (ARRAY_AGG(t.receiver_id ORDER BY t.amount DESC, t.id))[1] AS counterparty_id
+		  FROM users u
+		  JOIN transactions t ON t.sender_id = u.id AND t.type = $1
+		 WHERE u.created_at > $2
+		 GROUP BY u.id
+		HAVING SUM(t.amount) >= $3
+		 ORDER BY u.id
+		`
+	err := r.db.SelectContext(ctx, &flags, query, models.TransactionTypeTransfer, createdSince, minAmount)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot find new account forwards: %w", err)
+	}
+	return flags, nil
+}
+
+// FindVolumeSpikes finds users who sent at least minAmount coins since
+// daySince and more than factor times their daily average between
+// baselineSince and daySince.
+func (r *fraudRepo) FindVolumeSpikes(ctx context.Context, daySince time.Time, baselineSince time.Time,
+	baselineDays int, factor int, minAmount int) ([]models.FraudFlag, error) {
+	var flags []models.FraudFlag
+	query := `
+		SELECT sender_id AS user_id, SUM(amount) FILTER (WHERE timestamp > $2) AS amount
+		  FROM transactions
+		 WHERE type = $1 AND timestamp > $3
+		 GROUP BY sender_id
+		HAVING SUM(amount) FILTER (WHERE timestamp > $2) >= $6
+		   AND SUM(amount) FILTER (WHERE timestamp > $2) >
+		       $5 * COALESCE(SUM(amount) FILTER (WHERE timestamp <= $2), 0) / $4
+		 ORDER BY sender_id
+		`
+	err := r.db.SelectContext(ctx, &flags, query,
+		models.TransactionTypeTransfer, daySince, baselineSince, baselineDays, factor, minAmount)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot find volume spikes: %w", err)
+	}
+	return flags, nil
+}
+
+// GetSenderActivity sums the sender's transfers since signup, in the last
+// day and between baselineSince and daySince, and what the receiver sent
+// back to the sender since windowSince.
+func (r *fraudRepo) GetSenderActivity(ctx context.Context, senderID int, receiverID int,
+	windowSince time.Time, daySince time.Time, baselineSince time.Time) (*models.SenderActivity, error) {
+	var activity models.SenderActivity
+	query := `
+		SELECT u.created_at,
+		       COALESCE(SUM(t.amount) FILTER (
+		           WHERE t.sender_id = u.id AND t.timestamp >= u.created_at), 0) AS sent_since_signup,
+		       COALESCE(SUM(t.amount) FILTER (
+		           WHERE t.sender_id = $2 AND t.timestamp > $3), 0) AS received_from_receiver,
+		       COALESCE(SUM(t.amount) FILTER (
+		           WHERE t.sender_id = u.id AND t.timestamp > $4), 0) AS sent_last_day,
+		       COALESCE(SUM(t.amount) FILTER (
+		           WHERE t.sender_id = u.id AND t.timestamp <= $4), 0) AS sent_before
+		  FROM users u
+		  LEFT JOIN transactions t
+		    ON t.type = $6 AND t.timestamp > $5
+		   AND (t.sender_id = u.id OR (t.sender_id = $2 AND t.receiver_id = u.id))
+		 WHERE u.id = $1
+		 GROUP BY u.id, u.created_at
+		`
+	err := r.db.GetContext(ctx, &activity, query,
+		senderID, receiverID, windowSince, daySince, baselineSince, models.TransactionTypeTransfer)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get sender activity: %w", err)
+	}
+	return &activity, nil
+}
+
+func (r *fraudRepo) Create(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error {
+	query := `
+		INSERT INTO fraud_flags (rule, user_id, counterparty_id, amount, details, pending_transfer_id, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query, flag.Rule, flag.UserID, flag.CounterpartyID, flag.Amount,
+		flag.Details, flag.PendingTransferID, flag.Status).
+		Scan(&flag.ID, &flag.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create fraud flag: %w", err)
+	}
+	return nil
+}
+
+// CreateIfNew creates the flag unless the same rule already flagged the
+// same users since the given time, whatever came of that review.
+func (r *fraudRepo) CreateIfNew(ctx context.Context, flag *models.FraudFlag, since time.Time) (bool, error) {
+	query := `
+		INSERT INTO fraud_flags (rule, user_id, counterparty_id, amount, details, status)
+		SELECT $1::VARCHAR, $2::INT, $3::INT, $4::INT, $5::TEXT, $6::VARCHAR
+		 WHERE NOT EXISTS (
+		        SELECT 1
+		          FROM fraud_flags
+		         WHERE rule = $1 AND user_id = $2 AND counterparty_id IS NOT DISTINCT FROM $3
+		           AND pending_transfer_id IS NULL AND created_at > $7
+		       )
+		RETURNING id, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query, flag.Rule, flag.UserID, flag.CounterpartyID, flag.Amount,
+		flag.Details, flag.Status, since).
+		Scan(&flag.ID, &flag.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("repository: cannot create fraud flag: %w", err)
+	}
+	return true, nil
+}
+
+// GetAll returns the flags with the given status, or every flag when the
+// status is empty, oldest first.
+func (r *fraudRepo) GetAll(ctx context.Context, status string) ([]models.FraudFlag, error) {
+	var flags []models.FraudFlag
+	query := `
+		SELECT ` + fraudFlagColumns + `
+		  FROM fraud_flags f` + fraudFlagJoins + `
+		 WHERE ($1 = '' OR f.status = $1) AND ($2 = 0 OR u.tenant_id = $2)
+		 ORDER BY f.id
+		`
+	err := r.db.SelectContext(ctx, &flags, query, status, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get fraud flags: %w", err)
+	}
+	return flags, nil
+}
+
+func (r *fraudRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, flagID int) (*models.FraudFlag, error) {
+	var flag models.FraudFlag
+	query := `
+		SELECT ` + fraudFlagColumns + `
+		  FROM fraud_flags f` + fraudFlagJoins + `
+		 WHERE f.id = $1 AND ($2 = 0 OR u.tenant_id = $2)
+		   FOR UPDATE OF f
+		`
+	err := tx.GetContext(ctx, &flag, query, flagID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock fraud flag: %w", err)
+	}
+	return &flag, nil
+}
+
+func (r *fraudRepo) Review(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error {
+	query := `
+		UPDATE fraud_flags
+		   SET status = $1, reviewed_by = $2, reviewed_at = CURRENT_TIMESTAMP
+		 WHERE id = $3
+		RETURNING reviewed_at
+		`
+	err := tx.QueryRowContext(ctx, query, flag.Status, flag.ReviewedBy, flag.ID).Scan(&flag.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot review fraud flag: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFraudRepo_GetAll_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM fraud_flags f JOIN users u ON u\.id = f\.user_id LEFT JOIN users cu ON cu\.id = f\.counterparty_id `+
+		`WHERE \(\$1 = '' OR f\.status = \$1\) AND \(\$2 = 0 OR u\.tenant_id = \$2\)`).
+		WithArgs(models.FraudFlagStatusOpen, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewFraudRepo(sqlxDB)
+	flags, err := repo.GetAll(WithTenant(context.Background(), 2), models.FraudFlagStatusOpen)
+	assert.NoError(t, err)
+	assert.Empty(t, flags)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type IssuanceRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, issuance *models.Issuance) error
+	GetAll(ctx context.Context) ([]models.Issuance, error)
+	GetGrantByPeriod(ctx context.Context, tx *sqlx.Tx, period string) (*models.Issuance, error)
+	GetGrantRecipientIDs(ctx context.Context, tx *sqlx.Tx) ([]int, error)
+}
+
+type issuanceRepo struct {
+	db *sqlx.DB
+}
+
+func NewIssuanceRepo(db *sqlx.DB) IssuanceRepo {
+	return &issuanceRepo{db: db}
+}
+
+const issuanceColumns = `iss.id, iss.kind, iss.reason, iss.admin_id, COALESCE(au.username, '') AS admin,
+		       iss.period, iss.total, iss.recipients, iss.created_at`
+
+func (r *issuanceRepo) Create(ctx context.Context, tx *sqlx.Tx, issuance *models.Issuance) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot create issuance: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO issuances (kind, reason, admin_id, period, total, recipients, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query, issuance.Kind, issuance.Reason, issuance.AdminID,
+		issuance.Period, issuance.Total, issuance.Recipients, tenantID).Scan(&issuance.ID, &issuance.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create issuance: %w", err)
+	}
+	return nil
+}
+
+func (r *issuanceRepo) GetAll(ctx context.Context) ([]models.Issuance, error) {
+	var issuances []models.Issuance
+	query := `
+		SELECT ` + issuanceColumns + `
+		  FROM issuances iss
+		  LEFT JOIN users au ON au.id = iss.admin_id
+		 WHERE ($1 = 0 OR iss.tenant_id = $1)
+		 ORDER BY iss.id DESC
+		`
+	err := r.db.SelectContext(ctx, &issuances, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get issuances: %w", err)
+	}
+	return issuances, nil
+}
+
+func (r *issuanceRepo) GetGrantByPeriod(ctx context.Context, tx *sqlx.Tx, period string) (*models.Issuance, error) {
+	var issuance models.Issuance
+	query := `
+		SELECT ` + issuanceColumns + `
+		  FROM issuances iss
+		  LEFT JOIN users au ON au.id = iss.admin_id
+		 WHERE iss.kind = $1 AND iss.period = $2 AND ($3 = 0 OR iss.tenant_id = $3)
+		`
+	err := tx.GetContext(ctx, &issuance, query, models.IssuanceKindGrant, period, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get grant by period: %w", err)
+	}
+	return &issuance, nil
+}
+
+// GetGrantRecipientIDs returns every user account of the tenant, leaving
+// out the system accounts, which have negative ids.
+func (r *issuanceRepo) GetGrantRecipientIDs(ctx context.Context, tx *sqlx.Tx) ([]int, error) {
+	var ids []int
+	query := `SELECT id FROM users WHERE id > 0 AND ($1 = 0 OR tenant_id = $1) ORDER BY id`
+	err := tx.SelectContext(ctx, &ids, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get grant recipients: %w", err)
+	}
+	return ids, nil
+}
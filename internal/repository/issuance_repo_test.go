@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuanceRepo_Grant_Tenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	grantQuery := `FROM issuances iss LEFT JOIN users au ON au\.id = iss\.admin_id ` +
+		`WHERE iss\.kind = \$1 AND iss\.period = \$2 AND \(\$3 = 0 OR iss\.tenant_id = \$3\)`
+	recipientsQuery := `SELECT id FROM users WHERE id > 0 AND \(\$1 = 0 OR tenant_id = \$1\) ORDER BY id`
+
+	tests := []struct {
+		name               string
+		tenantID           int
+		mockSetup          func(mock sqlmock.Sqlmock)
+		expectedPaid       bool
+		expectedRecipients []int
+	}{
+		{
+			name:     "Tenant that paid the period",
+			tenantID: models.DefaultTenantID,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(grantQuery).
+					WithArgs(models.IssuanceKindGrant, "2026-10", models.DefaultTenantID).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "reason", "admin_id", "admin",
+						"period", "total", "recipients", "created_at"}).
+						AddRow(3, models.IssuanceKindGrant, "monthly", 1, "admin", "2026-10", 200, 2, time.Now()))
+				mock.ExpectQuery(recipientsQuery).
+					WithArgs(models.DefaultTenantID).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(3))
+			},
+			expectedPaid:       true,
+			expectedRecipients: []int{1, 3},
+		},
+		{
+			name:     "Other tenant, same period",
+			tenantID: 2,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(grantQuery).
+					WithArgs(models.IssuanceKindGrant, "2026-10", 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}))
+				mock.ExpectQuery(recipientsQuery).
+					WithArgs(2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6))
+			},
+			expectedRecipients: []int{5, 6},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			tt.mockSetup(mock)
+			mock.ExpectCommit()
+
+			tx, err := sqlxDB.Beginx()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+			}
+
+			ctx := WithTenant(context.Background(), tt.tenantID)
+			repo := NewIssuanceRepo(sqlxDB)
+
+			paid, err := repo.GetGrantByPeriod(ctx, tx, "2026-10")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPaid, paid != nil)
+
+			recipients, err := repo.GetGrantRecipientIDs(ctx, tx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRecipients, recipients)
+			assert.NoError(t, tx.Commit())
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestIssuanceRepo_Create_AllTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewIssuanceRepo(sqlxDB)
+	err = repo.Create(WithAllTenants(context.Background()), tx, &models.Issuance{Kind: models.IssuanceKindGrant})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
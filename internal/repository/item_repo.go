@@ -11,6 +11,13 @@ import (
 type ItemRepo interface {
 	GetAll(ctx context.Context) ([]models.Item, error)
 	GetItemByName(ctx context.Context, name string) (*models.Item, error)
+	GetVariants(ctx context.Context, itemID int) ([]models.ItemVariant, error)
+	DecrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) (bool, error)
+	IncrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) error
+	SetStock(ctx context.Context, tx *sqlx.Tx, variantID int, stock *int) (*int, error)
+	GetStock(ctx context.Context, tx *sqlx.Tx, variantID int) (*int, error)
+	SetArchived(ctx context.Context, tx *sqlx.Tx, itemID int, archived bool) error
+	SetPreorderEnabled(ctx context.Context, tx *sqlx.Tx, itemID int, enabled bool) error
 }
 
 type itemRepo struct {
@@ -23,8 +30,12 @@ func NewItemRepo(db *sqlx.DB) ItemRepo {
 
 func (r *itemRepo) GetAll(ctx context.Context) ([]models.Item, error) {
 	var items []models.Item
-	query := "SELECT id, name, price FROM items"
-	err := r.db.SelectContext(ctx, &items, query)
+	query := `
+		SELECT id, name, price, category, preorder_enabled, archived_at
+		FROM items
+		WHERE $1 = 0 OR tenant_id = $1
+		`
+	err := r.db.SelectContext(ctx, &items, query, TenantFromContext(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -36,9 +47,13 @@ func (r *itemRepo) GetAll(ctx context.Context) ([]models.Item, error) {
 
 func (r *itemRepo) GetItemByName(ctx context.Context, name string) (*models.Item, error) {
 	var item models.Item
-	query := `SELECT id, name, price FROM items WHERE name = $1`
+	query := `
+		SELECT id, name, price, category, preorder_enabled, archived_at
+		FROM items
+		WHERE name = $1 AND ($2 = 0 OR tenant_id = $2)
+		`
 
-	err := r.db.GetContext(ctx, &item, query, name)
+	err := r.db.GetContext(ctx, &item, query, name, TenantFromContext(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -47,3 +62,114 @@ func (r *itemRepo) GetItemByName(ctx context.Context, name string) (*models.Item
 	}
 	return &item, nil
 }
+
+func (r *itemRepo) GetVariants(ctx context.Context, itemID int) ([]models.ItemVariant, error) {
+	var variants []models.ItemVariant
+	query := `
+		SELECT id, item_id, size, color, price, stock
+		FROM item_variants
+		WHERE item_id = $1 AND item_id IN (SELECT id FROM items WHERE $2 = 0 OR tenant_id = $2)
+		ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &variants, query, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get item variants: %w", err)
+	}
+	return variants, nil
+}
+
+// DecrementStock reserves quantity units of a variant. It reports false when
+// the variant has limited stock and not enough units are left.
+func (r *itemRepo) DecrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) (bool, error) {
+	query := `
+		UPDATE item_variants
+		   SET stock = stock - $1
+		 WHERE id = $2 AND (stock IS NULL OR stock >= $1)
+		   AND item_id IN (SELECT id FROM items WHERE $3 = 0 OR tenant_id = $3)
+		`
+	res, err := tx.ExecContext(ctx, query, quantity, variantID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to decrement variant stock: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to decrement variant stock: %w", err)
+	}
+	return affected == 1, nil
+}
+
+func (r *itemRepo) IncrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) error {
+	query := `
+		UPDATE item_variants
+		   SET stock = stock + $1
+		 WHERE id = $2 AND stock IS NOT NULL
+		   AND item_id IN (SELECT id FROM items WHERE $3 = 0 OR tenant_id = $3)
+		`
+	_, err := tx.ExecContext(ctx, query, quantity, variantID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: failed to increment variant stock: %w", err)
+	}
+	return nil
+}
+
+// SetStock replaces the stock of a variant and returns the previous value.
+// A nil stock means the variant is unlimited.
+func (r *itemRepo) SetStock(ctx context.Context, tx *sqlx.Tx, variantID int, stock *int) (*int, error) {
+	var previous *int
+	query := `
+		UPDATE item_variants v
+		   SET stock = $1
+		  FROM (SELECT id, stock
+		          FROM item_variants
+		         WHERE id = $2 AND item_id IN (SELECT id FROM items WHERE $3 = 0 OR tenant_id = $3)
+		           FOR UPDATE) old
+		 WHERE v.id = old.id
+		RETURNING old.stock
+		`
+	err := tx.QueryRowContext(ctx, query, stock, variantID, TenantFromContext(ctx)).Scan(&previous)
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to set variant stock: %w", err)
+	}
+	return previous, nil
+}
+
+// GetStock returns the stock of a variant as the transaction sees it. A nil
+// stock means the variant is unlimited.
+func (r *itemRepo) GetStock(ctx context.Context, tx *sqlx.Tx, variantID int) (*int, error) {
+	var stock *int
+	query := `
+		SELECT stock
+		FROM item_variants
+		WHERE id = $1 AND item_id IN (SELECT id FROM items WHERE $2 = 0 OR tenant_id = $2)
+		`
+	err := tx.GetContext(ctx, &stock, query, variantID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: failed to get variant stock: %w", err)
+	}
+	return stock, nil
+}
+
+func (r *itemRepo) SetArchived(ctx context.Context, tx *sqlx.Tx, itemID int, archived bool) error {
+	query := `UPDATE items SET archived_at = NULL WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)`
+	if archived {
+		query = `
+			UPDATE items
+			SET archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP)
+			WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)
+			`
+	}
+	_, err := tx.ExecContext(ctx, query, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: failed to update item archive state: %w", err)
+	}
+	return nil
+}
+
+func (r *itemRepo) SetPreorderEnabled(ctx context.Context, tx *sqlx.Tx, itemID int, enabled bool) error {
+	query := `UPDATE items SET preorder_enabled = $1 WHERE id = $2 AND ($3 = 0 OR tenant_id = $3)`
+	_, err := tx.ExecContext(ctx, query, enabled, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: failed to update item preorder state: %w", err)
+	}
+	return nil
+}
@@ -30,10 +30,10 @@ func TestItemRepo_GetAll(t *testing.T) {
 		{
 			name: "Items found",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "price"}).
-					AddRow(1, "sword", 100).
-					AddRow(2, "shield", 200)
-				mock.ExpectQuery(`SELECT id, name, price FROM items`).
+				rows := sqlmock.NewRows([]string{"id", "name", "price", "category", "preorder_enabled", "archived_at"}).
+					AddRow(1, "sword", 100, "", false, nil).
+					AddRow(2, "shield", 200, "", false, nil)
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items`).
 					WillReturnRows(rows)
 			},
 			expected: []models.Item{
@@ -45,8 +45,8 @@ func TestItemRepo_GetAll(t *testing.T) {
 		{
 			name: "No items found",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "price"})
-				mock.ExpectQuery(`SELECT id, name, price FROM items`).
+				rows := sqlmock.NewRows([]string{"id", "name", "price", "category", "preorder_enabled", "archived_at"})
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items`).
 					WillReturnRows(rows)
 			},
 			expected:    nil,
@@ -55,7 +55,7 @@ func TestItemRepo_GetAll(t *testing.T) {
 		{
 			name: "Database error",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, name, price FROM items`).
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items`).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    nil,
@@ -105,10 +105,10 @@ func TestItemRepo_GetItemByName(t *testing.T) {
 			name:     "Item found",
 			itemName: "sword",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "price"}).
-					AddRow(1, "sword", 100)
-				mock.ExpectQuery(`SELECT id, name, price FROM items WHERE name = \$1`).
-					WithArgs("sword").
+				rows := sqlmock.NewRows([]string{"id", "name", "price", "category", "preorder_enabled", "archived_at"}).
+					AddRow(1, "sword", 100, "", false, nil)
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items WHERE name = \$1`).
+					WithArgs("sword", models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    &models.Item{ID: 1, Name: "sword", Price: 100},
@@ -118,9 +118,9 @@ func TestItemRepo_GetItemByName(t *testing.T) {
 			name:     "Item not found",
 			itemName: "nonexistent_item",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "name", "price"})
-				mock.ExpectQuery(`SELECT id, name, price FROM items WHERE name = \$1`).
-					WithArgs("nonexistent_item").
+				rows := sqlmock.NewRows([]string{"id", "name", "price", "category", "preorder_enabled", "archived_at"})
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items WHERE name = \$1`).
+					WithArgs("nonexistent_item", models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    nil,
@@ -130,8 +130,8 @@ func TestItemRepo_GetItemByName(t *testing.T) {
 			name:     "Database error",
 			itemName: "sword",
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectQuery(`SELECT id, name, price FROM items WHERE name = \$1`).
-					WithArgs("sword").
+				mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items WHERE name = \$1`).
+					WithArgs("sword", models.DefaultTenantID).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    nil,
@@ -160,3 +160,26 @@ func TestItemRepo_GetItemByName(t *testing.T) {
 		})
 	}
 }
+
+func TestItemRepo_GetItemByName_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`SELECT id, name, price, category, preorder_enabled, archived_at FROM items WHERE name = \$1 AND \(\$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs("sword", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "price", "category", "preorder_enabled", "archived_at"}))
+
+	repo := NewItemRepo(sqlxDB)
+	item, err := repo.GetItemByName(WithTenant(context.Background(), 2), "sword")
+	assert.NoError(t, err)
+	assert.Nil(t, item)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
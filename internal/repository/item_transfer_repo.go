@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type ItemTransferRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, transfer *models.ItemTransfer) error
+}
+
+type itemTransferRepo struct {
+	db *sqlx.DB
+}
+
+func NewItemTransferRepo(db *sqlx.DB) ItemTransferRepo {
+	return &itemTransferRepo{db: db}
+}
+
+// Create records the transfer. Both users must belong to the tenant in ctx.
+func (r *itemTransferRepo) Create(ctx context.Context, tx *sqlx.Tx, transfer *models.ItemTransfer) error {
+	query := `
+		INSERT INTO item_transfers (transaction_id, sender_id, receiver_id, item_id, variant_id, quantity)
+		SELECT $1, $2, $3, $4, $5, $6
+		 WHERE $7 = 0 OR NOT EXISTS (
+		           SELECT 1 FROM users WHERE id IN ($2, $3) AND tenant_id <> $7
+		       )
+		RETURNING id
+		`
+	err := tx.QueryRowContext(ctx, query,
+		transfer.TransactionID, transfer.SenderID, transfer.ReceiverID,
+		transfer.ItemID, transfer.VariantID, transfer.Quantity, TenantFromContext(ctx)).Scan(&transfer.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("repository: cannot create item transfer: %w", ErrCrossTenant)
+		}
+		return fmt.Errorf("repository: cannot create item transfer: %w", err)
+	}
+	return nil
+}
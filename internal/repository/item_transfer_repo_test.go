@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemTransferRepo_Create_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT 1 FROM users WHERE id IN \(\$2, \$3\) AND tenant_id <> \$7`).
+		WithArgs(3, 5, 6, 10, 12, 1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	transfer := &models.ItemTransfer{TransactionID: 3, SenderID: 5, ReceiverID: 6, ItemID: 10, VariantID: 12, Quantity: 1}
+	repo := NewItemTransferRepo(sqlxDB)
+	err = repo.Create(WithTenant(context.Background(), 2), tx, transfer)
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type NotificationRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, notification *models.Notification) error
+	GetUnread(ctx context.Context, userID int) ([]models.Notification, error)
+	MarkAllRead(ctx context.Context, userID int) error
+}
+
+type notificationRepo struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepo(db *sqlx.DB) NotificationRepo {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) Create(ctx context.Context, tx *sqlx.Tx, notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, type, message)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		notification.UserID, notification.Type, notification.Message).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create notification: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepo) GetUnread(ctx context.Context, userID int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := `
+		SELECT id, user_id, type, message, created_at
+		  FROM notifications
+		 WHERE user_id = $1 AND read_at IS NULL
+		   AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &notifications, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepo) MarkAllRead(ctx context.Context, userID int) error {
+	query := `
+		UPDATE notifications
+		   SET read_at = CURRENT_TIMESTAMP
+		 WHERE user_id = $1 AND read_at IS NULL
+		   AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		`
+	_, err := r.db.ExecContext(ctx, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: cannot mark notifications read: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationRepo_GetUnread_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM notifications WHERE user_id = \$1 AND read_at IS NULL `+
+		`AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "message", "created_at"}))
+
+	repo := NewNotificationRepo(sqlxDB)
+	notifications, err := repo.GetUnread(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestNotificationRepo_MarkAllRead_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectExec(`UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = \$1 AND read_at IS NULL `+
+		`AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewNotificationRepo(sqlxDB)
+	err = repo.MarkAllRead(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type OrderRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, order *models.Order) error
+	GetByID(ctx context.Context, orderID int) (*models.Order, error)
+	GetByUserID(ctx context.Context, userID int) ([]models.Order, error)
+	GetByStatus(ctx context.Context, status string) ([]models.Order, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.Order, error)
+	AddRefundedQuantity(ctx context.Context, tx *sqlx.Tx, orderID int, quantity int) error
+	UpdateStatus(ctx context.Context, tx *sqlx.Tx, orderID int, status string) error
+	UpdateDelivery(ctx context.Context, tx *sqlx.Tx, orderID int, delivery models.Delivery) error
+	GetPickList(ctx context.Context) ([]models.PickListEntry, error)
+}
+
+type orderRepo struct {
+	db *sqlx.DB
+}
+
+func NewOrderRepo(db *sqlx.DB) OrderRepo {
+	return &orderRepo{db: db}
+}
+
+const orderColumns = `id, user_id, recipient_id, item_id, variant_id, transaction_id, quantity, refunded_quantity,
+		       unit_price, list_total, discount, total, promo_code_id, message, status, delivery_method, office, shipping_address,
+		       created_at, updated_at`
+
+func (r *orderRepo) Create(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+	if order.Status == "" {
+		order.Status = models.OrderStatusPlaced
+	}
+	if order.DeliveryMethod == "" {
+		order.DeliveryMethod = models.DeliveryMethodPickup
+	}
+
+	query := `
+		INSERT INTO orders (user_id, recipient_id, item_id, variant_id, transaction_id,
+		                    quantity, unit_price, list_total, discount, total, promo_code_id,
+		                    message, status, delivery_method)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id, created_at, updated_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		order.UserID, order.RecipientID, order.ItemID, order.VariantID, order.TransactionID,
+		order.Quantity, order.UnitPrice, order.ListTotal, order.Discount, order.Total, order.PromoCodeID,
+		order.Message, order.Status, order.DeliveryMethod).
+		Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create order: %w", err)
+	}
+	return nil
+}
+
+// GetByUserID returns the orders the user placed and the gifts bought for
+// the user.
+func (r *orderRepo) GetByUserID(ctx context.Context, userID int) ([]models.Order, error) {
+	var orders []models.Order
+	query := `
+		SELECT ` + orderColumns + `
+		  FROM orders
+		 WHERE (user_id = $1 OR recipient_id = $1)
+		   AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &orders, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (r *orderRepo) GetByStatus(ctx context.Context, status string) ([]models.Order, error) {
+	var orders []models.Order
+	query := `
+		SELECT ` + orderColumns + `
+		  FROM orders
+		 WHERE status = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &orders, query, status, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get orders by status: %w", err)
+	}
+	return orders, nil
+}
+
+func (r *orderRepo) GetByID(ctx context.Context, orderID int) (*models.Order, error) {
+	var order models.Order
+	query := `
+		SELECT ` + orderColumns + `
+		  FROM orders
+		 WHERE id = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		`
+	err := r.db.GetContext(ctx, &order, query, orderID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get order by id: %w", err)
+	}
+	return &order, nil
+}
+
+func (r *orderRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.Order, error) {
+	var order models.Order
+	query := `
+		SELECT ` + orderColumns + `
+		  FROM orders
+		 WHERE id = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		   FOR UPDATE
+		`
+	err := tx.GetContext(ctx, &order, query, orderID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock order: %w", err)
+	}
+	return &order, nil
+}
+
+func (r *orderRepo) AddRefundedQuantity(ctx context.Context, tx *sqlx.Tx, orderID int, quantity int) error {
+	query := `UPDATE orders SET refunded_quantity = refunded_quantity + $1 WHERE id = $2`
+	_, err := tx.ExecContext(ctx, query, quantity, orderID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update refunded quantity: %w", err)
+	}
+	return nil
+}
+
+func (r *orderRepo) UpdateStatus(ctx context.Context, tx *sqlx.Tx, orderID int, status string) error {
+	query := `UPDATE orders SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	_, err := tx.ExecContext(ctx, query, status, orderID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update order status: %w", err)
+	}
+	return nil
+}
+
+func (r *orderRepo) UpdateDelivery(ctx context.Context, tx *sqlx.Tx, orderID int, delivery models.Delivery) error {
+	query := `
+		UPDATE orders
+		   SET delivery_method = $1, office = $2, shipping_address = $3, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $4
+		`
+	_, err := tx.ExecContext(ctx, query, delivery.Method, delivery.Office, delivery.ShippingAddress, orderID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update order delivery: %w", err)
+	}
+	return nil
+}
+
+// GetPickList returns the approved orders that still have to be picked from
+// the warehouse, grouped by pickup office. Orders to be shipped are grouped
+// under the "shipping" pseudo-office.
+func (r *orderRepo) GetPickList(ctx context.Context) ([]models.PickListEntry, error) {
+	var entries []models.PickListEntry
+	query := `
+		SELECT CASE WHEN o.delivery_method = 'shipping' THEN 'shipping' ELSE o.office END AS office,
+		       o.id AS order_id, u.username, i.name AS item, v.size, v.color,
+		       o.quantity - o.refunded_quantity AS quantity, o.shipping_address
+		  FROM orders o
+		  JOIN users u ON u.id = o.recipient_id
+		  JOIN items i ON i.id = o.item_id
+		  JOIN item_variants v ON v.id = o.variant_id
+		 WHERE o.status = 'approved' AND o.quantity > o.refunded_quantity AND ($1 = 0 OR i.tenant_id = $1)
+		 ORDER BY office, i.name, v.size, v.color, o.id
+		`
+	err := r.db.SelectContext(ctx, &entries, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get pick list: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderRepo_GetByID_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM orders WHERE id = \$1 AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(12, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	repo := NewOrderRepo(sqlxDB)
+	order, err := repo.GetByID(WithTenant(context.Background(), 2), 12)
+	assert.NoError(t, err)
+	assert.Nil(t, order)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestOrderRepo_GetByUserID_IncludesGifts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM orders WHERE \(user_id = \$1 OR recipient_id = \$1\) `+
+		`AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(2, models.DefaultTenantID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "recipient_id"}).AddRow(12, 1, 2))
+
+	repo := NewOrderRepo(sqlxDB)
+	orders, err := repo.GetByUserID(context.Background(), 2)
+	assert.NoError(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, 1, orders[0].UserID)
+		assert.Equal(t, 2, orders[0].RecipientID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
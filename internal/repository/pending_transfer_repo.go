@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type PendingTransferRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer) error
+	GetIncoming(ctx context.Context, receiverID int) ([]models.PendingTransfer, error)
+	GetOutgoing(ctx context.Context, senderID int) ([]models.PendingTransfer, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, transferID int) (*models.PendingTransfer, error)
+	GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error)
+	Resolve(ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer) error
+	GetReservedAmount(ctx context.Context, senderID int) (int, error)
+}
+
+type pendingTransferRepo struct {
+	db *sqlx.DB
+}
+
+func NewPendingTransferRepo(db *sqlx.DB) PendingTransferRepo {
+	return &pendingTransferRepo{db: db}
+}
+
+const pendingTransferColumns = `pt.id, pt.sender_id, su.username AS sender, pt.receiver_id, ru.username AS receiver,
+		       pt.amount, pt.status, pt.expires_at, pt.transaction_id, pt.batch_id, pt.created_at,
+		       pt.resolved_at`
+
+const pendingTransferJoins = `
+		  JOIN users su ON su.id = pt.sender_id
+		  JOIN users ru ON ru.id = pt.receiver_id`
+
+func (r *pendingTransferRepo) Create(ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer) error {
+	query := `
+		INSERT INTO pending_transfers (sender_id, receiver_id, amount, status, expires_at, batch_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		transfer.SenderID, transfer.ReceiverID, transfer.Amount, transfer.Status, transfer.ExpiresAt,
+		transfer.BatchID).
+		Scan(&transfer.ID, &transfer.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create pending transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *pendingTransferRepo) GetIncoming(ctx context.Context, receiverID int) ([]models.PendingTransfer, error) {
+	var transfers []models.PendingTransfer
+	query := `
+		SELECT ` + pendingTransferColumns + `
+		  FROM pending_transfers pt` + pendingTransferJoins + `
+		 WHERE pt.receiver_id = $1 AND pt.status = $2 AND ($3 = 0 OR ru.tenant_id = $3)
+		 ORDER BY pt.id
+		`
+	err := r.db.SelectContext(ctx, &transfers, query,
+		receiverID, models.PendingTransferStatusPending, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get incoming transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (r *pendingTransferRepo) GetOutgoing(ctx context.Context, senderID int) ([]models.PendingTransfer, error) {
+	var transfers []models.PendingTransfer
+	query := `
+		SELECT ` + pendingTransferColumns + `
+		  FROM pending_transfers pt` + pendingTransferJoins + `
+		 WHERE pt.sender_id = $1 AND pt.status IN ($2, $3) AND ($4 = 0 OR su.tenant_id = $4)
+		 ORDER BY pt.id
+		`
+	err := r.db.SelectContext(ctx, &transfers, query,
+		senderID, models.PendingTransferStatusPending, models.PendingTransferStatusReview, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get outgoing transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+func (r *pendingTransferRepo) GetByIDForUpdate(
+	ctx context.Context, tx *sqlx.Tx, transferID int) (*models.PendingTransfer, error) {
+	var transfer models.PendingTransfer
+	query := `
+		SELECT ` + pendingTransferColumns + `
+		  FROM pending_transfers pt` + pendingTransferJoins + `
+		 WHERE pt.id = $1 AND ($2 = 0 OR su.tenant_id = $2)
+		   FOR UPDATE OF pt
+		`
+	err := tx.GetContext(ctx, &transfer, query, transferID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock pending transfer: %w", err)
+	}
+	return &transfer, nil
+}
+
+func (r *pendingTransferRepo) GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT id
+		  FROM pending_transfers
+		 WHERE status = $1 AND expires_at <= $2
+		   AND sender_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY expires_at, id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, models.PendingTransferStatusPending, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get expired transfers: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *pendingTransferRepo) Resolve(ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer) error {
+	query := `
+		UPDATE pending_transfers
+		   SET status = $1, transaction_id = $2, resolved_at = CURRENT_TIMESTAMP
+		 WHERE id = $3
+		RETURNING resolved_at
+		`
+	err := tx.QueryRowContext(ctx, query, transfer.Status, transfer.TransactionID, transfer.ID).
+		Scan(&transfer.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot resolve pending transfer: %w", err)
+	}
+	return nil
+}
+
+// GetReservedAmount returns the coins the user has sent that are still
+// waiting for the receiver or for a review.
+func (r *pendingTransferRepo) GetReservedAmount(ctx context.Context, senderID int) (int, error) {
+	var amount int
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		  FROM pending_transfers
+		 WHERE sender_id = $1 AND status IN ($2, $3)
+		   AND sender_id IN (SELECT id FROM users WHERE $4 = 0 OR tenant_id = $4)
+		`
+	err := r.db.GetContext(ctx, &amount, query,
+		senderID, models.PendingTransferStatusPending, models.PendingTransferStatusReview, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get reserved transfer amount: %w", err)
+	}
+	return amount, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingTransferRepo_GetIncoming_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`WHERE pt\.receiver_id = \$1 AND pt\.status = \$2 AND \(\$3 = 0 OR ru\.tenant_id = \$3\)`).
+		WithArgs(5, models.PendingTransferStatusPending, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id"}))
+
+	repo := NewPendingTransferRepo(sqlxDB)
+	transfers, err := repo.GetIncoming(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, transfers)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPendingTransferRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE pt\.id = \$1 AND \(\$2 = 0 OR su\.tenant_id = \$2\) FOR UPDATE OF pt`).
+		WithArgs(4, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewPendingTransferRepo(sqlxDB)
+	transfer, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, transfer)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type PoolRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, pool *models.Pool) error
+	GetOpen(ctx context.Context) ([]models.Pool, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, poolID int) (*models.Pool, error)
+	GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error)
+	Close(ctx context.Context, tx *sqlx.Tx, pool *models.Pool) error
+	CreateContribution(ctx context.Context, tx *sqlx.Tx, contribution *models.PoolContribution) error
+	GetHeldContributions(ctx context.Context, tx *sqlx.Tx, poolID int) ([]models.PoolContribution, error)
+	GetSpentContributionsByOrder(ctx context.Context, tx *sqlx.Tx, orderID int) ([]models.PoolContribution, error)
+	ResolveContribution(ctx context.Context, tx *sqlx.Tx, contribution *models.PoolContribution) error
+	GetReservedAmount(ctx context.Context, userID int) (int, error)
+}
+
+type poolRepo struct {
+	db *sqlx.DB
+}
+
+func NewPoolRepo(db *sqlx.DB) PoolRepo {
+	return &poolRepo{db: db}
+}
+
+const poolColumns = `p.id, p.title, p.item_id, p.variant_id, i.name AS item, v.size, v.color, p.target,
+		       p.raised, p.deadline, p.status, p.creator_id, u.username AS creator, p.order_id,
+		       p.created_at, p.closed_at`
+
+const poolJoins = `
+		  JOIN items i ON i.id = p.item_id
+		  JOIN item_variants v ON v.id = p.variant_id
+		  JOIN users u ON u.id = p.creator_id`
+
+func (r *poolRepo) Create(ctx context.Context, tx *sqlx.Tx, pool *models.Pool) error {
+	query := `
+		INSERT INTO pools (title, item_id, variant_id, target, deadline, status, creator_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		pool.Title, pool.ItemID, pool.VariantID, pool.Target, pool.Deadline, pool.Status, pool.CreatorID).
+		Scan(&pool.ID, &pool.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create pool: %w", err)
+	}
+	return nil
+}
+
+func (r *poolRepo) GetOpen(ctx context.Context) ([]models.Pool, error) {
+	var pools []models.Pool
+	query := `
+		SELECT ` + poolColumns + `
+		  FROM pools p` + poolJoins + `
+		 WHERE p.status = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		 ORDER BY p.deadline, p.id
+		`
+	err := r.db.SelectContext(ctx, &pools, query, models.PoolStatusOpen, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get open pools: %w", err)
+	}
+	return pools, nil
+}
+
+func (r *poolRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, poolID int) (*models.Pool, error) {
+	var pool models.Pool
+	query := `
+		SELECT ` + poolColumns + `
+		  FROM pools p` + poolJoins + `
+		 WHERE p.id = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		   FOR UPDATE OF p
+		`
+	err := tx.GetContext(ctx, &pool, query, poolID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock pool: %w", err)
+	}
+	return &pool, nil
+}
+
+func (r *poolRepo) GetExpiredIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT id
+		  FROM pools
+		 WHERE status = $1 AND deadline <= $2
+		   AND item_id IN (SELECT id FROM items WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY deadline, id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, models.PoolStatusOpen, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get expired pools: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *poolRepo) Close(ctx context.Context, tx *sqlx.Tx, pool *models.Pool) error {
+	query := `
+		UPDATE pools
+		   SET status = $1, order_id = $2, closed_at = CURRENT_TIMESTAMP
+		 WHERE id = $3
+		RETURNING closed_at
+		`
+	err := tx.QueryRowContext(ctx, query, pool.Status, pool.OrderID, pool.ID).Scan(&pool.ClosedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot close pool: %w", err)
+	}
+	return nil
+}
+
+// CreateContribution records a held contribution and adds it to the pool's
+// raised amount.
+func (r *poolRepo) CreateContribution(ctx context.Context, tx *sqlx.Tx, contribution *models.PoolContribution) error {
+	query := `
+		INSERT INTO pool_contributions (pool_id, user_id, amount, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		contribution.PoolID, contribution.UserID, contribution.Amount, contribution.Status).
+		Scan(&contribution.ID, &contribution.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create pool contribution: %w", err)
+	}
+
+	query = `UPDATE pools SET raised = raised + $1 WHERE id = $2`
+	_, err = tx.ExecContext(ctx, query, contribution.Amount, contribution.PoolID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update pool raised amount: %w", err)
+	}
+	return nil
+}
+
+func (r *poolRepo) GetHeldContributions(
+	ctx context.Context, tx *sqlx.Tx, poolID int) ([]models.PoolContribution, error) {
+	var contributions []models.PoolContribution
+	query := `
+		SELECT id, pool_id, user_id, amount, status, transaction_id, created_at
+		  FROM pool_contributions
+		 WHERE pool_id = $1 AND status = $2
+		 ORDER BY id
+		`
+	err := tx.SelectContext(ctx, &contributions, query, poolID, models.ContributionStatusHeld)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get pool contributions: %w", err)
+	}
+	return contributions, nil
+}
+
+// GetSpentContributionsByOrder locks the contributions that paid for the
+// order of a funded pool. It returns nothing for an order not bought by a pool.
+func (r *poolRepo) GetSpentContributionsByOrder(
+	ctx context.Context, tx *sqlx.Tx, orderID int) ([]models.PoolContribution, error) {
+	var contributions []models.PoolContribution
+	query := `
+		SELECT c.id, c.pool_id, c.user_id, c.amount, c.status, c.transaction_id, c.created_at
+		  FROM pool_contributions c
+		  JOIN pools p ON p.id = c.pool_id
+		 WHERE p.order_id = $1 AND c.status = $2
+		 ORDER BY c.id
+		   FOR UPDATE OF c
+		`
+	err := tx.SelectContext(ctx, &contributions, query, orderID, models.ContributionStatusSpent)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get order pool contributions: %w", err)
+	}
+	return contributions, nil
+}
+
+func (r *poolRepo) ResolveContribution(ctx context.Context, tx *sqlx.Tx, contribution *models.PoolContribution) error {
+	query := `UPDATE pool_contributions SET status = $1, transaction_id = $2 WHERE id = $3`
+	_, err := tx.ExecContext(ctx, query, contribution.Status, contribution.TransactionID, contribution.ID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot resolve pool contribution: %w", err)
+	}
+	return nil
+}
+
+// GetReservedAmount returns the coins the user has held in open pools.
+func (r *poolRepo) GetReservedAmount(ctx context.Context, userID int) (int, error) {
+	var amount int
+	query := `SELECT COALESCE(SUM(amount), 0) FROM pool_contributions WHERE user_id = $1 AND status = $2`
+	err := r.db.GetContext(ctx, &amount, query, userID, models.ContributionStatusHeld)
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get reserved pool amount: %w", err)
+	}
+	return amount, nil
+}
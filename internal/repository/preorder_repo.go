@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type PreorderRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, preorder *models.Preorder) error
+	GetByUserID(ctx context.Context, userID int) ([]models.Preorder, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, preorderID int) (*models.Preorder, error)
+	GetPlacedByVariantForUpdate(ctx context.Context, tx *sqlx.Tx, variantID int) ([]models.Preorder, error)
+	Resolve(ctx context.Context, tx *sqlx.Tx, preorder *models.Preorder) error
+	GetReservedAmount(ctx context.Context, userID int) (int, error)
+}
+
+type preorderRepo struct {
+	db *sqlx.DB
+}
+
+func NewPreorderRepo(db *sqlx.DB) PreorderRepo {
+	return &preorderRepo{db: db}
+}
+
+const preorderColumns = `p.id, p.user_id, p.item_id, p.variant_id, i.name AS item, v.size, v.color,
+		       p.quantity, p.original_price, p.unit_price, p.amount, p.status, p.order_id,
+		       p.created_at, p.resolved_at`
+
+func (r *preorderRepo) Create(ctx context.Context, tx *sqlx.Tx, preorder *models.Preorder) error {
+	query := `
+		INSERT INTO preorders (user_id, item_id, variant_id, quantity, original_price, unit_price, amount, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		preorder.UserID, preorder.ItemID, preorder.VariantID, preorder.Quantity, preorder.OriginalPrice,
+		preorder.UnitPrice, preorder.Amount, preorder.Status).Scan(&preorder.ID, &preorder.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create preorder: %w", err)
+	}
+	return nil
+}
+
+func (r *preorderRepo) GetByUserID(ctx context.Context, userID int) ([]models.Preorder, error) {
+	var preorders []models.Preorder
+	query := `
+		SELECT ` + preorderColumns + `
+		  FROM preorders p
+		  JOIN items i ON i.id = p.item_id
+		  JOIN item_variants v ON v.id = p.variant_id
+		 WHERE p.user_id = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		 ORDER BY p.id
+		`
+	err := r.db.SelectContext(ctx, &preorders, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get preorders: %w", err)
+	}
+	return preorders, nil
+}
+
+func (r *preorderRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, preorderID int) (*models.Preorder, error) {
+	var preorder models.Preorder
+	query := `
+		SELECT ` + preorderColumns + `
+		  FROM preorders p
+		  JOIN items i ON i.id = p.item_id
+		  JOIN item_variants v ON v.id = p.variant_id
+		 WHERE p.id = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		   FOR UPDATE OF p
+		`
+	err := tx.GetContext(ctx, &preorder, query, preorderID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock preorder: %w", err)
+	}
+	return &preorder, nil
+}
+
+// GetPlacedByVariantForUpdate locks the open preorders of a variant in the
+// order they were placed.
+func (r *preorderRepo) GetPlacedByVariantForUpdate(
+	ctx context.Context, tx *sqlx.Tx, variantID int) ([]models.Preorder, error) {
+	var preorders []models.Preorder
+	query := `
+		SELECT ` + preorderColumns + `
+		  FROM preorders p
+		  JOIN items i ON i.id = p.item_id
+		  JOIN item_variants v ON v.id = p.variant_id
+		 WHERE p.variant_id = $1 AND p.status = $2 AND ($3 = 0 OR i.tenant_id = $3)
+		 ORDER BY p.id
+		   FOR UPDATE OF p
+		`
+	err := tx.SelectContext(ctx, &preorders, query, variantID, models.PreorderStatusPlaced, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot lock preorders: %w", err)
+	}
+	return preorders, nil
+}
+
+func (r *preorderRepo) Resolve(ctx context.Context, tx *sqlx.Tx, preorder *models.Preorder) error {
+	query := `
+		UPDATE preorders
+		   SET status = $1, order_id = $2, resolved_at = CURRENT_TIMESTAMP
+		 WHERE id = $3
+		RETURNING resolved_at
+		`
+	err := tx.QueryRowContext(ctx, query, preorder.Status, preorder.OrderID, preorder.ID).Scan(&preorder.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot resolve preorder: %w", err)
+	}
+	return nil
+}
+
+// GetReservedAmount returns the coins the user has held in open preorders.
+func (r *preorderRepo) GetReservedAmount(ctx context.Context, userID int) (int, error) {
+	var amount int
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		  FROM preorders
+		 WHERE user_id = $1 AND status = $2 AND user_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		`
+	err := r.db.GetContext(ctx, &amount, query, userID, models.PreorderStatusPlaced, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get reserved amount: %w", err)
+	}
+	return amount, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreorderRepo_GetByUserID_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`WHERE p\.user_id = \$1 AND \(\$2 = 0 OR i\.tenant_id = \$2\)`).
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "item_id"}))
+
+	repo := NewPreorderRepo(sqlxDB)
+	preorders, err := repo.GetByUserID(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, preorders)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPreorderRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE p\.id = \$1 AND \(\$2 = 0 OR i\.tenant_id = \$2\) FOR UPDATE OF p`).
+		WithArgs(4, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "item_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewPreorderRepo(sqlxDB)
+	preorder, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 4)
+	assert.NoError(t, err)
+	assert.Nil(t, preorder)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPreorderRepo_GetReservedAmount_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`user_id IN \(SELECT id FROM users WHERE \$3 = 0 OR tenant_id = \$3\)`).
+		WithArgs(5, models.PreorderStatusPlaced, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+
+	repo := NewPreorderRepo(sqlxDB)
+	amount, err := repo.GetReservedAmount(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Zero(t, amount)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type PriceRuleRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, rule *models.PriceRule) error
+	GetAll(ctx context.Context) ([]models.PriceRule, error)
+	GetActive(ctx context.Context, at time.Time) ([]models.PriceRule, error)
+	Delete(ctx context.Context, tx *sqlx.Tx, ruleID int) (bool, error)
+	RecordHistory(ctx context.Context, tx *sqlx.Tx, entry *models.PriceHistory) error
+	GetHistory(ctx context.Context, itemID int) ([]models.PriceHistory, error)
+}
+
+type priceRuleRepo struct {
+	db *sqlx.DB
+}
+
+func NewPriceRuleRepo(db *sqlx.DB) PriceRuleRepo {
+	return &priceRuleRepo{db: db}
+}
+
+const priceRuleColumns = `id, item_id, category, price, discount_percent, starts_at, ends_at, created_at`
+
+func (r *priceRuleRepo) Create(ctx context.Context, tx *sqlx.Tx, rule *models.PriceRule) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot create price rule: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO price_rules (item_id, category, price, discount_percent, starts_at, ends_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		rule.ItemID, rule.Category, rule.Price, rule.DiscountPercent, rule.StartsAt, rule.EndsAt, tenantID).
+		Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create price rule: %w", err)
+	}
+	return nil
+}
+
+func (r *priceRuleRepo) GetAll(ctx context.Context) ([]models.PriceRule, error) {
+	var rules []models.PriceRule
+	query := `
+		SELECT ` + priceRuleColumns + `
+		  FROM price_rules
+		 WHERE $1 = 0 OR tenant_id = $1
+		 ORDER BY starts_at, id
+		`
+	err := r.db.SelectContext(ctx, &rules, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get price rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *priceRuleRepo) GetActive(ctx context.Context, at time.Time) ([]models.PriceRule, error) {
+	var rules []models.PriceRule
+	query := `
+		SELECT ` + priceRuleColumns + `
+		  FROM price_rules
+		 WHERE starts_at <= $1 AND ends_at > $1 AND ($2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &rules, query, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get active price rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *priceRuleRepo) Delete(ctx context.Context, tx *sqlx.Tx, ruleID int) (bool, error) {
+	query := `DELETE FROM price_rules WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)`
+	res, err := tx.ExecContext(ctx, query, ruleID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot delete price rule: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot delete price rule: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *priceRuleRepo) RecordHistory(ctx context.Context, tx *sqlx.Tx, entry *models.PriceHistory) error {
+	query := `
+		INSERT INTO price_history (order_id, user_id, item_id, variant_id, original_price, sale_price,
+		                           price_rule_id, promo_discount, charged)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		entry.OrderID, entry.UserID, entry.ItemID, entry.VariantID, entry.OriginalPrice, entry.SalePrice,
+		entry.PriceRuleID, entry.PromoDiscount, entry.Charged).Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot record price history: %w", err)
+	}
+	return nil
+}
+
+func (r *priceRuleRepo) GetHistory(ctx context.Context, itemID int) ([]models.PriceHistory, error) {
+	var history []models.PriceHistory
+	query := `
+		SELECT id, order_id, user_id, item_id, variant_id, original_price, sale_price,
+		       price_rule_id, promo_discount, charged, created_at
+		  FROM price_history
+		 WHERE item_id = $1 AND item_id IN (SELECT id FROM items WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &history, query, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get price history: %w", err)
+	}
+	return history, nil
+}
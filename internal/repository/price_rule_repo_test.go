@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceRuleRepo_GetAll_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM price_rules WHERE \$1 = 0 OR tenant_id = \$1 ORDER BY starts_at, id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "category", "price", "discount_percent",
+			"starts_at", "ends_at", "created_at"}))
+
+	repo := NewPriceRuleRepo(sqlxDB)
+	rules, err := repo.GetAll(WithTenant(context.Background(), 2))
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPriceRuleRepo_GetActive_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	at := time.Now()
+
+	mock.ExpectQuery(`WHERE starts_at <= \$1 AND ends_at > \$1 AND \(\$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(at, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "item_id", "category", "price", "discount_percent",
+			"starts_at", "ends_at", "created_at"}))
+
+	repo := NewPriceRuleRepo(sqlxDB)
+	rules, err := repo.GetActive(WithTenant(context.Background(), 2), at)
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPriceRuleRepo_Delete_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM price_rules WHERE id = \$1 AND \(\$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(4, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewPriceRuleRepo(sqlxDB)
+	deleted, err := repo.Delete(WithTenant(context.Background(), 2), tx, 4)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPriceRuleRepo_Create_AllTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	category := "apparel"
+	repo := NewPriceRuleRepo(sqlxDB)
+	err = repo.Create(WithAllTenants(context.Background()), tx, &models.PriceRule{Category: &category})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
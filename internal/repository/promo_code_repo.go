@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type PromoCodeRepo interface {
+	Create(ctx context.Context, promo *models.PromoCode) error
+	GetAll(ctx context.Context) ([]models.PromoCode, error)
+	GetByCodeForUpdate(ctx context.Context, tx *sqlx.Tx, code string) (*models.PromoCode, error)
+	IncrementUsage(ctx context.Context, tx *sqlx.Tx, promoID int) error
+	CountUserRedemptions(ctx context.Context, tx *sqlx.Tx, promoID int, userID int) (int, error)
+	CreateRedemption(ctx context.Context, tx *sqlx.Tx, redemption *models.PromoRedemption) error
+}
+
+type promoCodeRepo struct {
+	db *sqlx.DB
+}
+
+func NewPromoCodeRepo(db *sqlx.DB) PromoCodeRepo {
+	return &promoCodeRepo{db: db}
+}
+
+const promoCodeColumns = `id, code, discount_type, discount_value, item_ids, categories, max_uses,
+		       per_user_limit, used_count, starts_at, ends_at, created_at`
+
+func (r *promoCodeRepo) Create(ctx context.Context, promo *models.PromoCode) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot create promo code: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO promo_codes (code, discount_type, discount_value, item_ids, categories,
+		                         max_uses, per_user_limit, starts_at, ends_at, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query,
+		promo.Code, promo.DiscountType, promo.DiscountValue, promo.ItemIDs, promo.Categories,
+		promo.MaxUses, promo.PerUserLimit, promo.StartsAt, promo.EndsAt, tenantID).Scan(&promo.ID, &promo.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create promo code: %w", err)
+	}
+	return nil
+}
+
+func (r *promoCodeRepo) GetAll(ctx context.Context) ([]models.PromoCode, error) {
+	var promos []models.PromoCode
+	query := `SELECT ` + promoCodeColumns + ` FROM promo_codes WHERE $1 = 0 OR tenant_id = $1 ORDER BY id`
+	err := r.db.SelectContext(ctx, &promos, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get promo codes: %w", err)
+	}
+	return promos, nil
+}
+
+// GetByCodeForUpdate locks the promo code of the context's tenant so that
+// usage caps hold under concurrent purchases.
+func (r *promoCodeRepo) GetByCodeForUpdate(ctx context.Context, tx *sqlx.Tx, code string) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	query := `
+		SELECT ` + promoCodeColumns + `
+		  FROM promo_codes
+		 WHERE code = $1 AND ($2 = 0 OR tenant_id = $2)
+		   FOR UPDATE
+		`
+	err := tx.GetContext(ctx, &promo, query, code, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock promo code: %w", err)
+	}
+	return &promo, nil
+}
+
+func (r *promoCodeRepo) IncrementUsage(ctx context.Context, tx *sqlx.Tx, promoID int) error {
+	query := `UPDATE promo_codes SET used_count = used_count + 1 WHERE id = $1`
+	_, err := tx.ExecContext(ctx, query, promoID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update promo code usage: %w", err)
+	}
+	return nil
+}
+
+func (r *promoCodeRepo) CountUserRedemptions(
+	ctx context.Context, tx *sqlx.Tx, promoID int, userID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM promo_redemptions WHERE promo_code_id = $1 AND user_id = $2`
+	err := tx.GetContext(ctx, &count, query, promoID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot count promo redemptions: %w", err)
+	}
+	return count, nil
+}
+
+func (r *promoCodeRepo) CreateRedemption(ctx context.Context, tx *sqlx.Tx, redemption *models.PromoRedemption) error {
+	query := `
+		INSERT INTO promo_redemptions (promo_code_id, user_id, order_id, discount)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		redemption.PromoCodeID, redemption.UserID, redemption.OrderID, redemption.Discount).
+		Scan(&redemption.ID, &redemption.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create promo redemption: %w", err)
+	}
+	return nil
+}
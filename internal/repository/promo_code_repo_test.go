@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromoCodeRepo_GetAll_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM promo_codes WHERE \$1 = 0 OR tenant_id = \$1 ORDER BY id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code"}))
+
+	repo := NewPromoCodeRepo(sqlxDB)
+	promos, err := repo.GetAll(WithTenant(context.Background(), 2))
+	assert.NoError(t, err)
+	assert.Empty(t, promos)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPromoCodeRepo_GetByCodeForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM promo_codes WHERE code = \$1 AND \(\$2 = 0 OR tenant_id = \$2\) FOR UPDATE`).
+		WithArgs("WELCOME", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "code"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewPromoCodeRepo(sqlxDB)
+	promo, err := repo.GetByCodeForUpdate(WithTenant(context.Background(), 2), tx, "WELCOME")
+	assert.NoError(t, err)
+	assert.Nil(t, promo)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPromoCodeRepo_Create_AllTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	repo := NewPromoCodeRepo(sqlxDB)
+	err = repo.Create(WithAllTenants(context.Background()), &models.PromoCode{Code: "WELCOME"})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
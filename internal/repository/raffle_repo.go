@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type RaffleRepo interface {
+	Create(ctx context.Context, tx *sqlx.Tx, raffle *models.Raffle) error
+	GetAll(ctx context.Context) ([]models.Raffle, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, raffleID int) (*models.Raffle, error)
+	GetClosedIDs(ctx context.Context, at time.Time) ([]int, error)
+	CountUserTickets(ctx context.Context, tx *sqlx.Tx, raffleID int, userID int) (int, error)
+	CreateTicket(ctx context.Context, tx *sqlx.Tx, ticket *models.RaffleTicket) error
+	GetTickets(ctx context.Context, tx *sqlx.Tx, raffleID int) ([]models.RaffleTicket, error)
+	Draw(ctx context.Context, tx *sqlx.Tx, raffle *models.Raffle) error
+}
+
+type raffleRepo struct {
+	db *sqlx.DB
+}
+
+func NewRaffleRepo(db *sqlx.DB) RaffleRepo {
+	return &raffleRepo{db: db}
+}
+
+const raffleColumns = `r.id, r.title, r.ticket_price, r.max_tickets_per_user, r.closes_at, r.prize_item_id,
+		       r.prize_variant_id, i.name AS prize_item, v.size AS prize_size, v.color AS prize_color,
+		       r.prize_coins, r.seed_hash, r.seed, r.status, r.tickets_sold, r.winning_ticket, r.winner_id,
+		       u.username AS winner, r.created_by, r.created_at, r.drawn_at`
+
+const raffleJoins = `
+		  LEFT JOIN items i ON i.id = r.prize_item_id
+		  LEFT JOIN item_variants v ON v.id = r.prize_variant_id
+		  LEFT JOIN users u ON u.id = r.winner_id`
+
+func (r *raffleRepo) Create(ctx context.Context, tx *sqlx.Tx, raffle *models.Raffle) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot create raffle: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO raffles (title, ticket_price, max_tickets_per_user, closes_at, prize_item_id,
+		                     prize_variant_id, prize_coins, seed_hash, seed, status, created_by, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		raffle.Title, raffle.TicketPrice, raffle.MaxTicketsPerUser, raffle.ClosesAt, raffle.PrizeItemID,
+		raffle.PrizeVariantID, raffle.PrizeCoins, raffle.SeedHash, raffle.Seed, raffle.Status,
+		raffle.CreatedBy, tenantID).Scan(&raffle.ID, &raffle.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create raffle: %w", err)
+	}
+	return nil
+}
+
+func (r *raffleRepo) GetAll(ctx context.Context) ([]models.Raffle, error) {
+	var raffles []models.Raffle
+	query := `
+		SELECT ` + raffleColumns + `
+		  FROM raffles r` + raffleJoins + `
+		 WHERE $1 = 0 OR r.tenant_id = $1
+		 ORDER BY r.closes_at DESC, r.id DESC
+		`
+	err := r.db.SelectContext(ctx, &raffles, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get raffles: %w", err)
+	}
+	return raffles, nil
+}
+
+func (r *raffleRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, raffleID int) (*models.Raffle, error) {
+	var raffle models.Raffle
+	query := `
+		SELECT ` + raffleColumns + `
+		  FROM raffles r` + raffleJoins + `
+		 WHERE r.id = $1 AND ($2 = 0 OR r.tenant_id = $2)
+		   FOR UPDATE OF r
+		`
+	err := tx.GetContext(ctx, &raffle, query, raffleID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock raffle: %w", err)
+	}
+	return &raffle, nil
+}
+
+func (r *raffleRepo) GetClosedIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT id
+		  FROM raffles
+		 WHERE status = $1 AND closes_at <= $2 AND ($3 = 0 OR tenant_id = $3)
+		 ORDER BY closes_at, id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, models.RaffleStatusOpen, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get closed raffles: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *raffleRepo) CountUserTickets(ctx context.Context, tx *sqlx.Tx, raffleID int, userID int) (int, error) {
+	var count int
+	query := `SELECT COALESCE(SUM(quantity), 0) FROM raffle_tickets WHERE raffle_id = $1 AND user_id = $2`
+	err := tx.GetContext(ctx, &count, query, raffleID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot count raffle tickets: %w", err)
+	}
+	return count, nil
+}
+
+// CreateTicket records a ticket purchase and adds it to the raffle's sold
+// counter.
+func (r *raffleRepo) CreateTicket(ctx context.Context, tx *sqlx.Tx, ticket *models.RaffleTicket) error {
+	query := `
+		INSERT INTO raffle_tickets (raffle_id, user_id, quantity, transaction_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query, ticket.RaffleID, ticket.UserID, ticket.Quantity, ticket.TransactionID).
+		Scan(&ticket.ID, &ticket.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create raffle ticket: %w", err)
+	}
+
+	query = `UPDATE raffles SET tickets_sold = tickets_sold + $1 WHERE id = $2`
+	_, err = tx.ExecContext(ctx, query, ticket.Quantity, ticket.RaffleID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update raffle tickets: %w", err)
+	}
+	return nil
+}
+
+func (r *raffleRepo) GetTickets(ctx context.Context, tx *sqlx.Tx, raffleID int) ([]models.RaffleTicket, error) {
+	var tickets []models.RaffleTicket
+	query := `
+		SELECT id, raffle_id, user_id, quantity, transaction_id, created_at
+		  FROM raffle_tickets
+		 WHERE raffle_id = $1
+		 ORDER BY id
+		`
+	err := tx.SelectContext(ctx, &tickets, query, raffleID)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get raffle tickets: %w", err)
+	}
+	return tickets, nil
+}
+
+func (r *raffleRepo) Draw(ctx context.Context, tx *sqlx.Tx, raffle *models.Raffle) error {
+	query := `
+		UPDATE raffles
+		   SET status = $1, winning_ticket = $2, winner_id = $3, drawn_at = CURRENT_TIMESTAMP
+		 WHERE id = $4
+		RETURNING drawn_at
+		`
+	err := tx.QueryRowContext(ctx, query, raffle.Status, raffle.WinningTicket, raffle.WinnerID, raffle.ID).
+		Scan(&raffle.DrawnAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot draw raffle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReturnRepo interface {
+	Create(ctx context.Context, returnEntry *models.Return) error
+	GetByStatus(ctx context.Context, status string) ([]models.Return, error)
+	GetPendingQuantity(ctx context.Context, orderID int) (int, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, returnID int) (*models.Return, error)
+	Review(ctx context.Context, tx *sqlx.Tx, returnEntry *models.Return) error
+}
+
+type returnRepo struct {
+	db *sqlx.DB
+}
+
+func NewReturnRepo(db *sqlx.DB) ReturnRepo {
+	return &returnRepo{db: db}
+}
+
+func (r *returnRepo) Create(ctx context.Context, ret *models.Return) error {
+	query := `
+		INSERT INTO returns (order_id, user_id, quantity, reason, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query,
+		ret.OrderID, ret.UserID, ret.Quantity, ret.Reason, ret.Status).Scan(&ret.ID, &ret.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create return: %w", err)
+	}
+	return nil
+}
+
+func (r *returnRepo) GetByStatus(ctx context.Context, status string) ([]models.Return, error) {
+	var returns []models.Return
+	query := `
+		SELECT id, order_id, user_id, quantity, reason, status, reviewer_id,
+		       refund_amount, transaction_id, created_at, reviewed_at
+		  FROM returns
+		 WHERE status = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY id
+		`
+	err := r.db.SelectContext(ctx, &returns, query, status, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get returns: %w", err)
+	}
+	return returns, nil
+}
+
+func (r *returnRepo) GetPendingQuantity(ctx context.Context, orderID int) (int, error) {
+	var quantity int
+	query := `
+		SELECT COALESCE(SUM(quantity), 0)
+		  FROM returns
+		 WHERE order_id = $1 AND status = $2 AND user_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		`
+	err := r.db.GetContext(ctx, &quantity, query, orderID, models.ReturnStatusRequested, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get pending return quantity: %w", err)
+	}
+	return quantity, nil
+}
+
+func (r *returnRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, returnID int) (*models.Return, error) {
+	var ret models.Return
+	query := `
+		SELECT id, order_id, user_id, quantity, reason, status, reviewer_id,
+		       refund_amount, transaction_id, created_at, reviewed_at
+		  FROM returns
+		 WHERE id = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		   FOR UPDATE
+		`
+	err := tx.GetContext(ctx, &ret, query, returnID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock return: %w", err)
+	}
+	return &ret, nil
+}
+
+func (r *returnRepo) Review(ctx context.Context, tx *sqlx.Tx, ret *models.Return) error {
+	query := `
+		UPDATE returns
+		   SET status = $1, reviewer_id = $2, refund_amount = $3, transaction_id = $4,
+		       reviewed_at = CURRENT_TIMESTAMP
+		 WHERE id = $5
+		RETURNING reviewed_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		ret.Status, ret.ReviewerID, ret.RefundAmount, ret.TransactionID, ret.ID).Scan(&ret.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot review return: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnRepo_GetByStatus_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM returns WHERE status = \$1 AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(models.ReturnStatusRequested, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "user_id"}))
+
+	repo := NewReturnRepo(sqlxDB)
+	returns, err := repo.GetByStatus(WithTenant(context.Background(), 2), models.ReturnStatusRequested)
+	assert.NoError(t, err)
+	assert.Empty(t, returns)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReturnRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM returns WHERE id = \$1 AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\) FOR UPDATE`).
+		WithArgs(6, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "order_id", "user_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewReturnRepo(sqlxDB)
+	ret, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 6)
+	assert.NoError(t, err)
+	assert.Nil(t, ret)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReversalRepo interface {
+	GetTransactionForUpdate(ctx context.Context, tx *sqlx.Tx, transactionID int) (*models.Transaction, error)
+	LockBalance(ctx context.Context, tx *sqlx.Tx, userID int) (int, error)
+	HasPending(ctx context.Context, tx *sqlx.Tx, transactionID int) (bool, error)
+	Create(ctx context.Context, tx *sqlx.Tx, reversal *models.Reversal) error
+	GetAll(ctx context.Context) ([]models.Reversal, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, reversalID int) (*models.Reversal, error)
+	Resolve(ctx context.Context, tx *sqlx.Tx, reversal *models.Reversal) error
+}
+
+type reversalRepo struct {
+	db *sqlx.DB
+}
+
+func NewReversalRepo(db *sqlx.DB) ReversalRepo {
+	return &reversalRepo{db: db}
+}
+
+const reversalColumns = `r.id, r.transaction_id, r.reason, r.allow_negative, r.status, r.requested_by,
+		       qu.username AS requested_by_name, r.approved_by, au.username AS approved_by_name,
+		       r.reversal_transaction_id, su.username AS from_user, ru.username AS to_user, t.amount,
+		       r.created_at, r.resolved_at`
+
+const reversalJoins = `
+		  JOIN transactions t ON t.id = r.transaction_id
+		  JOIN users su ON su.id = t.sender_id
+		  JOIN users ru ON ru.id = t.receiver_id
+		  LEFT JOIN users qu ON qu.id = r.requested_by
+		  LEFT JOIN users au ON au.id = r.approved_by`
+
+// GetTransactionForUpdate locks the transaction so it is reversed only once
+// and fills ReversedBy when a reversal already exists.
+func (r *reversalRepo) GetTransactionForUpdate(
+	ctx context.Context, tx *sqlx.Tx, transactionID int) (*models.Transaction, error) {
+	var transaction models.Transaction
+	query := `
+		SELECT t.id, t.sender_id, t.receiver_id, t.amount, t.type, t.reference_id, t.batch_id, t.issuance_id,
+		       t.reversal_of, (SELECT rv.id FROM transactions rv WHERE rv.reversal_of = t.id) AS reversed_by
+		  FROM transactions t
+		 WHERE t.id = $1 AND ($2 = 0 OR t.tenant_id = $2)
+		   FOR UPDATE
+		`
+	err := tx.GetContext(ctx, &transaction, query, transactionID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock transaction: %w", err)
+	}
+	return &transaction, nil
+}
+
+func (r *reversalRepo) LockBalance(ctx context.Context, tx *sqlx.Tx, userID int) (int, error) {
+	var balance int
+	query := `SELECT balance FROM users WHERE id = $1 AND ($2 = 0 OR tenant_id = $2) FOR UPDATE`
+	err := tx.GetContext(ctx, &balance, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot lock balance: %w", err)
+	}
+	return balance, nil
+}
+
+func (r *reversalRepo) HasPending(ctx context.Context, tx *sqlx.Tx, transactionID int) (bool, error) {
+	var pending bool
+	query := `SELECT EXISTS (SELECT 1 FROM reversals WHERE transaction_id = $1 AND status = $2)`
+	err := tx.GetContext(ctx, &pending, query, transactionID, models.ReversalStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot check pending reversals: %w", err)
+	}
+	return pending, nil
+}
+
+// Create stores the reversal. A reversal created already completed is
+// resolved at once.
+func (r *reversalRepo) Create(ctx context.Context, tx *sqlx.Tx, reversal *models.Reversal) error {
+	query := `
+		INSERT INTO reversals (transaction_id, reason, allow_negative, status, requested_by, approved_by,
+		                       reversal_transaction_id, resolved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, CASE WHEN $4 = $8::VARCHAR THEN CURRENT_TIMESTAMP END)
+		RETURNING id, created_at, resolved_at
+		`
+	err := tx.QueryRowContext(ctx, query, reversal.TransactionID, reversal.Reason, reversal.AllowNegative,
+		reversal.Status, reversal.RequestedBy, reversal.ApprovedBy, reversal.ReversalTransactionID,
+		models.ReversalStatusCompleted).
+		Scan(&reversal.ID, &reversal.CreatedAt, &reversal.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create reversal: %w", err)
+	}
+	return nil
+}
+
+func (r *reversalRepo) GetAll(ctx context.Context) ([]models.Reversal, error) {
+	var reversals []models.Reversal
+	query := `
+		SELECT ` + reversalColumns + `
+		  FROM reversals r` + reversalJoins + `
+		 WHERE $1 = 0 OR t.tenant_id = $1
+		 ORDER BY r.id DESC
+		`
+	err := r.db.SelectContext(ctx, &reversals, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get reversals: %w", err)
+	}
+	return reversals, nil
+}
+
+func (r *reversalRepo) GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, reversalID int) (*models.Reversal, error) {
+	var reversal models.Reversal
+	query := `
+		SELECT ` + reversalColumns + `
+		  FROM reversals r` + reversalJoins + `
+		 WHERE r.id = $1 AND ($2 = 0 OR t.tenant_id = $2)
+		   FOR UPDATE OF r
+		`
+	err := tx.GetContext(ctx, &reversal, query, reversalID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock reversal: %w", err)
+	}
+	return &reversal, nil
+}
+
+func (r *reversalRepo) Resolve(ctx context.Context, tx *sqlx.Tx, reversal *models.Reversal) error {
+	query := `
+		UPDATE reversals
+		   SET status = $1, approved_by = $2, reversal_transaction_id = $3, resolved_at = CURRENT_TIMESTAMP
+		 WHERE id = $4
+		RETURNING resolved_at
+		`
+	err := tx.QueryRowContext(ctx, query,
+		reversal.Status, reversal.ApprovedBy, reversal.ReversalTransactionID, reversal.ID).
+		Scan(&reversal.ResolvedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot resolve reversal: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReversalRepo_GetAll_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`LEFT JOIN users au ON au\.id = r\.approved_by WHERE \$1 = 0 OR t\.tenant_id = \$1 ORDER BY r\.id DESC`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "reason"}))
+
+	repo := NewReversalRepo(sqlxDB)
+	reversals, err := repo.GetAll(WithTenant(context.Background(), 2))
+	assert.NoError(t, err)
+	assert.Empty(t, reversals)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestReversalRepo_ForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM transactions t WHERE t\.id = \$1 AND \(\$2 = 0 OR t\.tenant_id = \$2\) FOR UPDATE`).
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id"}))
+	mock.ExpectQuery(`WHERE r\.id = \$1 AND \(\$2 = 0 OR t\.tenant_id = \$2\) FOR UPDATE OF r`).
+		WithArgs(3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "transaction_id", "reason"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	ctx := WithTenant(context.Background(), 2)
+	repo := NewReversalRepo(sqlxDB)
+
+	transaction, err := repo.GetTransactionForUpdate(ctx, tx, 5)
+	assert.NoError(t, err)
+	assert.Nil(t, transaction)
+
+	reversal, err := repo.GetByIDForUpdate(ctx, tx, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, reversal)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
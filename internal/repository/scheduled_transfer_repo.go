@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type ScheduledTransferRepo interface {
+	Create(ctx context.Context, schedule *models.ScheduledTransfer) error
+	GetBySenderID(ctx context.Context, senderID int) ([]models.ScheduledTransfer, error)
+	GetDueIDs(ctx context.Context, at time.Time) ([]int, error)
+	GetByIDForUpdate(ctx context.Context, tx *sqlx.Tx, scheduleID int) (*models.ScheduledTransfer, error)
+	Update(ctx context.Context, tx *sqlx.Tx, schedule *models.ScheduledTransfer) error
+	Cancel(ctx context.Context, scheduleID int, senderID int) (bool, error)
+	CreateRun(ctx context.Context, tx *sqlx.Tx, run *models.ScheduledTransferRun) error
+	GetRuns(ctx context.Context, scheduleID int, senderID int) ([]models.ScheduledTransferRun, error)
+}
+
+type scheduledTransferRepo struct {
+	db *sqlx.DB
+}
+
+func NewScheduledTransferRepo(db *sqlx.DB) ScheduledTransferRepo {
+	return &scheduledTransferRepo{db: db}
+}
+
+const scheduledTransferColumns = `s.id, s.sender_id, s.receiver_id, u.username AS receiver, s.amount, s.cron,
+		       s.catch_up, s.next_run_at, s.status, s.failures, s.last_run_at, s.last_error, s.created_at`
+
+func (r *scheduledTransferRepo) Create(ctx context.Context, schedule *models.ScheduledTransfer) error {
+	query := `
+		INSERT INTO scheduled_transfers (sender_id, receiver_id, amount, cron, catch_up, next_run_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query,
+		schedule.SenderID, schedule.ReceiverID, schedule.Amount, schedule.Cron, schedule.CatchUp,
+		schedule.NextRunAt, schedule.Status).Scan(&schedule.ID, &schedule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create scheduled transfer: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduledTransferRepo) GetBySenderID(ctx context.Context, senderID int) ([]models.ScheduledTransfer, error) {
+	var schedules []models.ScheduledTransfer
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		  FROM scheduled_transfers s
+		  JOIN users u ON u.id = s.receiver_id
+		 WHERE s.sender_id = $1 AND s.status != $2 AND ($3 = 0 OR u.tenant_id = $3)
+		 ORDER BY s.id
+		`
+	err := r.db.SelectContext(ctx, &schedules, query, senderID, models.ScheduleStatusCancelled, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get scheduled transfers: %w", err)
+	}
+	return schedules, nil
+}
+
+func (r *scheduledTransferRepo) GetDueIDs(ctx context.Context, at time.Time) ([]int, error) {
+	var ids []int
+	query := `
+		SELECT id
+		  FROM scheduled_transfers
+		 WHERE status = $1 AND next_run_at <= $2
+		   AND sender_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY next_run_at, id
+		`
+	err := r.db.SelectContext(ctx, &ids, query, models.ScheduleStatusActive, at, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get due scheduled transfers: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *scheduledTransferRepo) GetByIDForUpdate(
+	ctx context.Context, tx *sqlx.Tx, scheduleID int) (*models.ScheduledTransfer, error) {
+	var schedule models.ScheduledTransfer
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		  FROM scheduled_transfers s
+		  JOIN users u ON u.id = s.receiver_id
+		 WHERE s.id = $1 AND ($2 = 0 OR u.tenant_id = $2)
+		   FOR UPDATE OF s
+		`
+	err := tx.GetContext(ctx, &schedule, query, scheduleID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock scheduled transfer: %w", err)
+	}
+	return &schedule, nil
+}
+
+func (r *scheduledTransferRepo) Update(ctx context.Context, tx *sqlx.Tx, schedule *models.ScheduledTransfer) error {
+	query := `
+		UPDATE scheduled_transfers
+		   SET next_run_at = $1, status = $2, failures = $3, last_run_at = $4, last_error = $5
+		 WHERE id = $6
+		`
+	_, err := tx.ExecContext(ctx, query, schedule.NextRunAt, schedule.Status, schedule.Failures,
+		schedule.LastRunAt, schedule.LastError, schedule.ID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot update scheduled transfer: %w", err)
+	}
+	return nil
+}
+
+// Cancel stops an active or paused schedule of the sender. It reports
+// whether a schedule was cancelled.
+func (r *scheduledTransferRepo) Cancel(ctx context.Context, scheduleID int, senderID int) (bool, error) {
+	query := `
+		UPDATE scheduled_transfers
+		   SET status = $1, next_run_at = NULL
+		 WHERE id = $2 AND sender_id = $3 AND status IN ($4, $5)
+		   AND sender_id IN (SELECT id FROM users WHERE $6 = 0 OR tenant_id = $6)
+		`
+	res, err := r.db.ExecContext(ctx, query, models.ScheduleStatusCancelled, scheduleID, senderID,
+		models.ScheduleStatusActive, models.ScheduleStatusPaused, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot cancel scheduled transfer: %w", err)
+	}
+
+	cancelled, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot cancel scheduled transfer: %w", err)
+	}
+	return cancelled > 0, nil
+}
+
+func (r *scheduledTransferRepo) CreateRun(ctx context.Context, tx *sqlx.Tx, run *models.ScheduledTransferRun) error {
+	query := `
+		INSERT INTO scheduled_transfer_runs (schedule_id, scheduled_for, status, error)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+		`
+	err := tx.QueryRowContext(ctx, query, run.ScheduleID, run.ScheduledFor, run.Status, run.Error).
+		Scan(&run.ID, &run.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create scheduled transfer run: %w", err)
+	}
+	return nil
+}
+
+func (r *scheduledTransferRepo) GetRuns(
+	ctx context.Context, scheduleID int, senderID int) ([]models.ScheduledTransferRun, error) {
+	var runs []models.ScheduledTransferRun
+	query := `
+		SELECT r.id, r.schedule_id, r.scheduled_for, r.status, r.error, r.created_at
+		  FROM scheduled_transfer_runs r
+		  JOIN scheduled_transfers s ON s.id = r.schedule_id
+		 WHERE r.schedule_id = $1 AND s.sender_id = $2
+		   AND s.sender_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		 ORDER BY r.id DESC
+		`
+	err := r.db.SelectContext(ctx, &runs, query, scheduleID, senderID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get scheduled transfer runs: %w", err)
+	}
+	return runs, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledTransferRepo_GetBySenderID_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`WHERE s\.sender_id = \$1 AND s\.status != \$2 AND \(\$3 = 0 OR u\.tenant_id = \$3\)`).
+		WithArgs(5, models.ScheduleStatusCancelled, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id"}))
+
+	repo := NewScheduledTransferRepo(sqlxDB)
+	schedules, err := repo.GetBySenderID(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, schedules)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestScheduledTransferRepo_GetByIDForUpdate_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE s\.id = \$1 AND \(\$2 = 0 OR u\.tenant_id = \$2\) FOR UPDATE OF s`).
+		WithArgs(3, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "sender_id", "receiver_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewScheduledTransferRepo(sqlxDB)
+	schedule, err := repo.GetByIDForUpdate(WithTenant(context.Background(), 2), tx, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, schedule)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
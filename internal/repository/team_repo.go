@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type TeamRepo interface {
+	Create(ctx context.Context, team *models.Team) (bool, error)
+	GetAll(ctx context.Context) ([]models.Team, error)
+	GetByID(ctx context.Context, teamID int) (*models.Team, error)
+	GetForUpdate(ctx context.Context, tx *sqlx.Tx, teamID int) (*models.Team, error)
+	SetBudget(ctx context.Context, teamID int, budget int) (bool, error)
+	GetMembers(ctx context.Context, teamID int) ([]models.TeamMember, error)
+	GetMemberRole(ctx context.Context, teamID int, userID int) (string, error)
+	SaveMember(ctx context.Context, member *models.TeamMember) error
+	RemoveMember(ctx context.Context, teamID int, userID int) (bool, error)
+	UpdatePot(ctx context.Context, tx *sqlx.Tx, teamID int, amount int) error
+	GetPaidOut(ctx context.Context, tx *sqlx.Tx, teamID int, since time.Time) (int, error)
+	GetBudget(ctx context.Context, teamID int, since time.Time) (*models.TeamBudget, error)
+	GetLeaderboard(ctx context.Context, from time.Time, to time.Time) ([]models.TeamStanding, error)
+}
+
+type teamRepo struct {
+	db *sqlx.DB
+}
+
+func NewTeamRepo(db *sqlx.DB) TeamRepo {
+	return &teamRepo{db: db}
+}
+
+const teamColumns = `t.id, t.name, t.department, t.pot, t.budget, t.created_at,
+		       (SELECT COUNT(*) FROM team_members m WHERE m.team_id = t.id) AS members`
+
+// Create stores the team and reports false when the name is taken.
+func (r *teamRepo) Create(ctx context.Context, team *models.Team) (bool, error) {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return false, fmt.Errorf("repository: cannot create team: %w", ErrCrossTenant)
+	}
+
+	query := `
+		INSERT INTO teams (name, department, budget, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, name) DO NOTHING
+		RETURNING id, pot, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query, team.Name, team.Department, team.Budget, tenantID).
+		Scan(&team.ID, &team.Pot, &team.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("repository: cannot create team: %w", err)
+	}
+	return true, nil
+}
+
+func (r *teamRepo) GetAll(ctx context.Context) ([]models.Team, error) {
+	var teams []models.Team
+	query := `
+		SELECT ` + teamColumns + `
+		  FROM teams t
+		 WHERE $1 = 0 OR t.tenant_id = $1
+		 ORDER BY t.department, t.name
+		`
+	err := r.db.SelectContext(ctx, &teams, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get teams: %w", err)
+	}
+	return teams, nil
+}
+
+func (r *teamRepo) GetByID(ctx context.Context, teamID int) (*models.Team, error) {
+	var team models.Team
+	query := `
+		SELECT ` + teamColumns + `
+		  FROM teams t
+		 WHERE t.id = $1 AND ($2 = 0 OR t.tenant_id = $2)
+		`
+	err := r.db.GetContext(ctx, &team, query, teamID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get team: %w", err)
+	}
+	return &team, nil
+}
+
+// GetForUpdate locks the team, so its pot changes one at a time.
+func (r *teamRepo) GetForUpdate(ctx context.Context, tx *sqlx.Tx, teamID int) (*models.Team, error) {
+	var team models.Team
+	query := `
+		SELECT ` + teamColumns + `
+		  FROM teams t
+		 WHERE t.id = $1 AND ($2 = 0 OR t.tenant_id = $2)
+		   FOR UPDATE
+		`
+	err := tx.GetContext(ctx, &team, query, teamID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot lock team: %w", err)
+	}
+	return &team, nil
+}
+
+func (r *teamRepo) SetBudget(ctx context.Context, teamID int, budget int) (bool, error) {
+	query := `UPDATE teams SET budget = $1 WHERE id = $2 AND ($3 = 0 OR tenant_id = $3)`
+	res, err := r.db.ExecContext(ctx, query, budget, teamID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot set team budget: %w", err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot set team budget: %w", err)
+	}
+	return updated > 0, nil
+}
+
+// GetMembers returns the managers first, then the members by name.
+func (r *teamRepo) GetMembers(ctx context.Context, teamID int) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	query := `
+		SELECT m.team_id, m.user_id, u.username, m.role, m.joined_at
+		  FROM team_members m
+		  JOIN users u ON u.id = m.user_id
+		 WHERE m.team_id = $1 AND ($3 = 0 OR u.tenant_id = $3)
+		 ORDER BY m.role = $2 DESC, u.username
+		`
+	err := r.db.SelectContext(ctx, &members, query, teamID, models.TeamRoleManager, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get team members: %w", err)
+	}
+	return members, nil
+}
+
+// GetMemberRole returns the user's role in the team, or an empty string
+// when the user is not a member.
+func (r *teamRepo) GetMemberRole(ctx context.Context, teamID int, userID int) (string, error) {
+	var role string
+	query := `
+		SELECT role
+		  FROM team_members
+		 WHERE team_id = $1 AND user_id = $2
+		   AND team_id IN (SELECT id FROM teams WHERE $3 = 0 OR tenant_id = $3)
+		`
+	err := r.db.GetContext(ctx, &role, query, teamID, userID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("repository: cannot get team role: %w", err)
+	}
+	return role, nil
+}
+
+// SaveMember adds the user to the team, or changes the role of a member.
+// The user and the team must belong to the tenant of the context.
+func (r *teamRepo) SaveMember(ctx context.Context, member *models.TeamMember) error {
+	query := `
+		INSERT INTO team_members (team_id, user_id, role)
+		SELECT t.id, u.id, $3
+		  FROM teams t
+		  JOIN users u ON u.id = $2 AND u.tenant_id = t.tenant_id
+		 WHERE t.id = $1 AND ($4 = 0 OR t.tenant_id = $4)
+		ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role
+		RETURNING joined_at
+		`
+	err := r.db.QueryRowContext(ctx, query, member.TeamID, member.UserID, member.Role, TenantFromContext(ctx)).
+		Scan(&member.JoinedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("repository: cannot save team member: %w", ErrCrossTenant)
+		}
+		return fmt.Errorf("repository: cannot save team member: %w", err)
+	}
+	return nil
+}
+
+func (r *teamRepo) RemoveMember(ctx context.Context, teamID int, userID int) (bool, error) {
+	query := `
+		DELETE FROM team_members
+		 WHERE team_id = $1 AND user_id = $2
+		   AND team_id IN (SELECT id FROM teams WHERE $3 = 0 OR tenant_id = $3)
+		`
+	res, err := r.db.ExecContext(ctx, query, teamID, userID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot remove team member: %w", err)
+	}
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot remove team member: %w", err)
+	}
+	return removed > 0, nil
+}
+
+// UpdatePot adds amount to the team pot, a negative amount takes coins out.
+// The team pots account follows, so its balance is the sum of all pots.
+func (r *teamRepo) UpdatePot(ctx context.Context, tx *sqlx.Tx, teamID int, amount int) error {
+	query := `UPDATE teams SET pot = pot + $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, amount, teamID); err != nil {
+		return fmt.Errorf("repository: cannot update team pot: %w", err)
+	}
+
+	query = `UPDATE users SET balance = balance + $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, amount, models.TeamPotAccountID); err != nil {
+		return fmt.Errorf("repository: cannot update team pots account: %w", err)
+	}
+	return nil
+}
+
+// GetPaidOut sums the coins paid out of the team pot since the given time.
+func (r *teamRepo) GetPaidOut(ctx context.Context, tx *sqlx.Tx, teamID int, since time.Time) (int, error) {
+	var paidOut int
+	query := `
+		SELECT COALESCE(SUM(amount), 0)
+		  FROM transactions
+		 WHERE team_id = $1 AND type = $2 AND timestamp >= $3
+		`
+	err := tx.GetContext(ctx, &paidOut, query, teamID, models.TransactionTypeTeamPayout, since)
+	if err != nil {
+		return 0, fmt.Errorf("repository: cannot get team payouts: %w", err)
+	}
+	return paidOut, nil
+}
+
+func (r *teamRepo) GetBudget(ctx context.Context, teamID int, since time.Time) (*models.TeamBudget, error) {
+	var budget models.TeamBudget
+	query := `
+		SELECT t.id AS team_id, t.pot, t.budget,
+		       COALESCE((SELECT SUM(tr.amount)
+		                   FROM transactions tr
+		                  WHERE tr.team_id = t.id AND tr.type = $2 AND tr.timestamp >= $3), 0) AS paid_out
+		  FROM teams t
+		 WHERE t.id = $1 AND ($4 = 0 OR t.tenant_id = $4)
+		`
+	err := r.db.GetContext(ctx, &budget, query, teamID, models.TransactionTypeTeamPayout, since,
+		TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get team budget: %w", err)
+	}
+	return &budget, nil
+}
+
+// GetLeaderboard ranks the teams by the coins their members received
+// between from and to, from transfers and team pot payouts.
+func (r *teamRepo) GetLeaderboard(ctx context.Context, from time.Time, to time.Time) ([]models.TeamStanding, error) {
+	var standings []models.TeamStanding
+	query := `
+		SELECT t.id AS team_id, t.name, t.department, COUNT(DISTINCT m.user_id) AS members,
+		       COALESCE(SUM(tr.amount) FILTER (WHERE tr.receiver_id = m.user_id), 0) AS received,
+		       COALESCE(SUM(tr.amount) FILTER (WHERE tr.sender_id = m.user_id), 0) AS sent
+		  FROM teams t
+		  LEFT JOIN team_members m ON m.team_id = t.id
+		  LEFT JOIN transactions tr
+		    ON (tr.receiver_id = m.user_id OR tr.sender_id = m.user_id)
+		   AND tr.type IN ($1, $2) AND tr.timestamp >= $3 AND tr.timestamp < $4
+		 WHERE $5 = 0 OR t.tenant_id = $5
+		 GROUP BY t.id, t.name, t.department
+		 ORDER BY received DESC, sent DESC, t.name
+		`
+	err := r.db.SelectContext(ctx, &standings, query,
+		models.TransactionTypeTransfer, models.TransactionTypeTeamPayout, from, to, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get team leaderboard: %w", err)
+	}
+	return standings, nil
+}
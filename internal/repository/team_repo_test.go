@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamRepo_GetLeaderboard_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	to := time.Now()
+	from := to.AddDate(0, -1, 0)
+
+	mock.ExpectQuery(`WHERE \$5 = 0 OR t\.tenant_id = \$5 GROUP BY t\.id, t\.name, t\.department`).
+		WithArgs(models.TransactionTypeTransfer, models.TransactionTypeTeamPayout, from, to, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"team_id", "name", "department", "members", "received", "sent"}))
+
+	repo := NewTeamRepo(sqlxDB)
+	standings, err := repo.GetLeaderboard(WithTenant(context.Background(), 2), from, to)
+	assert.NoError(t, err)
+	assert.Empty(t, standings)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTeamRepo_SaveMember_OtherTenantUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`INSERT INTO team_members \(team_id, user_id, role\)\s+SELECT t\.id, u\.id, \$3\s+FROM teams t\s+`+
+		`JOIN users u ON u\.id = \$2 AND u\.tenant_id = t\.tenant_id`).
+		WithArgs(4, 9, models.TeamRoleMember, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"joined_at"}))
+
+	repo := NewTeamRepo(sqlxDB)
+	err = repo.SaveMember(WithTenant(context.Background(), 2),
+		&models.TeamMember{TeamID: 4, UserID: 9, Role: models.TeamRoleMember})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrCrossTenant is returned when a write would link rows of two tenants,
+// or touch a row outside the tenant of the context.
+var ErrCrossTenant = errors.New("repository: cross-tenant access")
+
+// allTenants lifts the tenant scope of the queries.
+const allTenants = 0
+
+type tenantContextKey struct{}
+
+// WithTenant scopes the repository calls made with the context to a tenant.
+func WithTenant(ctx context.Context, tenantID int) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// WithAllTenants lets the repository calls made with the context see every
+// tenant. Only background jobs should use it.
+func WithAllTenants(ctx context.Context) context.Context {
+	return WithTenant(ctx, allTenants)
+}
+
+// TenantFromContext returns the tenant of the context, the default tenant
+// when none was set, or 0 for a context made by WithAllTenants.
+func TenantFromContext(ctx context.Context) int {
+	if tenantID, ok := ctx.Value(tenantContextKey{}).(int); ok {
+		return tenantID
+	}
+	return models.DefaultTenantID
+}
+
+type TenantRepo interface {
+	GetByID(ctx context.Context, tenantID int) (*models.Tenant, error)
+	GetByHost(ctx context.Context, host string) (*models.Tenant, error)
+}
+
+type tenantRepo struct {
+	db *sqlx.DB
+}
+
+func NewTenantRepo(db *sqlx.DB) TenantRepo {
+	return &tenantRepo{db: db}
+}
+
+const tenantColumns = `id, slug, name, host, currency_name, starting_balance, created_at`
+
+func (r *tenantRepo) GetByID(ctx context.Context, tenantID int) (*models.Tenant, error) {
+	var tenant models.Tenant
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE id = $1`
+	err := r.db.GetContext(ctx, &tenant, query, tenantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get tenant: %w", err)
+	}
+	return &tenant, nil
+}
+
+// GetByHost returns the tenant serving the host, or nil when no tenant has
+// claimed it.
+func (r *tenantRepo) GetByHost(ctx context.Context, host string) (*models.Tenant, error) {
+	var tenant models.Tenant
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE host = LOWER($1)`
+	err := r.db.GetContext(ctx, &tenant, query, host)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("repository: cannot get tenant by host: %w", err)
+	}
+	return &tenant, nil
+}
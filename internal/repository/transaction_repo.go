@@ -11,6 +11,7 @@ import (
 type TransactionRepo interface {
 	Create(ctx context.Context, tx *sqlx.Tx, transaction *models.Transaction) error
 	GetByUserID(ctx context.Context, userID int) ([]models.Transaction, error)
+	CreateBatch(ctx context.Context, tx *sqlx.Tx, batch *models.TransferBatch) error
 }
 
 type transactionRepo struct {
@@ -21,10 +22,43 @@ func NewTransactionRepo(db *sqlx.DB) TransactionRepo {
 	return &transactionRepo{db: db}
 }
 
+// Create stores the transaction under the tenant of its parties. It fails
+// with ErrCrossTenant when sender and receiver belong to different tenants,
+// or to a tenant other than the context's. System accounts are shared by
+// all tenants.
 func (r *transactionRepo) Create(ctx context.Context, tx *sqlx.Tx, transaction *models.Transaction) error {
-	query := `INSERT INTO transactions (sender_id, receiver_id, amount) VALUES ($1, $2, $3) RETURNING id`
-	err := tx.QueryRowContext(
-		ctx, query, transaction.SenderID, transaction.ReceiverID, transaction.Amount).Scan(&transaction.ID)
+	if transaction.Type == "" {
+		transaction.Type = models.TransactionTypeTransfer
+	}
+
+	var tenantID int
+	query := `
+		SELECT CASE WHEN s.id < 0 THEN r.tenant_id ELSE s.tenant_id END
+		  FROM users s
+		  JOIN users r ON r.id = $2
+		 WHERE s.id = $1 AND (s.id < 0 OR r.id < 0 OR s.tenant_id = r.tenant_id)
+		`
+	err := tx.GetContext(ctx, &tenantID, query, transaction.SenderID, transaction.ReceiverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("repository: cannot create transaction: %w", ErrCrossTenant)
+		}
+		return fmt.Errorf("repository: cannot get transaction tenant: %w", err)
+	}
+	if scope := TenantFromContext(ctx); scope != allTenants && scope != tenantID {
+		return fmt.Errorf("repository: cannot create transaction: %w", ErrCrossTenant)
+	}
+
+	query = `
+		INSERT INTO transactions (sender_id, receiver_id, amount, type, reference_id, batch_id, issuance_id,
+		                          reversal_of, team_id, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id
+		`
+	err = tx.QueryRowContext(
+		ctx, query, transaction.SenderID, transaction.ReceiverID, transaction.Amount,
+		transaction.Type, transaction.ReferenceID, transaction.BatchID,
+		transaction.IssuanceID, transaction.ReversalOf, transaction.TeamID, tenantID).Scan(&transaction.ID)
 	if err != nil {
 		return fmt.Errorf("repository: cannot create transaction: %w", err)
 	}
@@ -35,13 +69,27 @@ func (r *transactionRepo) Create(ctx context.Context, tx *sqlx.Tx, transaction *
 func (r *transactionRepo) GetByUserID(ctx context.Context, userID int) ([]models.Transaction, error) {
 	var query string
 	query = `
-			SELECT id, sender_id, receiver_id, amount
-			FROM transactions
-			WHERE (sender_id = $1 OR receiver_id = $1) AND receiver_id != -1
+			SELECT t.id, t.sender_id, t.receiver_id, t.amount, t.type, t.reference_id, t.batch_id,
+			       t.reversal_of, rv.id AS reversed_by,
+			       COALESCE(i.name, ti.name, pi.name) AS item_name,
+			       COALESCE(o.quantity, it.quantity) AS item_quantity
+			FROM transactions t
+			LEFT JOIN orders o ON o.transaction_id = t.id
+			LEFT JOIN items i ON o.item_id = i.id
+			LEFT JOIN item_transfers it ON it.transaction_id = t.id
+			LEFT JOIN items ti ON it.item_id = ti.id
+			LEFT JOIN pool_contributions pc ON pc.transaction_id = t.id
+			LEFT JOIN pools p ON pc.pool_id = p.id
+			LEFT JOIN items pi ON p.item_id = pi.id
+			LEFT JOIN transactions rv ON rv.reversal_of = t.id
+			WHERE (t.sender_id = $1 OR t.receiver_id = $1) AND (t.receiver_id != -1 OR t.type != $2)
+			  AND ($3 = 0 OR t.tenant_id = $3)
+			ORDER BY t.id
 			`
 
 	var transactions []models.Transaction
-	err := r.db.SelectContext(ctx, &transactions, query, userID)
+	err := r.db.SelectContext(ctx, &transactions, query, userID, models.TransactionTypePurchase,
+		TenantFromContext(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -50,3 +98,16 @@ func (r *transactionRepo) GetByUserID(ctx context.Context, userID int) ([]models
 	}
 	return transactions, nil
 }
+
+func (r *transactionRepo) CreateBatch(ctx context.Context, tx *sqlx.Tx, batch *models.TransferBatch) error {
+	query := `
+		INSERT INTO transfer_batches (sender_id, total, recipients)
+		VALUES ($1, $2, $3)
+		RETURNING id
+		`
+	err := tx.QueryRowContext(ctx, query, batch.SenderID, batch.Total, batch.Recipients).Scan(&batch.ID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot create transfer batch: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionRepo_Create_Tenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	tenantQuery := `SELECT CASE WHEN s\.id < 0 THEN r\.tenant_id ELSE s\.tenant_id END FROM users s JOIN users r ON r\.id = \$2 ` +
+		`WHERE s\.id = \$1 AND \(s\.id < 0 OR r\.id < 0 OR s\.tenant_id = r\.tenant_id\)`
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		transaction models.Transaction
+		mockSetup   func(mock sqlmock.Sqlmock)
+		expectedID  int
+		expectedErr error
+	}{
+		{
+			name:        "Same tenant",
+			ctx:         WithTenant(context.Background(), 2),
+			transaction: models.Transaction{SenderID: 5, ReceiverID: 6, Amount: 50},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(tenantQuery).
+					WithArgs(5, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(2))
+				mock.ExpectQuery(`INSERT INTO transactions`).
+					WithArgs(5, 6, 50, models.TransactionTypeTransfer, nil, nil, nil, nil, nil, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(41))
+			},
+			expectedID: 41,
+		},
+		{
+			name:        "Sender and receiver in different tenants",
+			ctx:         WithTenant(context.Background(), 2),
+			transaction: models.Transaction{SenderID: 5, ReceiverID: 9, Amount: 50},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(tenantQuery).
+					WithArgs(5, 9).
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}))
+			},
+			expectedErr: ErrCrossTenant,
+		},
+		{
+			name:        "Parties in another tenant than the request",
+			ctx:         WithTenant(context.Background(), 2),
+			transaction: models.Transaction{SenderID: 1, ReceiverID: 3, Amount: 50},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(tenantQuery).
+					WithArgs(1, 3).
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(models.DefaultTenantID))
+			},
+			expectedErr: ErrCrossTenant,
+		},
+		{
+			name:        "Job working on every tenant",
+			ctx:         WithAllTenants(context.Background()),
+			transaction: models.Transaction{SenderID: -1, ReceiverID: 6, Amount: 20, Type: models.TransactionTypeRefund},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(tenantQuery).
+					WithArgs(-1, 6).
+					WillReturnRows(sqlmock.NewRows([]string{"tenant_id"}).AddRow(2))
+				mock.ExpectQuery(`INSERT INTO transactions`).
+					WithArgs(-1, 6, 20, models.TransactionTypeRefund, nil, nil, nil, nil, nil, 2).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+			},
+			expectedID: 42,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectBegin()
+			tt.mockSetup(mock)
+			mock.ExpectRollback()
+
+			tx, err := sqlxDB.Beginx()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+			}
+
+			repo := NewTransactionRepo(sqlxDB)
+			transaction := tt.transaction
+			err = repo.Create(tt.ctx, tx, &transaction)
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedID, transaction.ID)
+			}
+			assert.NoError(t, tx.Rollback())
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type TransferLimitRepo interface {
+	LockSender(ctx context.Context, tx *sqlx.Tx, userID int) (string, error)
+	GetOverrides(ctx context.Context, tx *sqlx.Tx, userID int, role string) ([]models.TransferLimitOverride, error)
+	GetUsage(ctx context.Context, tx *sqlx.Tx, senderID int, receiverID int) (*models.TransferUsage, error)
+	GetAll(ctx context.Context) ([]models.TransferLimitOverride, error)
+	Save(ctx context.Context, override *models.TransferLimitOverride) error
+	Delete(ctx context.Context, overrideID int) (bool, error)
+}
+
+type transferLimitRepo struct {
+	db *sqlx.DB
+}
+
+func NewTransferLimitRepo(db *sqlx.DB) TransferLimitRepo {
+	return &transferLimitRepo{db: db}
+}
+
+const transferLimitColumns = `o.id, o.user_id, u.username, o.role, o.max_per_transfer, o.max_per_day,
+		       o.max_per_recipient_week, o.max_recipients_per_hour, o.created_at`
+
+// LockSender locks the sender's row, so concurrent transfers of one user are
+// checked against the limits one at a time, and returns the sender's role.
+func (r *transferLimitRepo) LockSender(ctx context.Context, tx *sqlx.Tx, userID int) (string, error) {
+	var role string
+	query := `SELECT role FROM users WHERE id = $1 AND ($2 = 0 OR tenant_id = $2) FOR UPDATE`
+	err := tx.GetContext(ctx, &role, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return "", fmt.Errorf("repository: cannot lock sender: %w", err)
+	}
+	return role, nil
+}
+
+// GetOverrides returns the role override of the user's tenant before the
+// user override, in the order they should be applied.
+func (r *transferLimitRepo) GetOverrides(
+	ctx context.Context, tx *sqlx.Tx, userID int, role string) ([]models.TransferLimitOverride, error) {
+	var overrides []models.TransferLimitOverride
+	query := `
+		SELECT ` + transferLimitColumns + `
+		  FROM transfer_limit_overrides o
+		  LEFT JOIN users u ON u.id = o.user_id
+		 WHERE o.user_id = $1
+		    OR (o.role = $2 AND o.tenant_id = (SELECT tenant_id FROM users WHERE id = $1))
+		 ORDER BY o.user_id NULLS FIRST
+		`
+	err := tx.SelectContext(ctx, &overrides, query, userID, role)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get transfer limit overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// GetUsage sums what the sender sent in the limit windows, counting
+// completed transfers, allowance gifts and transfers still waiting in escrow
+// or in review.
+func (r *transferLimitRepo) GetUsage(
+	ctx context.Context, tx *sqlx.Tx, senderID int, receiverID int) (*models.TransferUsage, error) {
+	var usage models.TransferUsage
+	query := `
+		SELECT COALESCE(SUM(amount) FILTER (WHERE sent_at > now() - INTERVAL '1 day'), 0) AS sent_last_day,
+		       COALESCE(SUM(amount) FILTER (WHERE receiver_id = $2), 0) AS sent_to_receiver_week,
+		       COUNT(DISTINCT receiver_id) FILTER (
+		           WHERE sent_at > now() - INTERVAL '1 hour' AND receiver_id <> $2) AS other_recipients_hour,
+		       COALESCE(BOOL_OR(receiver_id = $2 AND sent_at > now() - INTERVAL '1 hour'), FALSE) AS receiver_last_hour
+		  FROM (
+		        SELECT receiver_id, amount, timestamp AS sent_at
+		          FROM transactions
+		         WHERE sender_id = $1 AND type IN ($3, $6) AND timestamp > now() - INTERVAL '7 days'
+		         UNION ALL
+		        SELECT receiver_id, amount, created_at AS sent_at
+		          FROM pending_transfers
+		         WHERE sender_id = $1 AND status IN ($4, $5) AND created_at > now() - INTERVAL '7 days'
+		       ) sent
+		`
+	err := tx.GetContext(ctx, &usage, query,
+		senderID, receiverID, models.TransactionTypeTransfer,
+		models.PendingTransferStatusPending, models.PendingTransferStatusReview, models.TransactionTypeAllowance)
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get transfer usage: %w", err)
+	}
+	return &usage, nil
+}
+
+func (r *transferLimitRepo) GetAll(ctx context.Context) ([]models.TransferLimitOverride, error) {
+	var overrides []models.TransferLimitOverride
+	query := `
+		SELECT ` + transferLimitColumns + `
+		  FROM transfer_limit_overrides o
+		  LEFT JOIN users u ON u.id = o.user_id
+		 WHERE $1 = 0 OR o.tenant_id = $1
+		 ORDER BY o.id
+		`
+	err := r.db.SelectContext(ctx, &overrides, query, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get transfer limit overrides: %w", err)
+	}
+	return overrides, nil
+}
+
+// Save creates the override for its user or the role in the context's
+// tenant, or replaces the one already there. An override of another tenant
+// is never replaced.
+func (r *transferLimitRepo) Save(ctx context.Context, override *models.TransferLimitOverride) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: cannot save transfer limit override: %w", ErrCrossTenant)
+	}
+
+	target := "user_id"
+	if override.Role != nil {
+		target = "tenant_id, role"
+	}
+
+	query := `
+		INSERT INTO transfer_limit_overrides (user_id, role, max_per_transfer, max_per_day,
+		                                      max_per_recipient_week, max_recipients_per_hour, tenant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (` + target + `) DO UPDATE
+		   SET max_per_transfer = EXCLUDED.max_per_transfer,
+		       max_per_day = EXCLUDED.max_per_day,
+		       max_per_recipient_week = EXCLUDED.max_per_recipient_week,
+		       max_recipients_per_hour = EXCLUDED.max_recipients_per_hour
+		 WHERE transfer_limit_overrides.tenant_id = EXCLUDED.tenant_id
+		RETURNING id, created_at
+		`
+	err := r.db.QueryRowContext(ctx, query, override.UserID, override.Role, override.MaxPerTransfer,
+		override.MaxPerDay, override.MaxPerRecipientWeek, override.MaxRecipientsPerHour, tenantID).
+		Scan(&override.ID, &override.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("repository: cannot save transfer limit override: %w", ErrCrossTenant)
+		}
+		return fmt.Errorf("repository: cannot save transfer limit override: %w", err)
+	}
+	return nil
+}
+
+func (r *transferLimitRepo) Delete(ctx context.Context, overrideID int) (bool, error) {
+	query := `DELETE FROM transfer_limit_overrides WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)`
+	res, err := r.db.ExecContext(ctx, query, overrideID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot delete transfer limit override: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot delete transfer limit override: %w", err)
+	}
+	return affected > 0, nil
+}
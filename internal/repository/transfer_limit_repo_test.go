@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferLimitRepo_GetAll_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`FROM transfer_limit_overrides o LEFT JOIN users u ON u\.id = o\.user_id ` +
+		`WHERE \$1 = 0 OR o\.tenant_id = \$1 ORDER BY o\.id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "username", "role"}))
+
+	repo := NewTransferLimitRepo(sqlxDB)
+	overrides, err := repo.GetAll(WithTenant(context.Background(), 2))
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferLimitRepo_GetOverrides_RoleOfSenderTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE o\.user_id = \$1 `+
+		`OR \(o\.role = \$2 AND o\.tenant_id = \(SELECT tenant_id FROM users WHERE id = \$1\)\)`).
+		WithArgs(5, models.RoleEmployee).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "username", "role"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewTransferLimitRepo(sqlxDB)
+	overrides, err := repo.GetOverrides(WithTenant(context.Background(), 2), tx, 5, models.RoleEmployee)
+	assert.NoError(t, err)
+	assert.Empty(t, overrides)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferLimitRepo_Save_RoleOfTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	role := models.RoleEmployee
+	maxPerDay := 300
+
+	mock.ExpectQuery(`ON CONFLICT \(tenant_id, role\) DO UPDATE .* `+
+		`WHERE transfer_limit_overrides\.tenant_id = EXCLUDED\.tenant_id`).
+		WithArgs(nil, &role, nil, &maxPerDay, nil, nil, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(3, time.Now()))
+
+	repo := NewTransferLimitRepo(sqlxDB)
+	err = repo.Save(WithTenant(context.Background(), 2),
+		&models.TransferLimitOverride{Role: &role, MaxPerDay: &maxPerDay})
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferLimitRepo_Save_OtherTenantUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	userID := 9
+	maxPerDay := 300
+
+	// The user already has an override in another tenant, which is kept.
+	mock.ExpectQuery(`ON CONFLICT \(user_id\) DO UPDATE`).
+		WithArgs(&userID, nil, nil, &maxPerDay, nil, nil, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+
+	repo := NewTransferLimitRepo(sqlxDB)
+	err = repo.Save(WithTenant(context.Background(), 2),
+		&models.TransferLimitOverride{UserID: &userID, MaxPerDay: &maxPerDay})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestTransferLimitRepo_Delete_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectExec(`DELETE FROM transfer_limit_overrides WHERE id = \$1 AND \(\$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(3, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	repo := NewTransferLimitRepo(sqlxDB)
+	deleted, err := repo.Delete(WithTenant(context.Background(), 2), 3)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -3,23 +3,41 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var ErrInsufficientBalance = errors.New("repository: insufficient balance")
+
 type UserRepo interface {
 	GetByID(ctx context.Context, userID int) (*models.User, error)
 	GetUsernameByID(ctx context.Context, userID int) (string, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
 	UpdateBalance(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error
+	Credit(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string) error
+	Debit(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error
+	Overdraw(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error
+	Hold(ctx context.Context, tx *sqlx.Tx, userID int, amount int, hold models.CoinHold) error
+	Release(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string, hold models.CoinHold) error
+	MoveHold(ctx context.Context, tx *sqlx.Tx, userID int, from models.CoinHold, to models.CoinHold) error
 	UpdateInventory(ctx context.Context, tx *sqlx.Tx, userID int, inventory []models.UserInventoryItem) error
-	Create(ctx context.Context, user *models.User) error
-	CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, existingQuantity *int) error
-	AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error
-	AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error
-	UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, newQuantity int) error
+	Create(ctx context.Context, tx *sqlx.Tx, user *models.User) error
+	CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, existingQuantity *int) error
+	AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error
+	AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error
+	UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, newQuantity int) error
+	RemoveFromInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, quantity int) (bool, error)
+	LockInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int) (int, error)
+	GetRole(ctx context.Context, userID int) (string, error)
+	UpdateRole(ctx context.Context, tx *sqlx.Tx, userID int, role string) error
+	GetAutoAcceptTransfers(ctx context.Context, userID int) (bool, error)
+	SetAutoAcceptTransfers(ctx context.Context, userID int, autoAccept bool) error
+	GetAllowance(ctx context.Context, userID int, period string) (int, error)
+	SpendAllowance(ctx context.Context, tx *sqlx.Tx, userID int, period string, amount int) (bool, error)
+	RefillAllowances(ctx context.Context, amount int, period string) (int, error)
 }
 
 type userRepo struct {
@@ -34,12 +52,14 @@ func (r *userRepo) GetByID(ctx context.Context, userID int) (*models.User, error
 	var user models.User
 	query := `
         SELECT u.id, u.username, u.balance,
-               i.id AS item_id, i.name AS item_name, ui.quantity AS item_quantity
+               i.id AS item_id, i.name AS item_name, ui.quantity AS item_quantity,
+               v.id AS variant_id, v.size AS variant_size, v.color AS variant_color
           FROM users u
           LEFT JOIN user_inventory ui ON u.id = ui.user_id
           LEFT JOIN items i ON ui.item_id = i.id
-         WHERE u.id = $1`
-	rows, err := r.db.QueryxContext(ctx, query, userID)
+          LEFT JOIN item_variants v ON ui.variant_id = v.id
+         WHERE u.id = $1 AND (u.id < 0 OR $2 = 0 OR u.tenant_id = $2)`
+	rows, err := r.db.QueryxContext(ctx, query, userID, TenantFromContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("repository: get user by id failed: %w", err)
 	}
@@ -49,8 +69,11 @@ func (r *userRepo) GetByID(ctx context.Context, userID int) (*models.User, error
 		var itemID sql.NullInt64
 		var itemName sql.NullString
 		var quantity sql.NullInt64
+		var variantID sql.NullInt64
+		var variantSize, variantColor sql.NullString
 
-		err = rows.Scan(&user.ID, &user.Username, &user.Balance, &itemID, &itemName, &quantity)
+		err = rows.Scan(&user.ID, &user.Username, &user.Balance, &itemID, &itemName, &quantity,
+			&variantID, &variantSize, &variantColor)
 		if err != nil {
 			return nil, fmt.Errorf("repository: scan user row failed: %w", err)
 		}
@@ -60,6 +83,12 @@ func (r *userRepo) GetByID(ctx context.Context, userID int) (*models.User, error
 					ID:   int(itemID.Int64),
 					Name: itemName.String,
 				},
+				Variant: models.ItemVariant{
+					ID:     int(variantID.Int64),
+					ItemID: int(itemID.Int64),
+					Size:   variantSize.String,
+					Color:  variantColor.String,
+				},
 				Quantity: int(quantity.Int64),
 			})
 		}
@@ -74,8 +103,11 @@ func (r *userRepo) GetByID(ctx context.Context, userID int) (*models.User, error
 		var itemID sql.NullInt64
 		var itemName sql.NullString
 		var quantity sql.NullInt64
+		var variantID sql.NullInt64
+		var variantSize, variantColor sql.NullString
 
-		err = rows.Scan(&dummyID, &dummyUsername, &dummyBalance, &itemID, &itemName, &quantity)
+		err = rows.Scan(&dummyID, &dummyUsername, &dummyBalance, &itemID, &itemName, &quantity,
+			&variantID, &variantSize, &variantColor)
 		if err != nil {
 			return nil, fmt.Errorf("repository: scan inventory row failed: %w", err)
 		}
@@ -85,6 +117,12 @@ func (r *userRepo) GetByID(ctx context.Context, userID int) (*models.User, error
 					ID:   int(itemID.Int64),
 					Name: itemName.String,
 				},
+				Variant: models.ItemVariant{
+					ID:     int(variantID.Int64),
+					ItemID: int(itemID.Int64),
+					Size:   variantSize.String,
+					Color:  variantColor.String,
+				},
 				Quantity: int(quantity.Int64),
 			})
 		}
@@ -101,10 +139,10 @@ func (r *userRepo) GetUsernameByID(ctx context.Context, userID int) (string, err
 	query := `
 		SELECT username
 		FROM users
-		WHERE id = $1
+		WHERE id = $1 AND (id < 0 OR $2 = 0 OR tenant_id = $2)
 		`
 
-	err := r.db.GetContext(ctx, &username, query, userID)
+	err := r.db.GetContext(ctx, &username, query, userID, TenantFromContext(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return "", nil
@@ -116,8 +154,12 @@ func (r *userRepo) GetUsernameByID(ctx context.Context, userID int) (string, err
 
 func (r *userRepo) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	query := `SELECT id, username, password_hash, balance FROM users WHERE username = $1`
-	err := r.db.GetContext(ctx, &user, query, username)
+	query := `
+		SELECT id, username, password_hash, balance
+		FROM users
+		WHERE username = $1 AND ($2 = 0 OR tenant_id = $2)
+		`
+	err := r.db.GetContext(ctx, &user, query, username, TenantFromContext(ctx))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -127,30 +169,246 @@ func (r *userRepo) GetByUsername(ctx context.Context, username string) (*models.
 	return &user, nil
 }
 
+// UpdateBalance changes the balance of a user of the context's tenant, or of
+// a system account. Any other user is refused with ErrCrossTenant.
 func (r *userRepo) UpdateBalance(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
-	query := `UPDATE users SET balance = balance + $1 WHERE id = $2`
-	_, err := tx.ExecContext(ctx, query, amount, userID)
+	query := `
+		UPDATE users
+		SET balance = balance + $1
+		WHERE id = $2 AND (id < 0 OR $3 = 0 OR tenant_id = $3)
+		`
+	res, err := tx.ExecContext(ctx, query, amount, userID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: update user balance failed: %w", err)
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("repository: update user balance failed: %w", err)
+	}
+	if updated == 0 {
+		return fmt.Errorf("repository: update user balance failed: %w", ErrCrossTenant)
+	}
+	return nil
+}
+
+// withdraw takes amount from the balance of a user of the context's tenant,
+// as long as the balance covers it. The check is made by the update itself,
+// so concurrent spends cannot both pass it.
+func (r *userRepo) withdraw(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	query := `
+		UPDATE users
+		SET balance = balance - $1
+		WHERE id = $2 AND balance >= $1 AND ($3 = 0 OR tenant_id = $3)
+		`
+	res, err := tx.ExecContext(ctx, query, amount, userID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: update user balance failed: %w", err)
+	}
+	updated, err := res.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("repository: update user balance failed: %w", err)
 	}
+	if updated == 0 {
+		return fmt.Errorf("repository: update user balance failed: %w", ErrInsufficientBalance)
+	}
+	return nil
+}
+
+// Credit adds coins to the balance as a new lot. When the balance was
+// negative, the coins pay off the debt first and only the rest can be spent.
+func (r *userRepo) Credit(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string) error {
+	if err := r.UpdateBalance(ctx, tx, userID, amount); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO coin_lots (user_id, amount, remaining, source)
+		SELECT id, $2::INT, LEAST($2::INT, GREATEST(balance, 0)), $3::VARCHAR
+		FROM users
+		WHERE id = $1
+		`
+	_, err := tx.ExecContext(ctx, query, userID, amount, source)
+	if err != nil {
+		return fmt.Errorf("repository: create coin lot failed: %w", err)
+	}
+	return nil
+}
+
+// Debit takes coins from the balance, spending the oldest lots first. It
+// fails with ErrInsufficientBalance when the user has fewer coins.
+func (r *userRepo) Debit(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	_, err := r.spend(ctx, tx, userID, amount, false)
+	return err
+}
+
+// Overdraw takes coins like Debit, but may take the balance below zero.
+func (r *userRepo) Overdraw(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	_, err := r.spend(ctx, tx, userID, amount, true)
+	return err
+}
+
+// Hold takes coins from the balance like Debit and records the lots they
+// were taken from, so that Release can put them back.
+func (r *userRepo) Hold(ctx context.Context, tx *sqlx.Tx, userID int, amount int, hold models.CoinHold) error {
+	spent, err := r.spend(ctx, tx, userID, amount, false)
+	if err != nil {
+		return err
+	}
+
+	for _, part := range spent {
+		query := `
+			INSERT INTO coin_lot_holds (user_id, hold_type, hold_id, lot_id, amount)
+			VALUES ($1, $2, $3, $4, $5)
+			`
+		if _, err := tx.ExecContext(ctx, query, userID, hold.Type, hold.ID, part.LotID, part.Amount); err != nil {
+			return fmt.Errorf("repository: record coin hold failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// Release gives coins of a hold back to the user. They go back to the lots
+// the hold took them from, last taken first, and keep the date those lots
+// were earned and expire. Coins the hold has no record of come back as a new
+// lot of the given source. As with Credit, coins that pay off a negative
+// balance are not spendable.
+func (r *userRepo) Release(
+	ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string, hold models.CoinHold) error {
+	if err := r.UpdateBalance(ctx, tx, userID, amount); err != nil {
+		return err
+	}
+
+	var spendable int
+	query := `SELECT LEAST($2::INT, GREATEST(balance, 0)) FROM users WHERE id = $1`
+	if err := tx.GetContext(ctx, &spendable, query, userID, amount); err != nil {
+		return fmt.Errorf("repository: get user balance failed: %w", err)
+	}
+
+	var parts []models.CoinLotHold
+	query = `
+		SELECT id, lot_id, amount
+		FROM coin_lot_holds
+		WHERE user_id = $1 AND hold_type = $2 AND hold_id = $3
+		ORDER BY id DESC
+		FOR UPDATE
+		`
+	if err := tx.SelectContext(ctx, &parts, query, userID, hold.Type, hold.ID); err != nil {
+		return fmt.Errorf("repository: get coin hold failed: %w", err)
+	}
+
+	released, restored := 0, 0
+	for _, part := range parts {
+		if released == amount {
+			break
+		}
+		taken := min(part.Amount, amount-released)
+		released += taken
+
+		back := min(taken, spendable-restored)
+		restored += back
+		if back > 0 {
+			query := `UPDATE coin_lots SET remaining = remaining + $1 WHERE id = $2`
+			if _, err := tx.ExecContext(ctx, query, back, part.LotID); err != nil {
+				return fmt.Errorf("repository: restore coin lot failed: %w", err)
+			}
+		}
+
+		var err error
+		if taken == part.Amount {
+			_, err = tx.ExecContext(ctx, `DELETE FROM coin_lot_holds WHERE id = $1`, part.ID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE coin_lot_holds SET amount = amount - $1 WHERE id = $2`, taken, part.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("repository: release coin hold failed: %w", err)
+		}
+	}
+
+	if released < amount {
+		query := `
+			INSERT INTO coin_lots (user_id, amount, remaining, source)
+			VALUES ($1, $2, $3, $4)
+			`
+		_, err := tx.ExecContext(ctx, query, userID, amount-released, spendable-restored, source)
+		if err != nil {
+			return fmt.Errorf("repository: create coin lot failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// MoveHold hands the coins of a hold over to another one, for example when
+// a preorder becomes an order.
+func (r *userRepo) MoveHold(ctx context.Context, tx *sqlx.Tx, userID int, from models.CoinHold, to models.CoinHold) error {
+	query := `
+		UPDATE coin_lot_holds
+		SET hold_type = $4, hold_id = $5
+		WHERE user_id = $1 AND hold_type = $2 AND hold_id = $3
+		`
+	_, err := tx.ExecContext(ctx, query, userID, from.Type, from.ID, to.Type, to.ID)
+	if err != nil {
+		return fmt.Errorf("repository: move coin hold failed: %w", err)
+	}
 	return nil
 }
 
+// spend takes coins from the balance, oldest lots first, and returns how
+// much it took from each lot. Unless overdraw is set, the balance must cover
+// the amount.
+func (r *userRepo) spend(
+	ctx context.Context, tx *sqlx.Tx, userID int, amount int, overdraw bool) ([]models.CoinLotHold, error) {
+	if overdraw {
+		if err := r.UpdateBalance(ctx, tx, userID, -amount); err != nil {
+			return nil, err
+		}
+	} else if err := r.withdraw(ctx, tx, userID, amount); err != nil {
+		return nil, err
+	}
+
+	var lots []models.CoinLot
+	query := `
+		SELECT id, remaining
+		FROM coin_lots
+		WHERE user_id = $1 AND remaining > 0
+		ORDER BY earned_at, id
+		FOR UPDATE
+		`
+	if err := tx.SelectContext(ctx, &lots, query, userID); err != nil {
+		return nil, fmt.Errorf("repository: get coin lots failed: %w", err)
+	}
+
+	var spent []models.CoinLotHold
+	for _, lot := range lots {
+		if amount == 0 {
+			break
+		}
+		taken := min(lot.Remaining, amount)
+		amount -= taken
+
+		query := `UPDATE coin_lots SET remaining = remaining - $1 WHERE id = $2`
+		if _, err := tx.ExecContext(ctx, query, taken, lot.ID); err != nil {
+			return nil, fmt.Errorf("repository: spend coin lot failed: %w", err)
+		}
+		spent = append(spent, models.CoinLotHold{LotID: lot.ID, Amount: taken})
+	}
+	return spent, nil
+}
+
 func (r *userRepo) UpdateInventory(ctx context.Context, tx *sqlx.Tx, userID int, inventory []models.UserInventoryItem) error {
 	for _, item := range inventory {
 		var existingQuantity int
-		err := r.CheckInventory(ctx, tx, userID, item.Type.ID, &existingQuantity)
-		if err != nil && err != sql.ErrNoRows {
+		err := r.CheckInventory(ctx, tx, userID, item.Variant.ID, &existingQuantity)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("repository: UpdateInventory check failed: %w", err)
 		}
 
-		if err == sql.ErrNoRows {
-			if err := r.AddToInventory(ctx, tx, userID, item.Type.ID, item.Quantity); err != nil {
+		if err != nil {
+			if err := r.AddToInventory(ctx, tx, userID, item.Type.ID, item.Variant.ID, item.Quantity); err != nil {
 				return fmt.Errorf("repository: UpdateInventory add failed: %w", err)
 			}
 		} else {
 			newQuantity := existingQuantity + item.Quantity
-			if err := r.UpdateInventoryQuantity(ctx, tx, userID, item.Type.ID, newQuantity); err != nil {
+			if err := r.UpdateInventoryQuantity(ctx, tx, userID, item.Variant.ID, newQuantity); err != nil {
 				return fmt.Errorf("repository: UpdateInventory update quantity failed: %w", err)
 			}
 		}
@@ -158,14 +416,25 @@ func (r *userRepo) UpdateInventory(ctx context.Context, tx *sqlx.Tx, userID int,
 	return nil
 }
 
-func (r *userRepo) Create(ctx context.Context, user *models.User) error {
+// Create stores the user with an empty balance. The starting balance is
+// issued to the user separately.
+func (r *userRepo) Create(ctx context.Context, tx *sqlx.Tx, user *models.User) error {
+	tenantID := TenantFromContext(ctx)
+	if tenantID == allTenants {
+		return fmt.Errorf("repository: failed to create new user: %w", ErrCrossTenant)
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.PasswordHash), bcrypt.DefaultCost)
 	if err != nil {
 		return fmt.Errorf("repository: hashing password failed: %w", err)
 	}
 
-	query := `INSERT INTO users (username, password_hash, balance) VALUES ($1, $2, $3) returning id`
-	err = r.db.QueryRowContext(ctx, query, user.Username, hashedPassword, user.Balance).Scan(&user.ID)
+	query := `
+		INSERT INTO users (username, password_hash, balance, tenant_id)
+		VALUES ($1, $2, 0, $3)
+		RETURNING id
+		`
+	err = tx.QueryRowContext(ctx, query, user.Username, hashedPassword, tenantID).Scan(&user.ID)
 	if err != nil {
 		return fmt.Errorf("repository: failed to create new user: %w", err)
 	}
@@ -174,58 +443,228 @@ func (r *userRepo) Create(ctx context.Context, user *models.User) error {
 
 func (r *userRepo) CheckInventory(
 	ctx context.Context, tx *sqlx.Tx,
-	userID int, itemID int, existingQuantity *int) error {
-	query := `SELECT quantity FROM user_inventory WHERE user_id = $1 AND item_id = $2`
-	err := tx.GetContext(ctx, existingQuantity, query, userID, itemID)
+	userID int, variantID int, existingQuantity *int) error {
+	query := `
+		SELECT ui.quantity
+		FROM user_inventory ui
+		JOIN users u ON u.id = ui.user_id
+		WHERE ui.user_id = $1 AND ui.variant_id = $2 AND ($3 = 0 OR u.tenant_id = $3)
+		`
+	err := tx.GetContext(ctx, existingQuantity, query, userID, variantID, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("repository: inventory check failed: %w", err)
 	}
 	return nil
 }
 
-func (r *userRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
+func (r *userRepo) AddOrIncrementItemInventory(
+	ctx context.Context, tx *sqlx.Tx,
+	userID int, itemID int, variantID int, quantity int) error {
 	var existingQuantity int
-	err := tx.GetContext(ctx, &existingQuantity,
-		`SELECT quantity FROM user_inventory WHERE user_id = $1 AND item_id = $2`, userID, itemID)
-
-	if err != nil && err != sql.ErrNoRows {
+	err := r.CheckInventory(ctx, tx, userID, variantID, &existingQuantity)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("repository: AddOrIncrement inventory check failed: %w", err)
 	}
 
-	if err == sql.ErrNoRows {
-		_, err = tx.ExecContext(ctx,
-			`INSERT INTO user_inventory (user_id, item_id, quantity) VALUES ($1, $2, $3)`,
-			userID, itemID, quantity)
-
-		if err != nil {
+	if err != nil {
+		if err := r.AddToInventory(ctx, tx, userID, itemID, variantID, quantity); err != nil {
 			return fmt.Errorf("repository: AddOrIncrement inventory failed to add: %w", err)
 		}
 	} else {
 		newQuantity := existingQuantity + quantity
-		_, err = tx.ExecContext(ctx, `UPDATE user_inventory SET quantity = $1 WHERE user_id = $2 AND item_id = $3`,
-			newQuantity, userID, itemID)
-
-		if err != nil {
+		if err := r.UpdateInventoryQuantity(ctx, tx, userID, variantID, newQuantity); err != nil {
 			return fmt.Errorf("repository: AddOrIncrement inventory failed to update quantity: %w", err)
 		}
 	}
 	return nil
 }
 
-func (r *userRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
-	query := `INSERT INTO user_inventory (user_id, item_id, quantity) VALUES ($1, $2, $3)`
-	_, err := tx.ExecContext(ctx, query, userID, itemID, quantity)
+// AddToInventory adds an item to the user's inventory. The item has to come
+// from the catalog of the user's tenant, otherwise it fails with
+// ErrCrossTenant.
+func (r *userRepo) AddToInventory(
+	ctx context.Context, tx *sqlx.Tx,
+	userID int, itemID int, variantID int, quantity int) error {
+	query := `
+		INSERT INTO user_inventory (user_id, item_id, variant_id, quantity)
+		SELECT u.id, i.id, $3, $4
+		FROM users u
+		JOIN items i ON i.id = $2 AND i.tenant_id = u.tenant_id
+		WHERE u.id = $1 AND ($5 = 0 OR u.tenant_id = $5)
+		`
+	res, err := tx.ExecContext(ctx, query, userID, itemID, variantID, quantity, TenantFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("repository: failed to add item to inventory: %w", err)
 	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("repository: failed to add item to inventory: %w", err)
+	}
+	if added == 0 {
+		return fmt.Errorf("repository: failed to add item to inventory: %w", ErrCrossTenant)
+	}
 	return nil
 }
 
-func (r *userRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
-	query := `UPDATE user_inventory SET quantity = $1 WHERE user_id = $2 AND item_id = $3`
-	_, err := tx.ExecContext(ctx, query, quantity, userID, itemID)
+func (r *userRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, quantity int) error {
+	query := `
+		UPDATE user_inventory
+		   SET quantity = $1
+		 WHERE user_id = $2 AND variant_id = $3
+		   AND user_id IN (SELECT id FROM users WHERE $4 = 0 OR tenant_id = $4)
+		`
+	res, err := tx.ExecContext(ctx, query, quantity, userID, variantID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: failed to update item quantity in inventory: %w", err)
+	}
+	updated, err := res.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("repository: failed to update item quantity in inventory: %w", err)
 	}
+	if updated == 0 {
+		return fmt.Errorf("repository: failed to update item quantity in inventory: %w", ErrCrossTenant)
+	}
 	return nil
 }
+
+// RemoveFromInventory takes quantity units of a variant out of the user's
+// inventory. It reports false when the user owns fewer units than requested.
+func (r *userRepo) RemoveFromInventory(
+	ctx context.Context, tx *sqlx.Tx,
+	userID int, variantID int, quantity int) (bool, error) {
+	query := `
+		UPDATE user_inventory
+		   SET quantity = quantity - $1
+		 WHERE user_id = $2 AND variant_id = $3 AND quantity >= $1
+		   AND user_id IN (SELECT id FROM users WHERE $4 = 0 OR tenant_id = $4)
+		`
+	res, err := tx.ExecContext(ctx, query, quantity, userID, variantID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to remove item from inventory: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: failed to remove item from inventory: %w", err)
+	}
+	return affected == 1, nil
+}
+
+// LockInventory locks the user's inventory row for a variant until the end of
+// the transaction and returns the owned quantity, or 0 if there is no row.
+func (r *userRepo) LockInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int) (int, error) {
+	var quantity int
+	query := `
+		SELECT ui.quantity
+		FROM user_inventory ui
+		JOIN users u ON u.id = ui.user_id
+		WHERE ui.user_id = $1 AND ui.variant_id = $2 AND ($3 = 0 OR u.tenant_id = $3)
+		FOR UPDATE OF ui
+		`
+	err := tx.GetContext(ctx, &quantity, query, userID, variantID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("repository: failed to lock inventory: %w", err)
+	}
+	return quantity, nil
+}
+
+func (r *userRepo) GetRole(ctx context.Context, userID int) (string, error) {
+	var role string
+	query := `SELECT role FROM users WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)`
+	err := r.db.GetContext(ctx, &role, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("repository: get user role failed: %w", err)
+	}
+	return role, nil
+}
+
+func (r *userRepo) UpdateRole(ctx context.Context, tx *sqlx.Tx, userID int, role string) error {
+	query := `UPDATE users SET role = $1 WHERE id = $2 AND ($3 = 0 OR tenant_id = $3)`
+	_, err := tx.ExecContext(ctx, query, role, userID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: update user role failed: %w", err)
+	}
+	return nil
+}
+
+func (r *userRepo) GetAutoAcceptTransfers(ctx context.Context, userID int) (bool, error) {
+	var autoAccept bool
+	query := `SELECT auto_accept_transfers FROM users WHERE id = $1 AND ($2 = 0 OR tenant_id = $2)`
+	err := r.db.GetContext(ctx, &autoAccept, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: get transfer setting failed: %w", err)
+	}
+	return autoAccept, nil
+}
+
+func (r *userRepo) SetAutoAcceptTransfers(ctx context.Context, userID int, autoAccept bool) error {
+	query := `UPDATE users SET auto_accept_transfers = $1 WHERE id = $2 AND ($3 = 0 OR tenant_id = $3)`
+	_, err := r.db.ExecContext(ctx, query, autoAccept, userID, TenantFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("repository: update transfer setting failed: %w", err)
+	}
+	return nil
+}
+
+// GetAllowance returns the giving allowance left for the period. An
+// allowance from an earlier period has expired and counts as zero.
+func (r *userRepo) GetAllowance(ctx context.Context, userID int, period string) (int, error) {
+	var allowance int
+	query := `
+		SELECT CASE WHEN allowance_period = $2 THEN allowance ELSE 0 END
+		FROM users
+		WHERE id = $1 AND ($3 = 0 OR tenant_id = $3)
+		`
+	err := r.db.GetContext(ctx, &allowance, query, userID, period, TenantFromContext(ctx))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("repository: get allowance failed: %w", err)
+	}
+	return allowance, nil
+}
+
+// SpendAllowance takes amount from the user's allowance for the period and
+// reports false when there is not enough left.
+func (r *userRepo) SpendAllowance(
+	ctx context.Context, tx *sqlx.Tx, userID int, period string, amount int) (bool, error) {
+	query := `
+		UPDATE users
+		SET allowance = allowance - $1
+		WHERE id = $2 AND allowance_period = $3 AND allowance >= $1 AND ($4 = 0 OR tenant_id = $4)
+		`
+	res, err := tx.ExecContext(ctx, query, amount, userID, period, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: spend allowance failed: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: spend allowance failed: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RefillAllowances starts a new period for every user that is still on an
+// older one, dropping whatever was left of the old allowance.
+func (r *userRepo) RefillAllowances(ctx context.Context, amount int, period string) (int, error) {
+	query := `
+		UPDATE users
+		SET allowance = $1, allowance_period = $2
+		WHERE id > 0 AND allowance_period IS DISTINCT FROM $2 AND ($3 = 0 OR tenant_id = $3)
+		`
+	res, err := r.db.ExecContext(ctx, query, amount, period, TenantFromContext(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("repository: refill allowances failed: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("repository: refill allowances failed: %w", err)
+	}
+	return int(rows), nil
+}
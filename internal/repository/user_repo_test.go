@@ -32,15 +32,18 @@ func TestUserRepo_GetByID(t *testing.T) {
 			name:   "User found with inventory",
 			userID: 1,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity"}).
-					AddRow(1, "user1", 850, 3, "book", 1).
-					AddRow(1, "user1", 850, 4, "sword", 2)
+				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity",
+					"variant_id", "variant_size", "variant_color"}).
+					AddRow(1, "user1", 850, 3, "book", 1, 3, "", "").
+					AddRow(1, "user1", 850, 4, "sword", 2, 7, "XL", "black")
 				mock.ExpectQuery(`
-									SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity 
+									SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity, 
+									    v\.id AS variant_id, v\.size AS variant_size, v\.color AS variant_color 
 									FROM users u LEFT JOIN user_inventory ui ON u\.id = ui\.user_id 
-									    LEFT JOIN items i ON ui\.item_id = i\.id WHERE u\.id = \$1
+									    LEFT JOIN items i ON ui\.item_id = i\.id 
+									    LEFT JOIN item_variants v ON ui\.variant_id = v\.id WHERE u\.id = \$1
 									    `).
-					WithArgs(1).
+					WithArgs(1, models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected: &models.User{
@@ -53,6 +56,10 @@ func TestUserRepo_GetByID(t *testing.T) {
 							ID:   3,
 							Name: "book",
 						},
+						Variant: models.ItemVariant{
+							ID:     3,
+							ItemID: 3,
+						},
 						Quantity: 1,
 					},
 					{
@@ -60,6 +67,12 @@ func TestUserRepo_GetByID(t *testing.T) {
 							ID:   4,
 							Name: "sword",
 						},
+						Variant: models.ItemVariant{
+							ID:     7,
+							ItemID: 4,
+							Size:   "XL",
+							Color:  "black",
+						},
 						Quantity: 2,
 					},
 				},
@@ -70,15 +83,18 @@ func TestUserRepo_GetByID(t *testing.T) {
 			name:   "User found without inventory",
 			userID: 1,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity"}).
-					AddRow(1, "user1", 850, nil, nil, nil)
+				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity",
+					"variant_id", "variant_size", "variant_color"}).
+					AddRow(1, "user1", 850, nil, nil, nil, nil, nil, nil)
 				mock.ExpectQuery(`
-						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity 
+						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity, 
+						    v\.id AS variant_id, v\.size AS variant_size, v\.color AS variant_color 
 						FROM users u LEFT JOIN user_inventory ui ON u\.id = ui\.user_id 
 						    LEFT JOIN items i ON ui\.item_id = i\.id 
+						    LEFT JOIN item_variants v ON ui\.variant_id = v\.id 
 						WHERE u\.id = \$1
 						`).
-					WithArgs(1).
+					WithArgs(1, models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected: &models.User{
@@ -93,14 +109,17 @@ func TestUserRepo_GetByID(t *testing.T) {
 			name:   "User not found",
 			userID: 999,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity"})
+				rows := sqlmock.NewRows([]string{"id", "username", "balance", "item_id", "item_name", "item_quantity",
+					"variant_id", "variant_size", "variant_color"})
 				mock.ExpectQuery(`
-						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity 
+						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity, 
+						    v\.id AS variant_id, v\.size AS variant_size, v\.color AS variant_color 
 						FROM users u LEFT JOIN user_inventory ui ON u\.id = ui\.user_id 
 						    LEFT JOIN items i ON ui\.item_id = i\.id 
+						    LEFT JOIN item_variants v ON ui\.variant_id = v\.id 
 						WHERE u\.id = \$1
 						`).
-					WithArgs(999).
+					WithArgs(999, models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    nil,
@@ -111,12 +130,14 @@ func TestUserRepo_GetByID(t *testing.T) {
 			userID: 1,
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(`
-						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity 
+						SELECT u\.id, u\.username, u\.balance, i\.id AS item_id, i\.name AS item_name, ui\.quantity AS item_quantity, 
+						    v\.id AS variant_id, v\.size AS variant_size, v\.color AS variant_color 
 						FROM users u LEFT JOIN user_inventory ui ON u\.id = ui\.user_id 
 						    LEFT JOIN items i ON ui\.item_id = i\.id 
+						    LEFT JOIN item_variants v ON ui\.variant_id = v\.id 
 						WHERE u\.id = \$1
 						`).
-					WithArgs(1).
+					WithArgs(1, models.DefaultTenantID).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    nil,
@@ -172,7 +193,7 @@ func TestUserRepo_GetUsernameByID(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"username"}).
 					AddRow("user1")
 				mock.ExpectQuery(`SELECT username FROM users WHERE id = \$1`).
-					WithArgs(1).
+					WithArgs(1, models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    "user1",
@@ -184,7 +205,7 @@ func TestUserRepo_GetUsernameByID(t *testing.T) {
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"username"})
 				mock.ExpectQuery(`SELECT username FROM users WHERE id = \$1`).
-					WithArgs(999).
+					WithArgs(999, models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    "",
@@ -195,7 +216,7 @@ func TestUserRepo_GetUsernameByID(t *testing.T) {
 			userID: 1,
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(`SELECT username FROM users WHERE id = \$1`).
-					WithArgs(1).
+					WithArgs(1, models.DefaultTenantID).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    "",
@@ -248,7 +269,7 @@ func TestUserRepo_GetByUsername(t *testing.T) {
 				rows := sqlmock.NewRows([]string{"id", "username", "password_hash", "balance"}).
 					AddRow(1, "user1", "$2a$10$Wn5VZPmD9YRYF4K6T2yv.O3HJ3G2F4T1JG2F4T1JG2F4T1", 1000)
 				mock.ExpectQuery(`SELECT id, username, password_hash, balance FROM users WHERE username = \$1`).
-					WithArgs("user1").
+					WithArgs("user1", models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected: &models.User{
@@ -266,7 +287,7 @@ func TestUserRepo_GetByUsername(t *testing.T) {
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				rows := sqlmock.NewRows([]string{"id", "username", "password_hash", "balance"})
 				mock.ExpectQuery(`SELECT id, username, password_hash, balance FROM users WHERE username = \$1`).
-					WithArgs("nonexistent_user").
+					WithArgs("nonexistent_user", models.DefaultTenantID).
 					WillReturnRows(rows)
 			},
 			expected:    nil,
@@ -277,7 +298,7 @@ func TestUserRepo_GetByUsername(t *testing.T) {
 			username: "user1",
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectQuery(`SELECT id, username, password_hash, balance FROM users WHERE username = \$1`).
-					WithArgs("user1").
+					WithArgs("user1", models.DefaultTenantID).
 					WillReturnError(sql.ErrConnDone)
 			},
 			expected:    nil,
@@ -333,7 +354,7 @@ func TestUserRepo_UpdateBalance(t *testing.T) {
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2`).
-					WithArgs(-100, 1).
+					WithArgs(-100, 1, models.DefaultTenantID).
 					WillReturnResult(sqlmock.NewResult(0, 1))
 				mock.ExpectCommit()
 			},
@@ -346,7 +367,7 @@ func TestUserRepo_UpdateBalance(t *testing.T) {
 			mockSetup: func(mock sqlmock.Sqlmock) {
 				mock.ExpectBegin()
 				mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2`).
-					WithArgs(-100, 1).
+					WithArgs(-100, 1, models.DefaultTenantID).
 					WillReturnError(sql.ErrConnDone)
 				mock.ExpectRollback()
 			},
@@ -382,3 +403,383 @@ func TestUserRepo_UpdateBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepo_Debit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance - \$1 WHERE id = \$2 AND balance >= \$1`).
+		WithArgs(120, 1, models.DefaultTenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, remaining FROM coin_lots WHERE user_id = \$1 AND remaining > 0 ORDER BY earned_at, id FOR UPDATE`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining"}).AddRow(3, 50).AddRow(8, 100).AddRow(9, 40))
+	mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining - \$1 WHERE id = \$2`).
+		WithArgs(50, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining - \$1 WHERE id = \$2`).
+		WithArgs(70, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Debit(context.Background(), tx, 1, 120)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Debit_InsufficientBalance(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance - \$1 WHERE id = \$2 AND balance >= \$1`).
+		WithArgs(120, 1, models.DefaultTenantID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Debit(context.Background(), tx, 1, 120)
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Overdraw(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2`).
+		WithArgs(-120, 1, models.DefaultTenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, remaining FROM coin_lots WHERE user_id = \$1 AND remaining > 0 ORDER BY earned_at, id FOR UPDATE`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining"}).AddRow(3, 50))
+	mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining - \$1 WHERE id = \$2`).
+		WithArgs(50, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Overdraw(context.Background(), tx, 1, 120)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Credit(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2`).
+		WithArgs(30, 2, models.DefaultTenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO coin_lots \(user_id, amount, remaining, source\)\s+SELECT id, \$2::INT, LEAST\(\$2::INT, GREATEST\(balance, 0\)\), \$3::VARCHAR`).
+		WithArgs(2, 30, models.CoinSourceTransfer).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Credit(context.Background(), tx, 2, 30, models.CoinSourceTransfer)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Hold(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	hold := models.CoinHold{Type: models.CoinHoldPreorder, ID: 4}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance - \$1 WHERE id = \$2 AND balance >= \$1`).
+		WithArgs(120, 1, models.DefaultTenantID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT id, remaining FROM coin_lots WHERE user_id = \$1 AND remaining > 0 ORDER BY earned_at, id FOR UPDATE`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining"}).AddRow(3, 50).AddRow(8, 100))
+	mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining - \$1 WHERE id = \$2`).
+		WithArgs(50, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining - \$1 WHERE id = \$2`).
+		WithArgs(70, 8).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO coin_lot_holds \(user_id, hold_type, hold_id, lot_id, amount\)`).
+		WithArgs(1, models.CoinHoldPreorder, 4, 3, 50).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO coin_lot_holds \(user_id, hold_type, hold_id, lot_id, amount\)`).
+		WithArgs(1, models.CoinHoldPreorder, 4, 8, 70).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Hold(context.Background(), tx, 1, 120, hold)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Release(t *testing.T) {
+	hold := models.CoinHold{Type: models.CoinHoldPreorder, ID: 4}
+
+	tests := []struct {
+		name      string
+		amount    int
+		mockSetup func(mock sqlmock.Sqlmock)
+	}{
+		{
+			name:   "Coins go back to the lots they were held from",
+			amount: 120,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, lot_id, amount FROM coin_lot_holds WHERE user_id = \$1 AND hold_type = \$2 AND hold_id = \$3 ORDER BY id DESC FOR UPDATE`).
+					WithArgs(1, models.CoinHoldPreorder, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "lot_id", "amount"}).AddRow(2, 8, 70).AddRow(1, 3, 50))
+				mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining \+ \$1 WHERE id = \$2`).
+					WithArgs(70, 8).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM coin_lot_holds WHERE id = \$1`).
+					WithArgs(2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining \+ \$1 WHERE id = \$2`).
+					WithArgs(50, 3).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`DELETE FROM coin_lot_holds WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Part of a hold",
+			amount: 30,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, lot_id, amount FROM coin_lot_holds`).
+					WithArgs(1, models.CoinHoldPreorder, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "lot_id", "amount"}).AddRow(2, 8, 70).AddRow(1, 3, 50))
+				mock.ExpectExec(`UPDATE coin_lots SET remaining = remaining \+ \$1 WHERE id = \$2`).
+					WithArgs(30, 8).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(`UPDATE coin_lot_holds SET amount = amount - \$1 WHERE id = \$2`).
+					WithArgs(30, 2).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:   "Hold without recorded lots",
+			amount: 120,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(`SELECT id, lot_id, amount FROM coin_lot_holds`).
+					WithArgs(1, models.CoinHoldPreorder, 4).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "lot_id", "amount"}))
+				mock.ExpectExec(`INSERT INTO coin_lots \(user_id, amount, remaining, source\)\s+VALUES \(\$1, \$2, \$3, \$4\)`).
+					WithArgs(1, 120, 120, models.CoinSourceRelease).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer db.Close()
+
+			sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2`).
+				WithArgs(tt.amount, 1, models.DefaultTenantID).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectQuery(`SELECT LEAST\(\$2::INT, GREATEST\(balance, 0\)\) FROM users WHERE id = \$1`).
+				WithArgs(1, tt.amount).
+				WillReturnRows(sqlmock.NewRows([]string{"least"}).AddRow(tt.amount))
+			tt.mockSetup(mock)
+			mock.ExpectCommit()
+
+			tx, err := sqlxDB.Beginx()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+			}
+
+			repo := NewUserRepo(sqlxDB)
+			err = repo.Release(context.Background(), tx, 1, tt.amount, models.CoinSourceRelease, hold)
+			assert.NoError(t, err)
+			assert.NoError(t, tx.Commit())
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestUserRepo_GetByUsername_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`SELECT id, username, password_hash, balance FROM users WHERE username = \$1 AND \(\$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs("user1", 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "password_hash", "balance"}))
+
+	repo := NewUserRepo(sqlxDB)
+	user, err := repo.GetByUsername(WithTenant(context.Background(), 2), "user1")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_UpdateBalance_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE users SET balance = balance \+ \$1 WHERE id = \$2 AND \(id < 0 OR \$3 = 0 OR tenant_id = \$3\)`).
+		WithArgs(100, 5, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.UpdateBalance(WithTenant(context.Background(), 2), tx, 5, 100)
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_AddToInventory_OtherTenantItem(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO user_inventory \(user_id, item_id, variant_id, quantity\)\s+SELECT u\.id, i\.id, \$3, \$4\s+FROM users u\s+JOIN items i ON i\.id = \$2 AND i\.tenant_id = u\.tenant_id`).
+		WithArgs(5, 7, 11, 1, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.AddToInventory(WithTenant(context.Background(), 2), tx, 5, 7, 11, 1)
+	assert.ErrorIs(t, err, ErrCrossTenant)
+	assert.NoError(t, tx.Rollback())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestUserRepo_Create_AllTenants(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	repo := NewUserRepo(sqlxDB)
+	err = repo.Create(WithAllTenants(context.Background()), nil, &models.User{Username: "user1", PasswordHash: "secret"})
+	assert.ErrorIs(t, err, ErrCrossTenant)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
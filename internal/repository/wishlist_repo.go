@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/jmoiron/sqlx"
+)
+
+type WishlistRepo interface {
+	Add(ctx context.Context, userID int, itemID int) error
+	Remove(ctx context.Context, userID int, itemID int) (bool, error)
+	GetByUserID(ctx context.Context, userID int) ([]models.WishlistEntry, error)
+	GetUserIDsByItem(ctx context.Context, tx *sqlx.Tx, itemID int) ([]int, error)
+}
+
+type wishlistRepo struct {
+	db *sqlx.DB
+}
+
+func NewWishlistRepo(db *sqlx.DB) WishlistRepo {
+	return &wishlistRepo{db: db}
+}
+
+func (r *wishlistRepo) Add(ctx context.Context, userID int, itemID int) error {
+	query := `INSERT INTO wishlist (user_id, item_id) VALUES ($1, $2) ON CONFLICT (user_id, item_id) DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, userID, itemID)
+	if err != nil {
+		return fmt.Errorf("repository: cannot add to wishlist: %w", err)
+	}
+	return nil
+}
+
+func (r *wishlistRepo) Remove(ctx context.Context, userID int, itemID int) (bool, error) {
+	query := `
+		DELETE FROM wishlist
+		 WHERE user_id = $1 AND item_id = $2 AND user_id IN (SELECT id FROM users WHERE $3 = 0 OR tenant_id = $3)
+		`
+	res, err := r.db.ExecContext(ctx, query, userID, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot remove from wishlist: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: cannot remove from wishlist: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// GetByUserID returns the wishlisted items. An item is in stock when any of
+// its variants has unlimited or positive stock.
+func (r *wishlistRepo) GetByUserID(ctx context.Context, userID int) ([]models.WishlistEntry, error) {
+	var entries []models.WishlistEntry
+	query := `
+		SELECT w.item_id, i.name AS item, i.category, i.price,
+		       i.archived_at IS NULL AND EXISTS (
+		           SELECT 1 FROM item_variants v
+		            WHERE v.item_id = i.id AND (v.stock IS NULL OR v.stock > 0)
+		       ) AS in_stock,
+		       w.created_at
+		  FROM wishlist w
+		  JOIN items i ON i.id = w.item_id
+		 WHERE w.user_id = $1 AND ($2 = 0 OR i.tenant_id = $2)
+		 ORDER BY w.id
+		`
+	err := r.db.SelectContext(ctx, &entries, query, userID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get wishlist: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *wishlistRepo) GetUserIDsByItem(ctx context.Context, tx *sqlx.Tx, itemID int) ([]int, error) {
+	var userIDs []int
+	query := `
+		SELECT user_id
+		  FROM wishlist
+		 WHERE item_id = $1 AND user_id IN (SELECT id FROM users WHERE $2 = 0 OR tenant_id = $2)
+		 ORDER BY user_id
+		`
+	err := tx.SelectContext(ctx, &userIDs, query, itemID, TenantFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("repository: cannot get wishlisting users: %w", err)
+	}
+	return userIDs, nil
+}
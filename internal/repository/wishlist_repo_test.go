@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWishlistRepo_GetByUserID_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectQuery(`WHERE w\.user_id = \$1 AND \(\$2 = 0 OR i\.tenant_id = \$2\)`).
+		WithArgs(5, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"item_id", "item", "category", "price", "in_stock", "created_at"}))
+
+	repo := NewWishlistRepo(sqlxDB)
+	entries, err := repo.GetByUserID(WithTenant(context.Background(), 2), 5)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWishlistRepo_GetUserIDsByItem_OtherTenant(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`WHERE item_id = \$1 AND user_id IN \(SELECT id FROM users WHERE \$2 = 0 OR tenant_id = \$2\)`).
+		WithArgs(10, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectCommit()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	repo := NewWishlistRepo(sqlxDB)
+	userIDs, err := repo.GetUserIDsByItem(WithTenant(context.Background(), 2), tx, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, userIDs)
+	assert.NoError(t, tx.Commit())
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
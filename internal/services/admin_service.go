@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+)
+
+var ErrInvalidRole = errors.New("services: unknown role")
+
+// AdminService makes the account and catalog changes reserved to admins.
+// Each change is audited in the transaction that makes it.
+type AdminService interface {
+	SetRole(ctx context.Context, username string, role string) error
+	SetArchived(ctx context.Context, itemName string, archived bool) error
+}
+
+type adminService struct {
+	userRepo     repository.UserRepo
+	itemRepo     repository.ItemRepo
+	auditService AuditService
+	db           *sqlx.DB
+}
+
+func NewAdminService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	auditService AuditService,
+	db *sqlx.DB,
+) AdminService {
+	return &adminService{
+		userRepo:     userRepo,
+		itemRepo:     itemRepo,
+		auditService: auditService,
+		db:           db,
+	}
+}
+
+func (s *adminService) SetRole(ctx context.Context, username string, role string) (err error) {
+	if !models.IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+
+	previousRole, err := s.userRepo.GetRole(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get user role: %w", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	if err := s.userRepo.UpdateRole(ctx, tx, user.ID, role); err != nil {
+		return fmt.Errorf("services: failed to update role: %w", err)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionRoleChanged,
+		TargetType: models.AuditTargetUser,
+		TargetID:   strconv.Itoa(user.ID),
+		Before:     models.AuditState(map[string]string{"role": previousRole}),
+		After:      models.AuditState(map[string]string{"role": role}),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit role: %w", err)
+	}
+	return nil
+}
+
+// SetArchived takes an item off the catalog, or puts it back.
+func (s *adminService) SetArchived(ctx context.Context, itemName string, archived bool) (err error) {
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return ErrItemNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	if err := s.itemRepo.SetArchived(ctx, tx, item.ID, archived); err != nil {
+		return fmt.Errorf("services: failed to update item: %w", err)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionItemUnarchived,
+		TargetType: models.AuditTargetItem,
+		TargetID:   strconv.Itoa(item.ID),
+		Before:     models.AuditState(map[string]bool{"archived": item.ArchivedAt != nil}),
+		After:      models.AuditState(map[string]bool{"archived": archived}),
+	}
+	if archived {
+		entry.Action = models.AuditActionItemArchived
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit item: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAdminService_SetRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		auditErr error
+	}{
+		{
+			name: "Change is audited in its transaction",
+		},
+		{
+			name:     "Audit failure rolls back the change",
+			auditErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.auditErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockAuditService := new(mocks.AuditService)
+			adminService := NewAdminService(mockUserRepo, nil, mockAuditService, sqlxDB)
+			ctx := context.Background()
+
+			mockUserRepo.On("GetByUsername", ctx, "alice").Return(&models.User{ID: 3, Username: "alice"}, nil).Once()
+			mockUserRepo.On("GetRole", ctx, 3).Return(models.RoleEmployee, nil).Once()
+			mockUserRepo.On("UpdateRole", ctx, mock.Anything, 3, models.RoleAdmin).Return(nil).Once()
+			mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+				return e.Action == models.AuditActionRoleChanged && e.TargetID == "3"
+			})).Return(tt.auditErr).Once()
+
+			err := adminService.SetRole(ctx, "alice", models.RoleAdmin)
+			if tt.auditErr != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			mockUserRepo.AssertExpectations(t)
+			mockAuditService.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAdminService_SetRole_UnknownRole(t *testing.T) {
+	adminService := NewAdminService(nil, nil, nil, nil)
+
+	err := adminService.SetRole(context.Background(), "alice", "superuser")
+	assert.ErrorIs(t, err, ErrInvalidRole)
+}
+
+func TestAdminService_SetArchived_AuditFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockItemRepo := new(mocks.ItemRepo)
+	mockAuditService := new(mocks.AuditService)
+	adminService := NewAdminService(nil, mockItemRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockItemRepo.On("GetItemByName", ctx, "pink-hoody").Return(&models.Item{ID: 10, Name: "pink-hoody"}, nil).Once()
+	mockItemRepo.On("SetArchived", ctx, mock.Anything, 10, true).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionItemArchived && e.TargetID == "10"
+	})).Return(errors.New("database error")).Once()
+
+	err := adminService.SetArchived(ctx, "pink-hoody", true)
+	assert.Error(t, err)
+
+	mockItemRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
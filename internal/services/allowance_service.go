@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrInsufficientAllowance = errors.New("services: insufficient giving allowance")
+	ErrAllowanceGiftFlagged  = errors.New("services: allowance gift flagged by the fraud screening")
+)
+
+type AllowanceService interface {
+	Get(ctx context.Context, userID int) (*models.Allowance, error)
+	Give(ctx context.Context, fromUserID int, toUserID int, amount int) error
+	Refill(ctx context.Context) (int, error)
+}
+
+type allowanceService struct {
+	userRepo        repository.UserRepo
+	transactionRepo repository.TransactionRepo
+	issuanceRepo    repository.IssuanceRepo
+	coinService     CoinService
+	fraudService    FraudService
+	db              *sqlx.DB
+	monthly         int
+}
+
+func NewAllowanceService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	issuanceRepo repository.IssuanceRepo,
+	coinService CoinService,
+	fraudService FraudService,
+	db *sqlx.DB,
+	monthly int,
+) AllowanceService {
+	return &allowanceService{
+		userRepo:        userRepo,
+		transactionRepo: transactionRepo,
+		issuanceRepo:    issuanceRepo,
+		coinService:     coinService,
+		fraudService:    fraudService,
+		db:              db,
+		monthly:         monthly,
+	}
+}
+
+func (s *allowanceService) Get(ctx context.Context, userID int) (*models.Allowance, error) {
+	now := time.Now()
+
+	amount, err := s.userRepo.GetAllowance(ctx, userID, now.Format(periodLayout))
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get allowance: %w", err)
+	}
+
+	return &models.Allowance{
+		Amount:    amount,
+		Monthly:   s.monthly,
+		ExpiresAt: time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, now.Location()),
+	}, nil
+}
+
+// Give sends coins from the sender's allowance. The receiver gets them as
+// regular spendable coins. The allowance is not part of any balance, so the
+// gift mints new coins and is recorded as an allowance issuance, like every
+// other coin put into circulation. Allowance gifts are never escrowed: the
+// allowance they came from may have expired by the time a pending transfer
+// would be returned. They count towards the sender's transfer limits like any
+// transfer, and as they cannot be held for a review, a gift flagged by the
+// fraud screening is refused.
+func (s *allowanceService) Give(ctx context.Context, fromUserID int, toUserID int, amount int) (err error) {
+	if amount < 1 || fromUserID == toUserID {
+		return ErrInvalidTransfer
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	flag, err := s.fraudService.ScreenTransfer(ctx, fromUserID, toUserID, amount)
+	if err != nil {
+		return fmt.Errorf("services: failed to screen transfer: %w", err)
+	}
+	if flag != nil {
+		return ErrAllowanceGiftFlagged
+	}
+
+	if err := s.coinService.CheckTransferLimits(ctx, tx, fromUserID, toUserID, amount); err != nil {
+		return err
+	}
+
+	period := time.Now().Format(periodLayout)
+	spent, err := s.userRepo.SpendAllowance(ctx, tx, fromUserID, period, amount)
+	if err != nil {
+		return fmt.Errorf("services: failed to spend allowance: %w", err)
+	}
+	if !spent {
+		return ErrInsufficientAllowance
+	}
+
+	issuance := &models.Issuance{
+		Kind:       models.IssuanceKindAllowance,
+		Reason:     "giving allowance",
+		Period:     &period,
+		Total:      amount,
+		Recipients: 1,
+	}
+
+	if err := s.issuanceRepo.Create(ctx, tx, issuance); err != nil {
+		return fmt.Errorf("services: failed to create issuance: %w", err)
+	}
+
+	if err := s.userRepo.Credit(ctx, tx, toUserID, amount, models.CoinSourceAllowance); err != nil {
+		return fmt.Errorf("services: failed to update balance of toUser: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   fromUserID,
+		ReceiverID: toUserID,
+		Amount:     amount,
+		Type:       models.TransactionTypeAllowance,
+		IssuanceID: &issuance.ID,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Refill gives every user the monthly allowance once the month changes.
+// The rest of the previous month's allowance expires at the same time.
+func (s *allowanceService) Refill(ctx context.Context) (int, error) {
+	refilled, err := s.userRepo.RefillAllowances(ctx, s.monthly, time.Now().Format(periodLayout))
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to refill allowances: %w", err)
+	}
+	return refilled, nil
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAllowanceService_Get(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	allowanceService := NewAllowanceService(mockUserRepo, nil, nil, nil, nil, nil, 100)
+	ctx := context.Background()
+
+	now := time.Now()
+	mockUserRepo.On("GetAllowance", ctx, 1, now.Format(periodLayout)).Return(40, nil).Once()
+
+	allowance, err := allowanceService.Get(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, allowance.Amount)
+	assert.Equal(t, 100, allowance.Monthly)
+	assert.True(t, allowance.ExpiresAt.After(now))
+	assert.Equal(t, 1, allowance.ExpiresAt.Day())
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAllowanceService_Give(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockIssuanceRepo := new(mocks.IssuanceRepo)
+	mockCoinService := new(mocks.CoinService)
+	mockFraudService := new(mocks.FraudService)
+	allowanceService := NewAllowanceService(mockUserRepo, mockTransactionRepo, mockIssuanceRepo,
+		mockCoinService, mockFraudService, sqlxDB, 100)
+	ctx := context.Background()
+
+	period := time.Now().Format(periodLayout)
+	mockFraudService.On("ScreenTransfer", ctx, 1, 2, 30).Return(nil, nil).Once()
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 30).Return(nil).Once()
+	mockUserRepo.On("SpendAllowance", ctx, mock.Anything, 1, period, 30).Return(true, nil).Once()
+	mockIssuanceRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(iss *models.Issuance) bool {
+		return iss.Kind == models.IssuanceKindAllowance && iss.Total == 30 && *iss.Period == period
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Issuance).ID = 8
+	}).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, 30, models.CoinSourceAllowance).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Amount == 30 && tr.Type == models.TransactionTypeAllowance &&
+			tr.IssuanceID != nil && *tr.IssuanceID == 8
+	})).Return(nil).Once()
+
+	err := allowanceService.Give(ctx, 1, 2, 30)
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertNotCalled(t, "Debit", ctx, mock.Anything, 1, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockIssuanceRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+	mockFraudService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAllowanceService_Give_Insufficient(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockCoinService := new(mocks.CoinService)
+	mockFraudService := new(mocks.FraudService)
+	allowanceService := NewAllowanceService(mockUserRepo, nil, nil, mockCoinService, mockFraudService, sqlxDB, 100)
+	ctx := context.Background()
+
+	mockFraudService.On("ScreenTransfer", ctx, 1, 2, 300).Return(nil, nil).Once()
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 300).Return(nil).Once()
+	mockUserRepo.On("SpendAllowance", ctx, mock.Anything, 1, mock.Anything, 300).Return(false, nil).Once()
+
+	err := allowanceService.Give(ctx, 1, 2, 300)
+	assert.ErrorIs(t, err, ErrInsufficientAllowance)
+
+	err = allowanceService.Give(ctx, 1, 1, 10)
+	assert.ErrorIs(t, err, ErrInvalidTransfer)
+
+	mockUserRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAllowanceService_Give_Screened(t *testing.T) {
+	tests := []struct {
+		name        string
+		flag        *models.FraudFlag
+		limitErr    error
+		expectedErr error
+	}{
+		{
+			name:        "Flagged by the fraud screening",
+			flag:        &models.FraudFlag{UserID: 1, Rule: models.FraudRuleCircularFlow},
+			expectedErr: ErrAllowanceGiftFlagged,
+		},
+		{
+			name:        "Over the transfer limit",
+			limitErr:    &TransferLimitError{Code: models.TransferLimitPerDay, Limit: 500},
+			expectedErr: ErrTransferLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockCoinService := new(mocks.CoinService)
+			mockFraudService := new(mocks.FraudService)
+			allowanceService := NewAllowanceService(mockUserRepo, nil, nil, mockCoinService, mockFraudService, sqlxDB, 100)
+			ctx := context.Background()
+
+			mockFraudService.On("ScreenTransfer", ctx, 1, 2, 30).Return(tt.flag, nil).Once()
+			mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 30).Return(tt.limitErr).Maybe()
+
+			err := allowanceService.Give(ctx, 1, 2, 30)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			mockUserRepo.AssertNotCalled(t, "SpendAllowance",
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockUserRepo.AssertNotCalled(t, "Credit",
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockFraudService.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAllowanceService_Refill(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	allowanceService := NewAllowanceService(mockUserRepo, nil, nil, nil, nil, nil, 100)
+	ctx := context.Background()
+
+	mockUserRepo.On("RefillAllowances", ctx, 100, time.Now().Format(periodLayout)).Return(5, nil).Once()
+
+	refilled, err := allowanceService.Refill(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, refilled)
+	mockUserRepo.AssertExpectations(t)
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrAuctionNotFound        = errors.New("services: auction not found")
+	ErrInvalidAuction         = errors.New("services: invalid auction")
+	ErrAuctionItemUnavailable = errors.New("services: item is out of stock")
+	ErrAuctionClosed          = errors.New("services: auction is closed")
+	ErrBidTooLow              = errors.New("services: bid is too low")
+	ErrAlreadyHighestBidder   = errors.New("services: you are already the highest bidder")
+)
+
+type AuctionService interface {
+	Create(ctx context.Context, adminID int, itemName string, opts models.BuyOptions,
+		auction *models.Auction) error
+	GetOpen(ctx context.Context) ([]models.Auction, error)
+	Bid(ctx context.Context, userID int, auctionID int, amount int) (*models.Bid, error)
+	CloseExpired(ctx context.Context) (int, error)
+}
+
+type auctionService struct {
+	userRepo         repository.UserRepo
+	itemRepo         repository.ItemRepo
+	transactionRepo  repository.TransactionRepo
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	auctionRepo      repository.AuctionRepo
+	db               *sqlx.DB
+}
+
+func NewAuctionService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	transactionRepo repository.TransactionRepo,
+	orderRepo repository.OrderRepo,
+	notificationRepo repository.NotificationRepo,
+	auctionRepo repository.AuctionRepo,
+	db *sqlx.DB,
+) AuctionService {
+	return &auctionService{
+		userRepo:         userRepo,
+		itemRepo:         itemRepo,
+		transactionRepo:  transactionRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		auctionRepo:      auctionRepo,
+		db:               db,
+	}
+}
+
+// Create puts one unit of an item variant up for auction. The unit is taken
+// out of stock until the auction closes.
+func (s *auctionService) Create(
+	ctx context.Context, adminID int, itemName string, opts models.BuyOptions, auction *models.Auction) (err error) {
+	if auction.StartPrice < 1 || auction.Increment < 1 || auction.ExtendBySeconds < 0 ||
+		!auction.EndsAt.After(time.Now()) {
+		return ErrInvalidAuction
+	}
+
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return ErrItemNotFound
+	}
+
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	reserved, err := s.itemRepo.DecrementStock(ctx, tx, variant.ID, 1)
+	if err != nil {
+		return fmt.Errorf("services: failed to reserve stock: %w", err)
+	}
+	if !reserved {
+		return ErrAuctionItemUnavailable
+	}
+
+	auction.ItemID = item.ID
+	auction.VariantID = variant.ID
+	auction.Item = item.Name
+	auction.Size = variant.Size
+	auction.Color = variant.Color
+	auction.Status = models.AuctionStatusOpen
+	auction.CreatedBy = &adminID
+
+	if err := s.auctionRepo.Create(ctx, tx, auction); err != nil {
+		return fmt.Errorf("services: failed to create auction: %w", err)
+	}
+
+	return nil
+}
+
+func (s *auctionService) GetOpen(ctx context.Context) ([]models.Auction, error) {
+	auctions, err := s.auctionRepo.GetOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get auctions: %w", err)
+	}
+	return auctions, nil
+}
+
+// Bid holds the bid amount from the bidder's balance and immediately refunds
+// the previous highest bidder.
+func (s *auctionService) Bid(ctx context.Context, userID int, auctionID int, amount int) (bid *models.Bid, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	auction, err := s.auctionRepo.GetByIDForUpdate(ctx, tx, auctionID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock auction: %w", err)
+	}
+	if auction == nil {
+		return nil, ErrAuctionNotFound
+	}
+
+	now := time.Now()
+	if auction.Status != models.AuctionStatusOpen || !now.Before(auction.EndsAt) {
+		return nil, ErrAuctionClosed
+	}
+	if auction.HighestBidderID != nil && *auction.HighestBidderID == userID {
+		return nil, ErrAlreadyHighestBidder
+	}
+	if amount < auction.MinimumBid() {
+		return nil, ErrBidTooLow
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by id: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("services: user not found")
+	}
+	if user.Balance < amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	hold := models.CoinHold{Type: models.CoinHoldAuction, ID: auction.ID}
+	if auction.HighestBidderID != nil {
+		outbidID := *auction.HighestBidderID
+
+		err := s.userRepo.Release(ctx, tx, outbidID, *auction.HighestBid, models.CoinSourceRelease, hold)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to refund outbid user: %w", err)
+		}
+
+		if err := s.auctionRepo.SetActiveBidStatus(ctx, tx, auction.ID, models.BidStatusOutbid); err != nil {
+			return nil, fmt.Errorf("services: failed to update bids: %w", err)
+		}
+
+		notification := &models.Notification{
+			UserID:  outbidID,
+			Type:    models.NotificationTypeOutbid,
+			Message: fmt.Sprintf("You have been outbid on %s, your %d coins were returned", auction.Item, *auction.HighestBid),
+		}
+
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return nil, fmt.Errorf("services: failed to notify outbid user: %w", err)
+		}
+	}
+
+	if err := s.userRepo.Hold(ctx, tx, userID, amount, hold); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("services: failed to hold bid: %w", err)
+	}
+
+	bid = &models.Bid{
+		AuctionID: auction.ID,
+		UserID:    userID,
+		Amount:    amount,
+		Status:    models.BidStatusActive,
+	}
+
+	if err := s.auctionRepo.CreateBid(ctx, tx, bid); err != nil {
+		return nil, fmt.Errorf("services: failed to create bid: %w", err)
+	}
+
+	auction.HighestBid = &amount
+	auction.HighestBidderID = &userID
+	auction.Extend(now)
+
+	if err := s.auctionRepo.UpdateHighestBid(ctx, tx, auction); err != nil {
+		return nil, fmt.Errorf("services: failed to update auction: %w", err)
+	}
+
+	return bid, nil
+}
+
+// CloseExpired closes every auction past its end time. Each auction is
+// closed in its own transaction so one failure does not block the others.
+func (s *auctionService) CloseExpired(ctx context.Context) (int, error) {
+	ids, err := s.auctionRepo.GetExpiredIDs(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get expired auctions: %w", err)
+	}
+
+	closed := 0
+	var errs []error
+	for _, id := range ids {
+		ok, err := s.close(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+
+	return closed, errors.Join(errs...)
+}
+
+// close awards the item to the highest bidder, turning the held bid into the
+// purchase payment. Without bids the unit goes back to stock.
+func (s *auctionService) close(ctx context.Context, auctionID int) (closed bool, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	auction, err := s.auctionRepo.GetByIDForUpdate(ctx, tx, auctionID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to lock auction: %w", err)
+	}
+	if auction == nil || auction.Status != models.AuctionStatusOpen || time.Now().Before(auction.EndsAt) {
+		return false, nil
+	}
+
+	auction.Status = models.AuctionStatusClosed
+
+	if auction.HighestBidderID == nil {
+		if err := s.itemRepo.IncrementStock(ctx, tx, auction.VariantID, 1); err != nil {
+			return false, fmt.Errorf("services: failed to restock auction item: %w", err)
+		}
+		if err := s.auctionRepo.Close(ctx, tx, auction); err != nil {
+			return false, fmt.Errorf("services: failed to close auction: %w", err)
+		}
+		return true, nil
+	}
+
+	winnerID := *auction.HighestBidderID
+	price := *auction.HighestBid
+
+	err = s.userRepo.AddOrIncrementItemInventory(ctx, tx, winnerID, auction.ItemID, auction.VariantID, 1)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to add to inventory: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   winnerID,
+		ReceiverID: -1,
+		Amount:     price,
+		Type:       models.TransactionTypePurchase,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return false, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	order := &models.Order{
+		UserID:        winnerID,
+		RecipientID:   winnerID,
+		ItemID:        auction.ItemID,
+		VariantID:     auction.VariantID,
+		TransactionID: transaction.ID,
+		Quantity:      1,
+		UnitPrice:     price,
+		ListTotal:     price,
+		Total:         price,
+	}
+
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return false, fmt.Errorf("services: failed to create order: %w", err)
+	}
+
+	hold := models.CoinHold{Type: models.CoinHoldAuction, ID: auction.ID}
+	if err := s.userRepo.MoveHold(ctx, tx, winnerID, hold, order.CoinHold()); err != nil {
+		return false, fmt.Errorf("services: failed to move held coins to the order: %w", err)
+	}
+
+	if err := s.auctionRepo.SetActiveBidStatus(ctx, tx, auction.ID, models.BidStatusWon); err != nil {
+		return false, fmt.Errorf("services: failed to update bids: %w", err)
+	}
+
+	auction.WinnerID = &winnerID
+	auction.OrderID = &order.ID
+
+	if err := s.auctionRepo.Close(ctx, tx, auction); err != nil {
+		return false, fmt.Errorf("services: failed to close auction: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  winnerID,
+		Type:    models.NotificationTypeAuctionWon,
+		Message: fmt.Sprintf("You won the auction for %s with a bid of %d coins", auction.Item, price),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return false, fmt.Errorf("services: failed to notify winner: %w", err)
+	}
+
+	return true, nil
+}
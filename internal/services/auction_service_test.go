@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestAuctionService_Bid_Rejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		auction     *models.Auction
+		userID      int
+		amount      int
+		expectedErr error
+	}{
+		{
+			name:        "Below start price",
+			auction:     &models.Auction{ID: 1, StartPrice: 50, Increment: 5, Status: models.AuctionStatusOpen},
+			userID:      1,
+			amount:      40,
+			expectedErr: ErrBidTooLow,
+		},
+		{
+			name: "Below increment",
+			auction: &models.Auction{ID: 1, StartPrice: 50, Increment: 5, Status: models.AuctionStatusOpen,
+				HighestBid: intPtr(60), HighestBidderID: intPtr(2)},
+			userID:      1,
+			amount:      64,
+			expectedErr: ErrBidTooLow,
+		},
+		{
+			name: "Already highest bidder",
+			auction: &models.Auction{ID: 1, StartPrice: 50, Increment: 5, Status: models.AuctionStatusOpen,
+				HighestBid: intPtr(60), HighestBidderID: intPtr(1)},
+			userID:      1,
+			amount:      100,
+			expectedErr: ErrAlreadyHighestBidder,
+		},
+		{
+			name:        "Closed auction",
+			auction:     &models.Auction{ID: 1, StartPrice: 50, Increment: 5, Status: models.AuctionStatusClosed},
+			userID:      1,
+			amount:      100,
+			expectedErr: ErrAuctionClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockAuctionRepo := new(mocks.AuctionRepo)
+			auctionService := NewAuctionService(mockUserRepo, nil, nil, nil, nil, mockAuctionRepo, sqlxDB)
+			ctx := context.Background()
+
+			tt.auction.EndsAt = time.Now().Add(time.Hour)
+			mockAuctionRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(tt.auction, nil).Once()
+
+			bid, err := auctionService.Bid(ctx, tt.userID, 1, tt.amount)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Nil(t, bid)
+
+			mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAuctionService_Bid_OtherTenantAuction(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockAuctionRepo := new(mocks.AuctionRepo)
+	auctionService := NewAuctionService(mockUserRepo, nil, nil, nil, nil, mockAuctionRepo, sqlxDB)
+	ctx := repository.WithTenant(context.Background(), 2)
+
+	// The auction of another tenant is not visible to the bidder's tenant.
+	mockAuctionRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(nil, nil).Once()
+
+	bid, err := auctionService.Bid(ctx, 5, 1, 100)
+	assert.ErrorIs(t, err, ErrAuctionNotFound)
+	assert.Nil(t, bid)
+
+	mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAuctionRepo.AssertNotCalled(t, "CreateBid", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAuctionService_Bid_Outbid(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockAuctionRepo := new(mocks.AuctionRepo)
+	auctionService := NewAuctionService(
+		mockUserRepo, nil, nil, nil, mockNotificationRepo, mockAuctionRepo, sqlxDB)
+	ctx := context.Background()
+
+	endsAt := time.Now().Add(30 * time.Second)
+	auction := &models.Auction{ID: 1, Item: "hoody", StartPrice: 50, Increment: 5, ExtendBySeconds: 120,
+		EndsAt: endsAt, Status: models.AuctionStatusOpen, HighestBid: intPtr(60), HighestBidderID: intPtr(2)}
+
+	mockAuctionRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(auction, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 100}, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 2, 60, models.CoinSourceRelease,
+		models.CoinHold{Type: models.CoinHoldAuction, ID: 1}).Return(nil).Once()
+	mockAuctionRepo.On("SetActiveBidStatus", ctx, mock.Anything, 1, models.BidStatusOutbid).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 2 && n.Type == models.NotificationTypeOutbid
+	})).Return(nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 70, models.CoinHold{Type: models.CoinHoldAuction, ID: 1}).Return(nil).Once()
+	mockAuctionRepo.On("CreateBid", ctx, mock.Anything, mock.MatchedBy(func(b *models.Bid) bool {
+		return b.UserID == 1 && b.Amount == 70 && b.Status == models.BidStatusActive
+	})).Return(nil).Once()
+	mockAuctionRepo.On("UpdateHighestBid", ctx, mock.Anything, mock.MatchedBy(func(a *models.Auction) bool {
+		return *a.HighestBid == 70 && *a.HighestBidderID == 1 && a.EndsAt.After(endsAt)
+	})).Return(nil).Once()
+
+	bid, err := auctionService.Bid(ctx, 1, 1, 70)
+	assert.NoError(t, err)
+	assert.Equal(t, 70, bid.Amount)
+
+	mockUserRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuctionRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAuctionService_CloseExpired(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockAuctionRepo := new(mocks.AuctionRepo)
+	auctionService := NewAuctionService(mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo,
+		mockNotificationRepo, mockAuctionRepo, sqlxDB)
+	ctx := context.Background()
+
+	ended := time.Now().Add(-time.Minute)
+	won := &models.Auction{ID: 1, ItemID: 3, VariantID: 5, Item: "hoody", EndsAt: ended,
+		Status: models.AuctionStatusOpen, HighestBid: intPtr(70), HighestBidderID: intPtr(1)}
+	unsold := &models.Auction{ID: 2, ItemID: 3, VariantID: 6, Item: "hoody", EndsAt: ended,
+		Status: models.AuctionStatusOpen}
+
+	mockAuctionRepo.On("GetExpiredIDs", ctx, mock.Anything).Return([]int{1, 2}, nil).Once()
+
+	mockAuctionRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(won, nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 3, 5, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == -1 && tr.Amount == 70 && tr.Type == models.TransactionTypePurchase
+	})).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == 1 && o.VariantID == 5 && o.Total == 70
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Order).ID = 12
+	}).Return(nil).Once()
+	mockUserRepo.On("MoveHold", ctx, mock.Anything, 1, models.CoinHold{Type: models.CoinHoldAuction, ID: 1},
+		models.CoinHold{Type: models.CoinHoldOrder, ID: 12}).Return(nil).Once()
+	mockAuctionRepo.On("SetActiveBidStatus", ctx, mock.Anything, 1, models.BidStatusWon).Return(nil).Once()
+	mockAuctionRepo.On("Close", ctx, mock.Anything, mock.MatchedBy(func(a *models.Auction) bool {
+		return a.ID == 1 && a.Status == models.AuctionStatusClosed && *a.WinnerID == 1
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeAuctionWon
+	})).Return(nil).Once()
+
+	mockAuctionRepo.On("GetByIDForUpdate", ctx, mock.Anything, 2).Return(unsold, nil).Once()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 6, 1).Return(nil).Once()
+	mockAuctionRepo.On("Close", ctx, mock.Anything, mock.MatchedBy(func(a *models.Auction) bool {
+		return a.ID == 2 && a.Status == models.AuctionStatusClosed && a.WinnerID == nil
+	})).Return(nil).Once()
+
+	closed, err := auctionService.CloseExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, closed)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuctionRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
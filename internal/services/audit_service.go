@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var ErrInvalidAuditFilter = errors.New("services: invalid audit filter")
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 500
+	// auditVerifyBatch is how many entries Verify reads at a time.
+	auditVerifyBatch = 500
+)
+
+type auditContextKey struct{}
+
+// auditContext is who made the request being served and its request id.
+type auditContext struct {
+	requestID string
+	actorID   *int
+}
+
+// WithAudit attaches the request id and the acting user to ctx, so audit
+// entries recorded while serving the request carry them. An actorID of 0
+// means the request is not authenticated.
+func WithAudit(ctx context.Context, requestID string, actorID int) context.Context {
+	audit := auditContext{requestID: requestID}
+	if actorID != 0 {
+		audit.actorID = &actorID
+	}
+	return context.WithValue(ctx, auditContextKey{}, audit)
+}
+
+type AuditService interface {
+	Record(ctx context.Context, tx *sqlx.Tx, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	Verify(ctx context.Context) (*models.AuditVerification, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepo
+	db        *sqlx.DB
+}
+
+func NewAuditService(auditRepo repository.AuditRepo, db *sqlx.DB) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+		db:        db,
+	}
+}
+
+// Record appends the entry to the audit log inside tx, so the entry is only
+// kept when the change it describes is. With a nil tx the entry is written
+// on its own. The request id and actor default to the ones in ctx.
+func (s *auditService) Record(ctx context.Context, tx *sqlx.Tx, entry *models.AuditEntry) (err error) {
+	if tx == nil {
+		tx, err = s.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("services: failed to begin transaction: %w", err)
+		}
+
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+			} else {
+				tx.Commit()
+			}
+		}()
+	}
+
+	if audit, ok := ctx.Value(auditContextKey{}).(auditContext); ok {
+		if entry.RequestID == "" {
+			entry.RequestID = audit.requestID
+		}
+		if entry.ActorID == nil && entry.ActorName == nil {
+			entry.ActorID = audit.actorID
+		}
+	}
+
+	prevHash, err := s.auditRepo.LockChain(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("services: failed to lock audit log: %w", err)
+	}
+
+	entry.PrevHash = prevHash
+	entry.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	entry.Hash = entry.ComputeHash()
+
+	if err := s.auditRepo.Create(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *auditService) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit < 0 || filter.BeforeID < 0 {
+		return nil, ErrInvalidAuditFilter
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, ErrInvalidAuditFilter
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	filter.Limit = min(filter.Limit, maxAuditLimit)
+
+	entries, err := s.auditRepo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get audit entries: %w", err)
+	}
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+	return entries, nil
+}
+
+// Verify walks the whole chain and reports the first entry that was changed,
+// or that follows a removed entry.
+func (s *auditService) Verify(ctx context.Context) (*models.AuditVerification, error) {
+	verification := &models.AuditVerification{Valid: true}
+	prevHash, lastID := "", 0
+
+	for {
+		entries, err := s.auditRepo.GetChain(ctx, lastID, auditVerifyBatch)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to get audit chain: %w", err)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			if entry.PrevHash != prevHash || entry.Hash != entry.ComputeHash() {
+				verification.Valid = false
+				verification.BrokenAt = &entry.ID
+				return verification, nil
+			}
+			verification.Checked++
+			prevHash, lastID = entry.Hash, entry.ID
+		}
+
+		if len(entries) < auditVerifyBatch {
+			return verification, nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockAuditRepo := new(mocks.AuditRepo)
+	auditService := NewAuditService(mockAuditRepo, sqlxDB)
+	ctx := WithAudit(context.Background(), "req-1", 9)
+
+	mockAuditRepo.On("LockChain", ctx, mock.Anything).Return("abc", nil).Once()
+	mockAuditRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionRoleChanged,
+		TargetType: models.AuditTargetUser,
+		TargetID:   "2",
+		Before:     models.AuditState(map[string]string{"role": models.RoleEmployee}),
+		After:      models.AuditState(map[string]string{"role": models.RoleAdmin}),
+	}
+	err := auditService.Record(ctx, nil, entry)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "req-1", entry.RequestID)
+	assert.Equal(t, 9, *entry.ActorID)
+	assert.Equal(t, "abc", entry.PrevHash)
+	assert.Equal(t, entry.ComputeHash(), entry.Hash)
+
+	mockAuditRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestAuditService_Verify(t *testing.T) {
+	chain := func() []models.AuditEntry {
+		entries := make([]models.AuditEntry, 3)
+		prevHash := ""
+		for i := range entries {
+			entries[i] = models.AuditEntry{ID: i + 1, Action: models.AuditActionLogin, TargetID: "1",
+				PrevHash: prevHash}
+			entries[i].Hash = entries[i].ComputeHash()
+			prevHash = entries[i].Hash
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name     string
+		tamper   func(entries []models.AuditEntry) []models.AuditEntry
+		valid    bool
+		checked  int
+		brokenAt int
+	}{
+		{
+			name:    "Untouched chain",
+			tamper:  func(entries []models.AuditEntry) []models.AuditEntry { return entries },
+			valid:   true,
+			checked: 3,
+		},
+		{
+			name: "Edited entry",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				entries[1].TargetID = "2"
+				return entries
+			},
+			checked:  1,
+			brokenAt: 2,
+		},
+		{
+			name: "Removed entry",
+			tamper: func(entries []models.AuditEntry) []models.AuditEntry {
+				return append(entries[:1], entries[2:]...)
+			},
+			checked:  1,
+			brokenAt: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAuditRepo := new(mocks.AuditRepo)
+			auditService := NewAuditService(mockAuditRepo, nil)
+			ctx := context.Background()
+
+			mockAuditRepo.On("GetChain", ctx, 0, auditVerifyBatch).Return(tt.tamper(chain()), nil).Once()
+
+			verification, err := auditService.Verify(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.valid, verification.Valid)
+			assert.Equal(t, tt.checked, verification.Checked)
+			if tt.valid {
+				assert.Nil(t, verification.BrokenAt)
+			} else {
+				assert.Equal(t, tt.brokenAt, *verification.BrokenAt)
+			}
+
+			mockAuditRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAuditService_List_Limit(t *testing.T) {
+	mockAuditRepo := new(mocks.AuditRepo)
+	auditService := NewAuditService(mockAuditRepo, nil)
+	ctx := context.Background()
+
+	mockAuditRepo.On("GetAll", ctx, mock.MatchedBy(func(f models.AuditFilter) bool {
+		return f.Limit == maxAuditLimit && f.Action == models.AuditActionLogin
+	})).Return(nil, nil).Once()
+
+	entries, err := auditService.List(ctx, models.AuditFilter{Action: models.AuditActionLogin, Limit: 10000})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = auditService.List(ctx, models.AuditFilter{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidAuditFilter)
+
+	mockAuditRepo.AssertExpectations(t)
+}
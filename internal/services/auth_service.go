@@ -2,54 +2,83 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
+	"strconv"
 )
 
+var ErrUnknownTenant = errors.New("services: unknown tenant")
+
 type AuthService interface {
 	Auth(ctx context.Context, username string, password string) (string, error)
 }
 
 type authService struct {
-	userRepo repository.UserRepo
-	secret   string
+	userRepo        repository.UserRepo
+	tenantRepo      repository.TenantRepo
+	transactionRepo repository.TransactionRepo
+	issuanceRepo    repository.IssuanceRepo
+	auditService    AuditService
+	db              *sqlx.DB
+	secret          string
 }
 
-func NewAuthService(userRepo repository.UserRepo, secret string) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepo,
+	tenantRepo repository.TenantRepo,
+	transactionRepo repository.TransactionRepo,
+	issuanceRepo repository.IssuanceRepo,
+	auditService AuditService,
+	db *sqlx.DB,
+	secret string,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		secret:   secret,
+		userRepo:        userRepo,
+		tenantRepo:      tenantRepo,
+		transactionRepo: transactionRepo,
+		issuanceRepo:    issuanceRepo,
+		auditService:    auditService,
+		db:              db,
+		secret:          secret,
 	}
 }
 
+// Auth signs the user in to the tenant of the context, signing them up with
+// the tenant's starting balance on the first visit. The token carries the
+// tenant, so it cannot be used in another one.
 func (s *authService) Auth(ctx context.Context, username string, password string) (string, error) {
+	tenantID := repository.TenantFromContext(ctx)
+
 	user, err := s.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return "", fmt.Errorf("services: failed to get user by username: %w", err)
 	}
 	if user == nil {
-		newUser := &models.User{
-			Username:     username,
-			PasswordHash: password,
-			Balance:      1000,
-		}
-
-		if err := s.userRepo.Create(ctx, newUser); err != nil {
-			return "", fmt.Errorf("services: failed to create user: %w", err)
+		user, err = s.signup(ctx, tenantID, username, password)
+		if err != nil {
+			return "", err
 		}
-
-		user = newUser
 	} else {
 		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+			if auditErr := s.audit(ctx, nil, models.AuditActionLoginFailed, user, &username); auditErr != nil {
+				return "", auditErr
+			}
 			return "", fmt.Errorf("services: invalid password: %w", err)
 		}
+
+		if err := s.audit(ctx, nil, models.AuditActionLogin, user, nil); err != nil {
+			return "", err
+		}
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"sub": user.ID,
+		"sub":    user.ID,
+		"tenant": tenantID,
 	})
 
 	tokenString, err := token.SignedString([]byte(s.secret))
@@ -59,3 +88,96 @@ func (s *authService) Auth(ctx context.Context, username string, password string
 
 	return tokenString, nil
 }
+
+// signup creates the user and issues the tenant's starting balance to them
+// from the issuance account, so the coins are on the ledger like any other
+// issuance.
+func (s *authService) signup(
+	ctx context.Context, tenantID int, username string, password string) (user *models.User, err error) {
+	tenant, err := s.tenantRepo.GetByID(ctx, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get tenant: %w", err)
+	}
+	if tenant == nil {
+		return nil, ErrUnknownTenant
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	user = &models.User{
+		Username:     username,
+		PasswordHash: password,
+	}
+
+	if err := s.userRepo.Create(ctx, tx, user); err != nil {
+		return nil, fmt.Errorf("services: failed to create user: %w", err)
+	}
+
+	if tenant.StartingBalance > 0 {
+		issuance := &models.Issuance{
+			Kind:       models.IssuanceKindSignup,
+			Reason:     "starting balance",
+			Total:      tenant.StartingBalance,
+			Recipients: 1,
+		}
+
+		if err := s.issuanceRepo.Create(ctx, tx, issuance); err != nil {
+			return nil, fmt.Errorf("services: failed to create issuance: %w", err)
+		}
+
+		err := s.userRepo.Credit(ctx, tx, user.ID, tenant.StartingBalance, models.CoinSourceSignup)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to update balance: %w", err)
+		}
+
+		transaction := &models.Transaction{
+			SenderID:   models.IssuanceAccountID,
+			ReceiverID: user.ID,
+			Amount:     tenant.StartingBalance,
+			Type:       models.TransactionTypeSignup,
+			IssuanceID: &issuance.ID,
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+		}
+
+		user.Balance = tenant.StartingBalance
+	}
+
+	if err := s.audit(ctx, tx, models.AuditActionSignup, user, nil); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// audit records a login attempt on the user's account. A failed attempt is
+// made by whoever typed the username, not by the account owner.
+func (s *authService) audit(
+	ctx context.Context, tx *sqlx.Tx, action string, user *models.User, actorName *string) error {
+	entry := &models.AuditEntry{
+		Action:     action,
+		ActorName:  actorName,
+		TargetType: models.AuditTargetUser,
+		TargetID:   strconv.Itoa(user.ID),
+	}
+	if actorName == nil {
+		entry.ActorID = &user.ID
+	}
+
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit %s: %w", action, err)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -39,8 +40,8 @@ func (m *MockUserRepo) GetByUsername(ctx context.Context, username string) (*mod
 	return args.Get(0).(*models.User), args.Error(1)
 }
 
-func (m *MockUserRepo) Create(ctx context.Context, user *models.User) error {
-	args := m.Called(ctx, user)
+func (m *MockUserRepo) Create(ctx context.Context, tx *sqlx.Tx, user *models.User) error {
+	args := m.Called(ctx, tx, user)
 	return args.Error(0)
 }
 
@@ -48,26 +49,89 @@ func (m *MockUserRepo) UpdateBalance(ctx context.Context, tx *sqlx.Tx, userID in
 	return nil
 }
 
+func (m *MockUserRepo) Credit(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string) error {
+	args := m.Called(ctx, tx, userID, amount, source)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) Debit(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	return nil
+}
+
+func (m *MockUserRepo) Overdraw(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	return nil
+}
+
+func (m *MockUserRepo) Hold(ctx context.Context, tx *sqlx.Tx, userID int, amount int, hold models.CoinHold) error {
+	return nil
+}
+
+func (m *MockUserRepo) Release(
+	ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string, hold models.CoinHold) error {
+	return nil
+}
+
+func (m *MockUserRepo) MoveHold(
+	ctx context.Context, tx *sqlx.Tx, userID int, from models.CoinHold, to models.CoinHold) error {
+	return nil
+}
+
 func (m *MockUserRepo) UpdateInventory(ctx context.Context, tx *sqlx.Tx, userID int, inventory []models.UserInventoryItem) error {
 	return nil
 }
 
-func (m *MockUserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, existingQuantity *int) error {
+func (m *MockUserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, existingQuantity *int) error {
+	return nil
+}
+
+func (m *MockUserRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error {
+	return nil
+}
+
+func (m *MockUserRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error {
 	return nil
 }
 
-func (m *MockUserRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
+func (m *MockUserRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, quantity int) error {
 	return nil
 }
 
-func (m *MockUserRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
+func (m *MockUserRepo) RemoveFromInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, quantity int) (bool, error) {
+	return true, nil
+}
+
+func (m *MockUserRepo) LockInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int) (int, error) {
+	return 0, nil
+}
+
+func (m *MockUserRepo) GetRole(ctx context.Context, userID int) (string, error) {
+	return models.RoleEmployee, nil
+}
+
+func (m *MockUserRepo) UpdateRole(ctx context.Context, tx *sqlx.Tx, userID int, role string) error {
 	return nil
 }
 
-func (m *MockUserRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
+func (m *MockUserRepo) GetAutoAcceptTransfers(ctx context.Context, userID int) (bool, error) {
+	return true, nil
+}
+
+func (m *MockUserRepo) SetAutoAcceptTransfers(ctx context.Context, userID int, autoAccept bool) error {
 	return nil
 }
 
+func (m *MockUserRepo) GetAllowance(ctx context.Context, userID int, period string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockUserRepo) SpendAllowance(ctx context.Context, tx *sqlx.Tx, userID int, period string, amount int) (bool, error) {
+	return true, nil
+}
+
+func (m *MockUserRepo) RefillAllowances(ctx context.Context, amount int, period string) (int, error) {
+	return 0, nil
+}
+
 func TestAuthService_Auth(t *testing.T) {
 	secret := "your-secret-key"
 
@@ -76,8 +140,11 @@ func TestAuthService_Auth(t *testing.T) {
 		username          string
 		password          string
 		mockSetup         func(m *MockUserRepo)
+		tenantSetup       func(m *mocks.TenantRepo)
 		expectErrorSubstr string
 		expectedSubClaim  int
+		auditAction       string
+		signup            bool
 	}{
 		{
 			name:     "Existing user with correct password",
@@ -95,6 +162,7 @@ func TestAuthService_Auth(t *testing.T) {
 			},
 			expectErrorSubstr: "",
 			expectedSubClaim:  1,
+			auditAction:       models.AuditActionLogin,
 		},
 		{
 			name:     "Existing user with incorrect password",
@@ -112,6 +180,7 @@ func TestAuthService_Auth(t *testing.T) {
 			},
 			expectErrorSubstr: "services: invalid password:",
 			expectedSubClaim:  0,
+			auditAction:       models.AuditActionLoginFailed,
 		},
 		{
 			name:     "New user creation",
@@ -119,12 +188,21 @@ func TestAuthService_Auth(t *testing.T) {
 			password: "newpassword",
 			mockSetup: func(m *MockUserRepo) {
 				m.On("GetByUsername", mock.Anything, "newuser").Return(nil, nil).Once()
-				m.On("Create", mock.Anything, mock.MatchedBy(func(u *models.User) bool {
-					return u.Username == "newuser" && u.PasswordHash == "newpassword" && u.Balance == 1000
-				})).Return(nil)
+				m.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(u *models.User) bool {
+					return u.Username == "newuser" && u.PasswordHash == "newpassword" && u.Balance == 0
+				})).Run(func(args mock.Arguments) {
+					args.Get(2).(*models.User).ID = 7
+				}).Return(nil).Once()
+				m.On("Credit", mock.Anything, mock.Anything, 7, 750, models.CoinSourceSignup).Return(nil).Once()
+			},
+			tenantSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByID", mock.Anything, models.DefaultTenantID).
+					Return(&models.Tenant{ID: models.DefaultTenantID, StartingBalance: 750}, nil).Once()
 			},
 			expectErrorSubstr: "",
-			expectedSubClaim:  0,
+			expectedSubClaim:  7,
+			auditAction:       models.AuditActionSignup,
+			signup:            true,
 		},
 		{
 			name:     "New user creation failed - database error",
@@ -132,10 +210,15 @@ func TestAuthService_Auth(t *testing.T) {
 			password: "newpassword",
 			mockSetup: func(m *MockUserRepo) {
 				m.On("GetByUsername", mock.Anything, "newuser").Return(nil, nil).Once()
-				m.On("Create", mock.Anything, mock.Anything).Return(errors.New("database error"))
+				m.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("database error"))
+			},
+			tenantSetup: func(m *mocks.TenantRepo) {
+				m.On("GetByID", mock.Anything, models.DefaultTenantID).
+					Return(&models.Tenant{ID: models.DefaultTenantID, StartingBalance: 1000}, nil).Once()
 			},
 			expectErrorSubstr: "services: failed to create user: database error",
 			expectedSubClaim:  0,
+			signup:            true,
 		},
 		{
 			name:     "Database error during GetByUsername",
@@ -151,10 +234,52 @@ func TestAuthService_Auth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			if tt.signup {
+				sqlMock.ExpectBegin()
+				if tt.expectErrorSubstr != "" {
+					sqlMock.ExpectRollback()
+				} else {
+					sqlMock.ExpectCommit()
+				}
+			}
+
 			mockUserRepo := new(MockUserRepo)
 			tt.mockSetup(mockUserRepo)
+			mockTenantRepo := new(mocks.TenantRepo)
+			if tt.tenantSetup != nil {
+				tt.tenantSetup(mockTenantRepo)
+			}
+			mockTransactionRepo := new(mocks.TransactionRepo)
+			mockIssuanceRepo := new(mocks.IssuanceRepo)
+			if tt.signup && tt.expectErrorSubstr == "" {
+				mockIssuanceRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(i *models.Issuance) bool {
+					return i.Kind == models.IssuanceKindSignup && i.Total == 750 && i.Recipients == 1
+				})).Run(func(args mock.Arguments) {
+					args.Get(2).(*models.Issuance).ID = 4
+				}).Return(nil).Once()
+				mockTransactionRepo.On("Create", mock.Anything, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+					return tr.SenderID == models.IssuanceAccountID && tr.ReceiverID == 7 && tr.Amount == 750 &&
+						tr.Type == models.TransactionTypeSignup && *tr.IssuanceID == 4
+				})).Return(nil).Once()
+			}
+			mockAuditService := new(mocks.AuditService)
+			if tt.auditAction != "" {
+				// A sign-up is audited in the transaction that creates the user.
+				var tx interface{} = mock.Anything
+				if !tt.signup {
+					tx = (*sqlx.Tx)(nil)
+				}
+				mockAuditService.On("Record", mock.Anything, tx,
+					mock.MatchedBy(func(e *models.AuditEntry) bool {
+						return e.Action == tt.auditAction && e.TargetType == models.AuditTargetUser
+					})).Return(nil).Once()
+			}
 
-			authService := NewAuthService(mockUserRepo, secret)
+			authService := NewAuthService(mockUserRepo, mockTenantRepo, mockTransactionRepo, mockIssuanceRepo,
+				mockAuditService, sqlxDB, secret)
 			tokenString, err := authService.Auth(context.Background(), tt.username, tt.password)
 
 			if tt.expectErrorSubstr != "" {
@@ -178,12 +303,18 @@ func TestAuthService_Auth(t *testing.T) {
 					assert.True(t, ok, "claim sub must be a number")
 					subClaim := int(subClaimFloat)
 					assert.Equal(t, tt.expectedSubClaim, subClaim)
+					assert.Equal(t, float64(models.DefaultTenantID), claims["tenant"])
 				} else {
 					t.Error("Invalid token claims")
 				}
 			}
 
 			mockUserRepo.AssertExpectations(t)
+			mockTenantRepo.AssertExpectations(t)
+			mockTransactionRepo.AssertExpectations(t)
+			mockIssuanceRepo.AssertExpectations(t)
+			mockAuditService.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
 		})
 	}
 }
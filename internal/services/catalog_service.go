@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrItemNotFound      = errors.New("services: item not found")
+	ErrInvalidPriceRule  = errors.New("services: invalid price rule")
+	ErrPriceRuleNotFound = errors.New("services: price rule not found")
+	ErrInvalidStock      = errors.New("services: invalid stock")
+)
+
+type CatalogService interface {
+	GetCatalog(ctx context.Context) ([]models.CatalogItem, error)
+	CreatePriceRule(ctx context.Context, itemName string, rule *models.PriceRule) error
+	GetPriceRules(ctx context.Context) ([]models.PriceRule, error)
+	DeletePriceRule(ctx context.Context, ruleID int) error
+	GetPriceHistory(ctx context.Context, itemName string) ([]models.PriceHistory, error)
+	SetStock(ctx context.Context, itemName string, size string, color string, stock *int) error
+	SetPreorderEnabled(ctx context.Context, itemName string, enabled bool) error
+}
+
+type catalogService struct {
+	itemRepo         repository.ItemRepo
+	priceRuleRepo    repository.PriceRuleRepo
+	wishlistRepo     repository.WishlistRepo
+	notificationRepo repository.NotificationRepo
+	preorderService  PreorderService
+	auditService     AuditService
+	db               *sqlx.DB
+}
+
+func NewCatalogService(
+	itemRepo repository.ItemRepo,
+	priceRuleRepo repository.PriceRuleRepo,
+	wishlistRepo repository.WishlistRepo,
+	notificationRepo repository.NotificationRepo,
+	preorderService PreorderService,
+	auditService AuditService,
+	db *sqlx.DB,
+) CatalogService {
+	return &catalogService{
+		itemRepo:         itemRepo,
+		priceRuleRepo:    priceRuleRepo,
+		wishlistRepo:     wishlistRepo,
+		notificationRepo: notificationRepo,
+		preorderService:  preorderService,
+		auditService:     auditService,
+		db:               db,
+	}
+}
+
+// GetCatalog lists the items on sale with their current price, the price
+// before any active price rule and the time the sale ends.
+func (s *catalogService) GetCatalog(ctx context.Context) ([]models.CatalogItem, error) {
+	items, err := s.itemRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get items: %w", err)
+	}
+
+	rules, err := s.priceRuleRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get price rules: %w", err)
+	}
+
+	catalog := make([]models.CatalogItem, 0, len(items))
+	for _, item := range items {
+		if item.ArchivedAt != nil {
+			continue
+		}
+
+		variants, err := s.itemRepo.GetVariants(ctx, item.ID)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to get item variants: %w", err)
+		}
+
+		price, rule := models.SalePrice(item, item.Price, rules)
+		entry := models.CatalogItem{
+			Name:          item.Name,
+			Category:      item.Category,
+			Price:         price,
+			OriginalPrice: item.Price,
+			SaleEndsAt:    saleEnd(rule),
+			Variants:      make([]models.CatalogVariant, 0, len(variants)),
+		}
+
+		for _, v := range variants {
+			original := v.UnitPrice(item)
+			price, rule := models.SalePrice(item, original, rules)
+			entry.Variants = append(entry.Variants, models.CatalogVariant{
+				Size:          v.Size,
+				Color:         v.Color,
+				Price:         price,
+				OriginalPrice: original,
+				SaleEndsAt:    saleEnd(rule),
+				Stock:         v.Stock,
+			})
+		}
+
+		catalog = append(catalog, entry)
+	}
+
+	return catalog, nil
+}
+
+// CreatePriceRule schedules a price change for the named item, or for the
+// rule's category when no item name is given. The rule and its audit entry
+// are written in one transaction.
+func (s *catalogService) CreatePriceRule(ctx context.Context, itemName string, rule *models.PriceRule) (err error) {
+	if itemName != "" {
+		item, err := s.itemRepo.GetItemByName(ctx, itemName)
+		if err != nil {
+			return fmt.Errorf("services: failed to get item by name: %w", err)
+		}
+		if item == nil {
+			return ErrItemNotFound
+		}
+		rule.ItemID = &item.ID
+	}
+
+	if rule.Category != nil {
+		category := strings.TrimSpace(*rule.Category)
+		rule.Category = &category
+	}
+
+	if (rule.ItemID == nil) == (rule.Category == nil || *rule.Category == "") {
+		return ErrInvalidPriceRule
+	}
+	if (rule.Price == nil) == (rule.DiscountPercent == nil) {
+		return ErrInvalidPriceRule
+	}
+	if rule.Price != nil && *rule.Price < 0 {
+		return ErrInvalidPriceRule
+	}
+	if rule.DiscountPercent != nil && (*rule.DiscountPercent < 1 || *rule.DiscountPercent > 100) {
+		return ErrInvalidPriceRule
+	}
+	if !rule.EndsAt.After(rule.StartsAt) {
+		return ErrInvalidPriceRule
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	if err := s.priceRuleRepo.Create(ctx, tx, rule); err != nil {
+		return fmt.Errorf("services: failed to create price rule: %w", err)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionPriceRuleCreated,
+		TargetType: models.AuditTargetPriceRule,
+		TargetID:   strconv.Itoa(rule.ID),
+		After:      models.AuditState(rule),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit price rule: %w", err)
+	}
+	return nil
+}
+
+func (s *catalogService) GetPriceRules(ctx context.Context) ([]models.PriceRule, error) {
+	rules, err := s.priceRuleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get price rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *catalogService) DeletePriceRule(ctx context.Context, ruleID int) (err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	deleted, err := s.priceRuleRepo.Delete(ctx, tx, ruleID)
+	if err != nil {
+		return fmt.Errorf("services: failed to delete price rule: %w", err)
+	}
+	if !deleted {
+		return ErrPriceRuleNotFound
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionPriceRuleDeleted,
+		TargetType: models.AuditTargetPriceRule,
+		TargetID:   strconv.Itoa(ruleID),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit price rule: %w", err)
+	}
+	return nil
+}
+
+func (s *catalogService) GetPriceHistory(ctx context.Context, itemName string) ([]models.PriceHistory, error) {
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+
+	history, err := s.priceRuleRepo.GetHistory(ctx, item.ID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get price history: %w", err)
+	}
+	return history, nil
+}
+
+// SetStock replaces the stock of an item variant. When a variant is
+// replenished, open pre-orders are fulfilled first, in the same transaction,
+// and if it was sold out everyone who wishlisted the item is notified.
+func (s *catalogService) SetStock(ctx context.Context, itemName string, size string, color string, stock *int) error {
+	if stock != nil && *stock < 0 {
+		return ErrInvalidStock
+	}
+
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return ErrItemNotFound
+	}
+
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, size, color)
+	if err != nil {
+		return err
+	}
+
+	return s.updateStock(ctx, item, variant.ID, stock)
+}
+
+func (s *catalogService) updateStock(ctx context.Context, item *models.Item, variantID int, stock *int) (err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	previous, err := s.itemRepo.SetStock(ctx, tx, variantID, stock)
+	if err != nil {
+		return fmt.Errorf("services: failed to set stock: %w", err)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionStockChanged,
+		TargetType: models.AuditTargetVariant,
+		TargetID:   strconv.Itoa(variantID),
+		Before:     models.AuditState(map[string]*int{"stock": previous}),
+		After:      models.AuditState(map[string]*int{"stock": stock}),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit stock: %w", err)
+	}
+
+	restocked := stock == nil || *stock > 0
+	if !restocked {
+		return nil
+	}
+
+	captured, err := s.preorderService.Capture(ctx, tx, variantID)
+	if err != nil {
+		return err
+	}
+
+	soldOut := previous != nil && *previous == 0
+	if !soldOut || item.ArchivedAt != nil {
+		return nil
+	}
+
+	// Pre-orders are served first; wishlisters only hear about what is left.
+	if captured > 0 {
+		left, err := s.itemRepo.GetStock(ctx, tx, variantID)
+		if err != nil {
+			return fmt.Errorf("services: failed to get stock: %w", err)
+		}
+		if left != nil && *left == 0 {
+			return nil
+		}
+	}
+
+	userIDs, err := s.wishlistRepo.GetUserIDsByItem(ctx, tx, item.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get wishlisting users: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		notification := &models.Notification{
+			UserID:  userID,
+			Type:    models.NotificationTypeRestock,
+			Message: fmt.Sprintf("%s is back in stock", item.Name),
+		}
+
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return fmt.Errorf("services: failed to notify wishlisting user: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (s *catalogService) SetPreorderEnabled(ctx context.Context, itemName string, enabled bool) (err error) {
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return ErrItemNotFound
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	if err := s.itemRepo.SetPreorderEnabled(ctx, tx, item.ID, enabled); err != nil {
+		return fmt.Errorf("services: failed to update item: %w", err)
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionPreorderChanged,
+		TargetType: models.AuditTargetItem,
+		TargetID:   strconv.Itoa(item.ID),
+		Before:     models.AuditState(map[string]bool{"preorderEnabled": item.PreorderEnabled}),
+		After:      models.AuditState(map[string]bool{"preorderEnabled": enabled}),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit item: %w", err)
+	}
+	return nil
+}
+
+func saleEnd(rule *models.PriceRule) *time.Time {
+	if rule == nil {
+		return nil
+	}
+	return &rule.EndsAt
+}
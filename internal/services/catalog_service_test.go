@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCatalogService_SetStock(t *testing.T) {
+	zero := 0
+	three := 3
+	five := 5
+
+	tests := []struct {
+		name     string
+		previous *int
+		stock    *int
+		captured int
+		left     *int
+		notified bool
+	}{
+		{
+			name:     "Sold out variant restocked",
+			previous: &zero,
+			stock:    &five,
+			notified: true,
+		},
+		{
+			name:     "Sold out variant made unlimited",
+			previous: &zero,
+			stock:    nil,
+			notified: true,
+		},
+		{
+			name:     "Restock partly taken by pre-orders",
+			previous: &zero,
+			stock:    &five,
+			captured: 1,
+			left:     &three,
+			notified: true,
+		},
+		{
+			name:     "Restock taken by pre-orders",
+			previous: &zero,
+			stock:    &five,
+			captured: 2,
+			left:     &zero,
+		},
+		{
+			name:     "Variant still in stock",
+			previous: &five,
+			stock:    &five,
+		},
+		{
+			name:     "Variant sold out again",
+			previous: &five,
+			stock:    &zero,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectCommit()
+
+			mockItemRepo := new(mocks.ItemRepo)
+			mockWishlistRepo := new(mocks.WishlistRepo)
+			mockNotificationRepo := new(mocks.NotificationRepo)
+			mockPreorderService := new(mocks.PreorderService)
+			mockAuditService := new(mocks.AuditService)
+
+			catalogService := NewCatalogService(
+				mockItemRepo, nil, mockWishlistRepo, mockNotificationRepo, mockPreorderService, mockAuditService,
+				sqlxDB)
+			ctx := context.Background()
+
+			item := &models.Item{ID: 10, Name: "pink-hoody", Price: 500}
+
+			mockItemRepo.On("GetItemByName", ctx, "pink-hoody").Return(item, nil).Once()
+			mockItemRepo.On("GetVariants", ctx, 10).Return([]models.ItemVariant{{ID: 12, ItemID: 10}}, nil).Once()
+			mockItemRepo.On("SetStock", ctx, mock.Anything, 12, tt.stock).Return(tt.previous, nil).Once()
+			mockWishlistRepo.On("GetUserIDsByItem", ctx, mock.Anything, 10).Return([]int{1, 2}, nil).Maybe()
+			mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+				return n.Type == models.NotificationTypeRestock
+			})).Return(nil).Maybe()
+			mockPreorderService.On("Capture", ctx, mock.Anything, 12).Return(tt.captured, nil).Maybe()
+			mockItemRepo.On("GetStock", ctx, mock.Anything, 12).Return(tt.left, nil).Maybe()
+			mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+				return e.Action == models.AuditActionStockChanged && e.TargetID == "12"
+			})).Return(nil).Once()
+
+			err := catalogService.SetStock(ctx, "pink-hoody", "", "", tt.stock)
+			assert.NoError(t, err)
+
+			if tt.stock == nil || *tt.stock > 0 {
+				mockPreorderService.AssertCalled(t, "Capture", ctx, mock.Anything, 12)
+			} else {
+				mockPreorderService.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything)
+			}
+			if tt.notified {
+				mockNotificationRepo.AssertNumberOfCalls(t, "Create", 2)
+			} else {
+				mockNotificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockAuditService.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCatalogService_SetStock_CaptureFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockItemRepo := new(mocks.ItemRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPreorderService := new(mocks.PreorderService)
+	mockAuditService := new(mocks.AuditService)
+
+	catalogService := NewCatalogService(
+		mockItemRepo, nil, nil, mockNotificationRepo, mockPreorderService, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	zero := 0
+	five := 5
+	item := &models.Item{ID: 10, Name: "pink-hoody", Price: 500}
+
+	mockItemRepo.On("GetItemByName", ctx, "pink-hoody").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 10).Return([]models.ItemVariant{{ID: 12, ItemID: 10}}, nil).Once()
+	mockItemRepo.On("SetStock", ctx, mock.Anything, 12, &five).Return(&zero, nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockPreorderService.On("Capture", ctx, mock.Anything, 12).Return(0, errors.New("database error")).Once()
+
+	err := catalogService.SetStock(ctx, "pink-hoody", "", "", &five)
+	assert.Error(t, err)
+
+	mockNotificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	mockPreorderService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCatalogService_CreatePriceRule_Invalid(t *testing.T) {
+	category := "apparel"
+	price := 200
+	percent := 10
+
+	tests := []struct {
+		name string
+		rule models.PriceRule
+	}{
+		{
+			name: "No target",
+			rule: models.PriceRule{Price: &price},
+		},
+		{
+			name: "Both fixed price and percentage",
+			rule: models.PriceRule{Category: &category, Price: &price, DiscountPercent: &percent},
+		},
+		{
+			name: "Ends before it starts",
+			rule: models.PriceRule{Category: &category, Price: &price},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalogService := NewCatalogService(nil, nil, nil, nil, nil, nil, nil)
+
+			err := catalogService.CreatePriceRule(context.Background(), "", &tt.rule)
+			assert.ErrorIs(t, err, ErrInvalidPriceRule)
+		})
+	}
+}
+
+func TestCatalogService_DeletePriceRule_AuditFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockPriceRuleRepo := new(mocks.PriceRuleRepo)
+	mockAuditService := new(mocks.AuditService)
+
+	catalogService := NewCatalogService(nil, mockPriceRuleRepo, nil, nil, nil, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockPriceRuleRepo.On("Delete", ctx, mock.Anything, 4).Return(true, nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionPriceRuleDeleted && e.TargetID == "4"
+	})).Return(errors.New("database error")).Once()
+
+	err := catalogService.DeletePriceRule(ctx, 4)
+	assert.Error(t, err)
+
+	mockPriceRuleRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCatalogService_SetPreorderEnabled(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockItemRepo := new(mocks.ItemRepo)
+	mockAuditService := new(mocks.AuditService)
+
+	catalogService := NewCatalogService(mockItemRepo, nil, nil, nil, nil, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockItemRepo.On("GetItemByName", ctx, "pink-hoody").Return(&models.Item{ID: 10, Name: "pink-hoody"}, nil).Once()
+	mockItemRepo.On("SetPreorderEnabled", ctx, mock.Anything, 10, true).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionPreorderChanged && e.TargetID == "10"
+	})).Return(nil).Once()
+
+	err := catalogService.SetPreorderEnabled(ctx, "pink-hoody", true)
+	assert.NoError(t, err)
+
+	mockItemRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+type CoinExpiryService interface {
+	ExpireDue(ctx context.Context) (int, error)
+}
+
+type coinExpiryService struct {
+	userRepo         repository.UserRepo
+	transactionRepo  repository.TransactionRepo
+	notificationRepo repository.NotificationRepo
+	coinLotRepo      repository.CoinLotRepo
+	db               *sqlx.DB
+}
+
+func NewCoinExpiryService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	coinLotRepo repository.CoinLotRepo,
+	db *sqlx.DB,
+) CoinExpiryService {
+	return &coinExpiryService{
+		userRepo:         userRepo,
+		transactionRepo:  transactionRepo,
+		notificationRepo: notificationRepo,
+		coinLotRepo:      coinLotRepo,
+		db:               db,
+	}
+}
+
+// ExpireDue moves every expired coin to the forfeiture account, one user
+// per transaction, and returns the number of coins forfeited.
+func (s *coinExpiryService) ExpireDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	userIDs, err := s.coinLotRepo.GetExpiredUserIDs(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get users with expired coins: %w", err)
+	}
+
+	forfeited := 0
+	var errs []error
+	for _, userID := range userIDs {
+		amount, err := s.expire(ctx, userID, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		forfeited += amount
+	}
+
+	return forfeited, errors.Join(errs...)
+}
+
+func (s *coinExpiryService) expire(ctx context.Context, userID int, at time.Time) (amount int, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	amount, err = s.coinLotRepo.Expire(ctx, tx, userID, at)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to expire coins of user %d: %w", userID, err)
+	}
+	if amount == 0 {
+		return 0, nil
+	}
+
+	if err := s.userRepo.UpdateBalance(ctx, tx, userID, -amount); err != nil {
+		return 0, fmt.Errorf("services: failed to update balance: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   userID,
+		ReceiverID: models.ForfeitureAccountID,
+		Amount:     amount,
+		Type:       models.TransactionTypeExpiry,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return 0, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    models.NotificationTypeCoinsExpired,
+		Message: fmt.Sprintf("%d coins expired 12 months after you earned them", amount),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return 0, fmt.Errorf("services: failed to notify user: %w", err)
+	}
+
+	return amount, nil
+}
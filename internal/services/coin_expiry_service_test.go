@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCoinExpiryService_ExpireDue(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockCoinLotRepo := new(mocks.CoinLotRepo)
+	expiryService := NewCoinExpiryService(mockUserRepo, mockTransactionRepo, mockNotificationRepo, mockCoinLotRepo, sqlxDB)
+	ctx := context.Background()
+
+	mockCoinLotRepo.On("GetExpiredUserIDs", ctx, mock.Anything).Return([]int{1, 2}, nil).Once()
+	mockCoinLotRepo.On("Expire", ctx, mock.Anything, 1, mock.Anything).Return(80, nil).Once()
+	mockCoinLotRepo.On("Expire", ctx, mock.Anything, 2, mock.Anything).Return(0, nil).Once()
+	mockUserRepo.On("UpdateBalance", ctx, mock.Anything, 1, -80).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == models.ForfeitureAccountID && tr.Amount == 80 &&
+			tr.Type == models.TransactionTypeExpiry
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeCoinsExpired
+	})).Return(nil).Once()
+
+	forfeited, err := expiryService.ExpireDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 80, forfeited)
+
+	mockUserRepo.AssertNotCalled(t, "UpdateBalance", ctx, mock.Anything, 2, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockCoinLotRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUserNotFound         = errors.New("services: user not found")
+	ErrCoinRequestNotFound  = errors.New("services: coin request not found")
+	ErrInvalidCoinRequest   = errors.New("services: invalid coin request")
+	ErrDuplicateCoinRequest = errors.New("services: the same request is already pending")
+	ErrTooManyCoinRequests  = errors.New("services: too many open requests to this user")
+	ErrCoinRequestResolved  = errors.New("services: coin request is no longer pending")
+)
+
+type CoinRequestService interface {
+	Create(ctx context.Context, requesterID int, payerName string, amount int, reason string) (*models.CoinRequest, error)
+	List(ctx context.Context, userID int) (*models.CoinRequests, error)
+	Accept(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error)
+	Decline(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error)
+	ExpireOverdue(ctx context.Context) (int, error)
+}
+
+type coinRequestService struct {
+	userRepo         repository.UserRepo
+	coinRequestRepo  repository.CoinRequestRepo
+	notificationRepo repository.NotificationRepo
+	coinService      CoinService
+	db               *sqlx.DB
+	ttl              time.Duration
+	maxOpen          int
+}
+
+func NewCoinRequestService(
+	userRepo repository.UserRepo,
+	coinRequestRepo repository.CoinRequestRepo,
+	notificationRepo repository.NotificationRepo,
+	coinService CoinService,
+	db *sqlx.DB,
+	ttl time.Duration,
+	maxOpen int,
+) CoinRequestService {
+	return &coinRequestService{
+		userRepo:         userRepo,
+		coinRequestRepo:  coinRequestRepo,
+		notificationRepo: notificationRepo,
+		coinService:      coinService,
+		db:               db,
+		ttl:              ttl,
+		maxOpen:          maxOpen,
+	}
+}
+
+// Create asks payerName for coins. A pair may only have maxOpen pending
+// requests at a time, and an identical pending request is rejected.
+func (s *coinRequestService) Create(
+	ctx context.Context, requesterID int, payerName string, amount int, reason string) (request *models.CoinRequest, err error) {
+	reason = strings.TrimSpace(reason)
+	if amount < 1 || reason == "" {
+		return nil, ErrInvalidCoinRequest
+	}
+
+	payer, err := s.userRepo.GetByUsername(ctx, payerName)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if payer == nil {
+		return nil, ErrUserNotFound
+	}
+	if payer.ID == requesterID {
+		return nil, ErrInvalidCoinRequest
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	now := time.Now()
+	open, err := s.coinRequestRepo.GetOpenByPair(ctx, tx, requesterID, payer.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get open requests: %w", err)
+	}
+	for _, r := range open {
+		if r.Amount == amount && r.Reason == reason {
+			return nil, ErrDuplicateCoinRequest
+		}
+	}
+	if len(open) >= s.maxOpen {
+		return nil, ErrTooManyCoinRequests
+	}
+
+	request = &models.CoinRequest{
+		RequesterID: requesterID,
+		PayerID:     payer.ID,
+		Payer:       payer.Username,
+		Amount:      amount,
+		Reason:      reason,
+		Status:      models.CoinRequestStatusPending,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	if err := s.coinRequestRepo.Create(ctx, tx, request); err != nil {
+		return nil, fmt.Errorf("services: failed to create coin request: %w", err)
+	}
+
+	request.Requester, err = s.userRepo.GetUsernameByID(ctx, requesterID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get requester username: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  payer.ID,
+		Type:    models.NotificationTypeCoinRequest,
+		Message: fmt.Sprintf("%s asks you for %d coins: %s", request.Requester, amount, reason),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return nil, fmt.Errorf("services: failed to notify payer: %w", err)
+	}
+
+	return request, nil
+}
+
+func (s *coinRequestService) List(ctx context.Context, userID int) (*models.CoinRequests, error) {
+	incoming, err := s.coinRequestRepo.GetIncoming(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get incoming requests: %w", err)
+	}
+
+	outgoing, err := s.coinRequestRepo.GetOutgoing(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get outgoing requests: %w", err)
+	}
+
+	requests := &models.CoinRequests{
+		Incoming: make([]models.CoinRequest, 0, len(incoming)),
+		Outgoing: outgoing,
+	}
+	if requests.Outgoing == nil {
+		requests.Outgoing = []models.CoinRequest{}
+	}
+
+	now := time.Now()
+	for _, r := range incoming {
+		if now.Before(r.ExpiresAt) {
+			requests.Incoming = append(requests.Incoming, r)
+		}
+	}
+
+	return requests, nil
+}
+
+// Accept pays the request with a regular coin transfer made in the same
+// transaction that resolves the request, so it cannot be paid twice or paid
+// without being resolved.
+func (s *coinRequestService) Accept(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	return s.resolve(ctx, userID, requestID, models.CoinRequestStatusAccepted)
+}
+
+func (s *coinRequestService) Decline(ctx context.Context, userID int, requestID int) (*models.CoinRequest, error) {
+	return s.resolve(ctx, userID, requestID, models.CoinRequestStatusDeclined)
+}
+
+func (s *coinRequestService) resolve(
+	ctx context.Context, userID int, requestID int, status string) (request *models.CoinRequest, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	request, err = s.coinRequestRepo.GetByIDForUpdate(ctx, tx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock coin request: %w", err)
+	}
+	if request == nil || request.PayerID != userID {
+		return nil, ErrCoinRequestNotFound
+	}
+	if request.Status != models.CoinRequestStatusPending || !time.Now().Before(request.ExpiresAt) {
+		return nil, ErrCoinRequestResolved
+	}
+
+	if status == models.CoinRequestStatusAccepted {
+		if err := s.coinService.SendTx(ctx, tx, request.PayerID, request.RequesterID, request.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	request.Status = status
+
+	if err := s.coinRequestRepo.Resolve(ctx, tx, request); err != nil {
+		return nil, fmt.Errorf("services: failed to resolve coin request: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  request.RequesterID,
+		Type:    models.NotificationTypeCoinRequestAnswer,
+		Message: fmt.Sprintf("%s %s your request for %d coins", request.Payer, status, request.Amount),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return nil, fmt.Errorf("services: failed to notify requester: %w", err)
+	}
+
+	return request, nil
+}
+
+func (s *coinRequestService) ExpireOverdue(ctx context.Context) (int, error) {
+	expired, err := s.coinRequestRepo.ExpirePending(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to expire coin requests: %w", err)
+	}
+	return expired, nil
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCoinRequestService_Create(t *testing.T) {
+	tests := []struct {
+		name        string
+		open        []models.CoinRequest
+		amount      int
+		reason      string
+		expectedErr error
+	}{
+		{
+			name:   "Within pair cap",
+			open:   []models.CoinRequest{{ID: 1, Amount: 10, Reason: "lunch"}},
+			amount: 20,
+			reason: "taxi",
+		},
+		{
+			name:        "Duplicate pending request",
+			open:        []models.CoinRequest{{ID: 1, Amount: 20, Reason: "taxi"}},
+			amount:      20,
+			reason:      " taxi ",
+			expectedErr: ErrDuplicateCoinRequest,
+		},
+		{
+			name:        "Pair cap reached",
+			open:        []models.CoinRequest{{ID: 1, Amount: 10, Reason: "a"}, {ID: 2, Amount: 10, Reason: "b"}},
+			amount:      20,
+			reason:      "taxi",
+			expectedErr: ErrTooManyCoinRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockCoinRequestRepo := new(mocks.CoinRequestRepo)
+			mockNotificationRepo := new(mocks.NotificationRepo)
+			coinRequestService := NewCoinRequestService(
+				mockUserRepo, mockCoinRequestRepo, mockNotificationRepo, nil, sqlxDB, time.Hour, 2)
+			ctx := context.Background()
+
+			mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+			mockCoinRequestRepo.On("GetOpenByPair", ctx, mock.Anything, 1, 2, mock.Anything).Return(tt.open, nil).Once()
+			mockCoinRequestRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(r *models.CoinRequest) bool {
+				return r.RequesterID == 1 && r.PayerID == 2 && r.Amount == tt.amount &&
+					r.Status == models.CoinRequestStatusPending
+			})).Return(nil).Maybe()
+			mockUserRepo.On("GetUsernameByID", ctx, 1).Return("alice", nil).Maybe()
+			mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+				return n.UserID == 2 && n.Type == models.NotificationTypeCoinRequest
+			})).Return(nil).Maybe()
+
+			request, err := coinRequestService.Create(ctx, 1, "bob", tt.amount, tt.reason)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, request)
+				mockCoinRequestRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "alice", request.Requester)
+				mockNotificationRepo.AssertExpectations(t)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCoinRequestService_Accept(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockCoinRequestRepo := new(mocks.CoinRequestRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockCoinService := new(mocks.CoinService)
+	coinRequestService := NewCoinRequestService(
+		nil, mockCoinRequestRepo, mockNotificationRepo, mockCoinService, sqlxDB, time.Hour, 2)
+	ctx := context.Background()
+
+	request := &models.CoinRequest{ID: 5, RequesterID: 1, PayerID: 2, Payer: "bob", Amount: 30,
+		Status: models.CoinRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockCoinRequestRepo.On("GetByIDForUpdate", ctx, mock.Anything, 5).Return(request, nil).Once()
+	mockCoinService.On("SendTx", ctx, mock.Anything, 2, 1, 30).Return(nil).Once()
+	mockCoinRequestRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(r *models.CoinRequest) bool {
+		return r.ID == 5 && r.Status == models.CoinRequestStatusAccepted
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeCoinRequestAnswer
+	})).Return(nil).Once()
+
+	accepted, err := coinRequestService.Accept(ctx, 2, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, models.CoinRequestStatusAccepted, accepted.Status)
+
+	mockCoinService.AssertExpectations(t)
+	mockCoinRequestRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCoinRequestService_Accept_SendFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockCoinRequestRepo := new(mocks.CoinRequestRepo)
+	mockCoinService := new(mocks.CoinService)
+	coinRequestService := NewCoinRequestService(
+		nil, mockCoinRequestRepo, nil, mockCoinService, sqlxDB, time.Hour, 2)
+	ctx := context.Background()
+
+	request := &models.CoinRequest{ID: 5, RequesterID: 1, PayerID: 2, Amount: 30,
+		Status: models.CoinRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	sendErr := errors.New("services: insufficient balance")
+
+	mockCoinRequestRepo.On("GetByIDForUpdate", ctx, mock.Anything, 5).Return(request, nil).Once()
+	mockCoinService.On("SendTx", ctx, mock.Anything, 2, 1, 30).Return(sendErr).Once()
+
+	_, err := coinRequestService.Accept(ctx, 2, 5)
+	assert.ErrorIs(t, err, sendErr)
+
+	mockCoinRequestRepo.AssertNotCalled(t, "Resolve", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCoinRequestService_Accept_ResolveFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockCoinRequestRepo := new(mocks.CoinRequestRepo)
+	mockCoinService := new(mocks.CoinService)
+	coinRequestService := NewCoinRequestService(
+		nil, mockCoinRequestRepo, nil, mockCoinService, sqlxDB, time.Hour, 2)
+	ctx := context.Background()
+
+	request := &models.CoinRequest{ID: 5, RequesterID: 1, PayerID: 2, Amount: 30,
+		Status: models.CoinRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockCoinRequestRepo.On("GetByIDForUpdate", ctx, mock.Anything, 5).Return(request, nil).Once()
+	mockCoinService.On("SendTx", ctx, mock.Anything, 2, 1, 30).Return(nil).Once()
+	mockCoinRequestRepo.On("Resolve", ctx, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+	_, err := coinRequestService.Accept(ctx, 2, 5)
+	assert.Error(t, err)
+
+	mockCoinService.AssertExpectations(t)
+	mockCoinRequestRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCoinRequestService_Decline_NotPayer(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockCoinRequestRepo := new(mocks.CoinRequestRepo)
+	coinRequestService := NewCoinRequestService(nil, mockCoinRequestRepo, nil, nil, sqlxDB, time.Hour, 2)
+	ctx := context.Background()
+
+	request := &models.CoinRequest{ID: 5, RequesterID: 1, PayerID: 2, Amount: 30,
+		Status: models.CoinRequestStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+	mockCoinRequestRepo.On("GetByIDForUpdate", ctx, mock.Anything, 5).Return(request, nil).Once()
+
+	_, err := coinRequestService.Decline(ctx, 1, 5)
+	assert.ErrorIs(t, err, ErrCoinRequestNotFound)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
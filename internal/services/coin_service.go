@@ -2,32 +2,65 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	ErrTransferLimitExceeded = errors.New("services: transfer limit exceeded")
+	ErrInsufficientBalance   = errors.New("services: insufficient balance")
+)
+
+// TransferLimitError tells which limit a transfer would break. It matches
+// ErrTransferLimitExceeded with errors.Is.
+type TransferLimitError struct {
+	Code  string
+	Limit int
+}
+
+func (e *TransferLimitError) Error() string {
+	return fmt.Sprintf("%v: %s is %d", ErrTransferLimitExceeded, e.Code, e.Limit)
+}
+
+func (e *TransferLimitError) Unwrap() error {
+	return ErrTransferLimitExceeded
+}
+
 type CoinService interface {
 	Send(ctx context.Context, fromUserID int, toUserID int, amount int) error
+	SendTx(ctx context.Context, tx *sqlx.Tx, fromUserID int, toUserID int, amount int) error
 	GetCoinHistory(ctx context.Context, userID int) (*models.CoinHistory, error)
+	CheckTransferLimits(ctx context.Context, tx *sqlx.Tx, fromUserID int, toUserID int, amount int) error
 }
 
 type coinService struct {
-	userRepo        repository.UserRepo
-	transactionRepo repository.TransactionRepo
-	db              *sqlx.DB
+	userRepo          repository.UserRepo
+	transactionRepo   repository.TransactionRepo
+	transferLimitRepo repository.TransferLimitRepo
+	db                *sqlx.DB
+	limits            models.TransferLimits
 }
 
-func NewCoinService(userRepo repository.UserRepo, transactionRepo repository.TransactionRepo, db *sqlx.DB) CoinService {
+func NewCoinService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	transferLimitRepo repository.TransferLimitRepo,
+	db *sqlx.DB,
+	limits models.TransferLimits,
+) CoinService {
 	return &coinService{
-		userRepo:        userRepo,
-		transactionRepo: transactionRepo,
-		db:              db,
+		userRepo:          userRepo,
+		transactionRepo:   transactionRepo,
+		transferLimitRepo: transferLimitRepo,
+		db:                db,
+		limits:            limits,
 	}
 }
 
-func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount int) error {
+func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount int) (err error) {
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("services: failed to begin transaction: %w", err)
@@ -41,6 +74,13 @@ func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount
 		}
 	}()
 
+	return s.SendTx(ctx, tx, fromUserID, toUserID, amount)
+}
+
+// SendTx makes a transfer in the caller's transaction, so it commits or rolls
+// back together with the caller's other writes. The caller must not commit
+// the transaction when SendTx fails.
+func (s *coinService) SendTx(ctx context.Context, tx *sqlx.Tx, fromUserID, toUserID int, amount int) error {
 	fromUser, err := s.userRepo.GetByID(ctx, fromUserID)
 	if err != nil {
 		return fmt.Errorf("services: failed to get fromUser by id: %w", err)
@@ -58,14 +98,21 @@ func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount
 	}
 
 	if fromUser.Balance < amount {
-		return fmt.Errorf("services: insufficient balance")
+		return ErrInsufficientBalance
+	}
+
+	if err := s.CheckTransferLimits(ctx, tx, fromUserID, toUserID, amount); err != nil {
+		return err
 	}
 
-	if err := s.userRepo.UpdateBalance(ctx, tx, fromUserID, -amount); err != nil {
+	if err := s.userRepo.Debit(ctx, tx, fromUserID, amount); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return ErrInsufficientBalance
+		}
 		return fmt.Errorf("services: failed to update balance of fromUser: %w", err)
 	}
 
-	if err := s.userRepo.UpdateBalance(ctx, tx, toUserID, amount); err != nil {
+	if err := s.userRepo.Credit(ctx, tx, toUserID, amount, models.CoinSourceTransfer); err != nil {
 		return fmt.Errorf("services: failed to update balance of toUser: %w", err)
 	}
 
@@ -73,6 +120,7 @@ func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount
 		SenderID:   fromUserID,
 		ReceiverID: toUserID,
 		Amount:     amount,
+		Type:       models.TransactionTypeTransfer,
 	}
 
 	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
@@ -82,6 +130,49 @@ func (s *coinService) Send(ctx context.Context, fromUserID, toUserID int, amount
 	return nil
 }
 
+// CheckTransferLimits locks the sender and fails with a *TransferLimitError
+// when the transfer would break one of the sender's limits. It must run in
+// the transaction that makes the transfer, so transfers made at the same
+// time are counted one after another.
+func (s *coinService) CheckTransferLimits(
+	ctx context.Context, tx *sqlx.Tx, fromUserID int, toUserID int, amount int) error {
+	role, err := s.transferLimitRepo.LockSender(ctx, tx, fromUserID)
+	if err != nil {
+		return fmt.Errorf("services: failed to lock sender: %w", err)
+	}
+
+	overrides, err := s.transferLimitRepo.GetOverrides(ctx, tx, fromUserID, role)
+	if err != nil {
+		return fmt.Errorf("services: failed to get transfer limits: %w", err)
+	}
+
+	limits := s.limits
+	for _, override := range overrides {
+		limits = override.Apply(limits)
+	}
+
+	if limits.MaxPerTransfer > 0 && amount > limits.MaxPerTransfer {
+		return &TransferLimitError{Code: models.TransferLimitPerTransfer, Limit: limits.MaxPerTransfer}
+	}
+
+	usage, err := s.transferLimitRepo.GetUsage(ctx, tx, fromUserID, toUserID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get transfer usage: %w", err)
+	}
+
+	switch {
+	case limits.MaxPerDay > 0 && usage.SentLastDay+amount > limits.MaxPerDay:
+		return &TransferLimitError{Code: models.TransferLimitPerDay, Limit: limits.MaxPerDay}
+	case limits.MaxPerRecipientWeek > 0 && usage.SentToReceiverWeek+amount > limits.MaxPerRecipientWeek:
+		return &TransferLimitError{Code: models.TransferLimitPerRecipientWeek, Limit: limits.MaxPerRecipientWeek}
+	case limits.MaxRecipientsPerHour > 0 && !usage.ReceiverLastHour &&
+		usage.OtherRecipientsHour+1 > limits.MaxRecipientsPerHour:
+		return &TransferLimitError{Code: models.TransferLimitRecipientsHour, Limit: limits.MaxRecipientsPerHour}
+	}
+
+	return nil
+}
+
 func (s *coinService) GetCoinHistory(ctx context.Context, userID int) (*models.CoinHistory, error) {
 	allTransactions, err := s.transactionRepo.GetByUserID(ctx, userID)
 	if err != nil {
@@ -92,7 +183,15 @@ func (s *coinService) GetCoinHistory(ctx context.Context, userID int) (*models.C
 	sent := make([]models.TransactionSummary, 0)
 
 	for _, t := range allTransactions {
-		var fromUser, toUser string
+		var fromUser, toUser, itemName string
+		var itemQuantity int
+
+		if t.ItemName != nil {
+			itemName = *t.ItemName
+		}
+		if t.ItemQuantity != nil {
+			itemQuantity = *t.ItemQuantity
+		}
 
 		if t.SenderID != -1 {
 			fromUser, err = s.userRepo.GetUsernameByID(ctx, t.SenderID)
@@ -110,13 +209,27 @@ func (s *coinService) GetCoinHistory(ctx context.Context, userID int) (*models.C
 
 		if t.ReceiverID == userID {
 			received = append(received, models.TransactionSummary{
-				FromUser: fromUser,
-				Amount:   t.Amount,
+				ID:         t.ID,
+				FromUser:   fromUser,
+				Amount:     t.Amount,
+				Type:       t.Type,
+				Item:       itemName,
+				Quantity:   itemQuantity,
+				BatchID:    t.BatchID,
+				ReversalOf: t.ReversalOf,
+				ReversedBy: t.ReversedBy,
 			})
 		} else if t.SenderID == userID {
 			sent = append(sent, models.TransactionSummary{
-				ToUser: toUser,
-				Amount: t.Amount,
+				ID:         t.ID,
+				ToUser:     toUser,
+				Amount:     t.Amount,
+				Type:       t.Type,
+				Item:       itemName,
+				Quantity:   itemQuantity,
+				BatchID:    t.BatchID,
+				ReversalOf: t.ReversalOf,
+				ReversedBy: t.ReversedBy,
 			})
 		}
 	}
@@ -2,10 +2,13 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/gratefultolord/merch-store/mocks"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
@@ -19,6 +22,13 @@ func setupTestDB(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
 	return sqlxDB, sqlMock
 }
 
+var defaultTransferLimits = models.TransferLimits{
+	MaxPerTransfer:       500,
+	MaxPerDay:            1000,
+	MaxPerRecipientWeek:  1000,
+	MaxRecipientsPerHour: 10,
+}
+
 func TestCoinService_Send(t *testing.T) {
 	sqlxDB, sqlMock := setupTestDB(t)
 	defer sqlxDB.Close()
@@ -28,8 +38,9 @@ func TestCoinService_Send(t *testing.T) {
 
 	mockUserRepo := new(mocks.UserRepo)
 	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockTransferLimitRepo := new(mocks.TransferLimitRepo)
 
-	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, sqlxDB)
+	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, mockTransferLimitRepo, sqlxDB, defaultTransferLimits)
 	ctx := context.Background()
 
 	fromUser := &models.User{ID: 1, Balance: 100}
@@ -38,8 +49,11 @@ func TestCoinService_Send(t *testing.T) {
 
 	mockUserRepo.On("GetByID", ctx, 1).Return(fromUser, nil).Once()
 	mockUserRepo.On("GetByID", ctx, 2).Return(toUser, nil).Once()
-	mockUserRepo.On("UpdateBalance", ctx, mock.Anything, 1, -amount).Return(nil).Once()
-	mockUserRepo.On("UpdateBalance", ctx, mock.Anything, 2, amount).Return(nil).Once()
+	mockTransferLimitRepo.On("LockSender", ctx, mock.Anything, 1).Return(models.RoleEmployee, nil).Once()
+	mockTransferLimitRepo.On("GetOverrides", ctx, mock.Anything, 1, models.RoleEmployee).Return(nil, nil).Once()
+	mockTransferLimitRepo.On("GetUsage", ctx, mock.Anything, 1, 2).Return(&models.TransferUsage{}, nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 1, amount).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, amount, models.CoinSourceTransfer).Return(nil).Once()
 	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
 		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Amount == amount
 	})).Return(nil).Once()
@@ -49,6 +63,7 @@ func TestCoinService_Send(t *testing.T) {
 
 	mockUserRepo.AssertExpectations(t)
 	mockTransactionRepo.AssertExpectations(t)
+	mockTransferLimitRepo.AssertExpectations(t)
 	assert.NoError(t, sqlMock.ExpectationsWereMet())
 }
 
@@ -57,12 +72,12 @@ func TestCoinService_Send_InsufficientBalance(t *testing.T) {
 	defer sqlxDB.Close()
 
 	sqlMock.ExpectBegin()
-	sqlMock.ExpectCommit()
+	sqlMock.ExpectRollback()
 
 	mockUserRepo := new(mocks.UserRepo)
 	mockTransactionRepo := new(mocks.TransactionRepo)
 
-	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, sqlxDB)
+	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, nil, sqlxDB, defaultTransferLimits)
 	ctx := context.Background()
 
 	fromUser := &models.User{ID: 1, Balance: 10}
@@ -80,6 +95,70 @@ func TestCoinService_Send_InsufficientBalance(t *testing.T) {
 	assert.NoError(t, sqlMock.ExpectationsWereMet())
 }
 
+func TestCoinService_Send_BalanceSpentConcurrently(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockTransferLimitRepo := new(mocks.TransferLimitRepo)
+
+	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, mockTransferLimitRepo, sqlxDB, defaultTransferLimits)
+	ctx := context.Background()
+
+	// The balance read before the transaction still covers the transfer, but
+	// another spend has taken the coins by the time of the debit.
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 100}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Balance: 50}, nil).Once()
+	mockTransferLimitRepo.On("LockSender", ctx, mock.Anything, 1).Return(models.RoleEmployee, nil).Once()
+	mockTransferLimitRepo.On("GetOverrides", ctx, mock.Anything, 1, models.RoleEmployee).Return(nil, nil).Once()
+	mockTransferLimitRepo.On("GetUsage", ctx, mock.Anything, 1, 2).Return(&models.TransferUsage{}, nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 1, 30).
+		Return(fmt.Errorf("repository: update user balance failed: %w", repository.ErrInsufficientBalance)).Once()
+
+	err := coinService.Send(ctx, 1, 2, 30)
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockTransactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestCoinService_Send_CreateTransactionFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockTransferLimitRepo := new(mocks.TransferLimitRepo)
+
+	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, mockTransferLimitRepo, sqlxDB, defaultTransferLimits)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 100}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Balance: 50}, nil).Once()
+	mockTransferLimitRepo.On("LockSender", ctx, mock.Anything, 1).Return(models.RoleEmployee, nil).Once()
+	mockTransferLimitRepo.On("GetOverrides", ctx, mock.Anything, 1, models.RoleEmployee).Return(nil, nil).Once()
+	mockTransferLimitRepo.On("GetUsage", ctx, mock.Anything, 1, 2).Return(&models.TransferUsage{}, nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 1, 30).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, 30, models.CoinSourceTransfer).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+	err := coinService.Send(ctx, 1, 2, 30)
+	assert.Error(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
 func TestCoinService_GetCoinHistory(t *testing.T) {
 	sqlxDB, _ := setupTestDB(t)
 	defer sqlxDB.Close()
@@ -87,7 +166,7 @@ func TestCoinService_GetCoinHistory(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepo)
 	mockTransactionRepo := new(mocks.TransactionRepo)
 
-	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, sqlxDB)
+	coinService := NewCoinService(mockUserRepo, mockTransactionRepo, nil, sqlxDB, defaultTransferLimits)
 	ctx := context.Background()
 
 	transactions := []models.Transaction{
@@ -108,3 +187,92 @@ func TestCoinService_GetCoinHistory(t *testing.T) {
 	mockTransactionRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestCoinService_CheckTransferLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       int
+		overrides    []models.TransferLimitOverride
+		usage        *models.TransferUsage
+		expectedCode string
+	}{
+		{
+			name:   "Within limits",
+			amount: 100,
+			usage:  &models.TransferUsage{SentLastDay: 300, SentToReceiverWeek: 200, OtherRecipientsHour: 3},
+		},
+		{
+			name:         "Too much for one transfer",
+			amount:       600,
+			expectedCode: models.TransferLimitPerTransfer,
+		},
+		{
+			name:         "Too much in a day",
+			amount:       200,
+			usage:        &models.TransferUsage{SentLastDay: 900},
+			expectedCode: models.TransferLimitPerDay,
+		},
+		{
+			name:         "Too much to one recipient in a week",
+			amount:       200,
+			usage:        &models.TransferUsage{SentToReceiverWeek: 900},
+			expectedCode: models.TransferLimitPerRecipientWeek,
+		},
+		{
+			name:         "Too many recipients in an hour",
+			amount:       10,
+			usage:        &models.TransferUsage{OtherRecipientsHour: 10},
+			expectedCode: models.TransferLimitRecipientsHour,
+		},
+		{
+			name:   "Recipient already paid this hour",
+			amount: 10,
+			usage:  &models.TransferUsage{OtherRecipientsHour: 9, ReceiverLastHour: true},
+		},
+		{
+			name:   "User override lifts the per transfer limit",
+			amount: 800,
+			overrides: []models.TransferLimitOverride{
+				{Role: strPtr(models.RoleEmployee), MaxPerTransfer: intPtr(700)},
+				{UserID: intPtr(1), MaxPerTransfer: intPtr(0)},
+			},
+			usage: &models.TransferUsage{},
+		},
+		{
+			name:   "Role override tightens the daily limit",
+			amount: 100,
+			overrides: []models.TransferLimitOverride{
+				{Role: strPtr(models.RoleEmployee), MaxPerDay: intPtr(150)},
+			},
+			usage:        &models.TransferUsage{SentLastDay: 100},
+			expectedCode: models.TransferLimitPerDay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockTransferLimitRepo := new(mocks.TransferLimitRepo)
+			coinService := NewCoinService(nil, nil, mockTransferLimitRepo, nil, defaultTransferLimits)
+			ctx := context.Background()
+
+			mockTransferLimitRepo.On("LockSender", ctx, mock.Anything, 1).Return(models.RoleEmployee, nil).Once()
+			mockTransferLimitRepo.On("GetOverrides", ctx, mock.Anything, 1, models.RoleEmployee).
+				Return(tt.overrides, nil).Once()
+			if tt.usage != nil {
+				mockTransferLimitRepo.On("GetUsage", ctx, mock.Anything, 1, 2).Return(tt.usage, nil).Once()
+			}
+
+			err := coinService.CheckTransferLimits(ctx, nil, 1, 2, tt.amount)
+			if tt.expectedCode == "" {
+				assert.NoError(t, err)
+			} else {
+				var limitErr *TransferLimitError
+				assert.ErrorAs(t, err, &limitErr)
+				assert.ErrorIs(t, err, ErrTransferLimitExceeded)
+				assert.Equal(t, tt.expectedCode, limitErr.Code)
+			}
+
+			mockTransferLimitRepo.AssertExpectations(t)
+		})
+	}
+}
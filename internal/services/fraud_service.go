@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrFraudFlagNotFound  = errors.New("services: fraud flag not found")
+	ErrFraudFlagReviewed  = errors.New("services: fraud flag is already reviewed")
+	ErrInvalidFraudReview = errors.New("services: invalid fraud review")
+)
+
+const (
+	// circularFlowWindow is how far back coins going both ways between two
+	// users count as a circular flow.
+	circularFlowWindow    = 7 * 24 * time.Hour
+	circularFlowMinAmount = 50
+	// newAccountAge is how long an account counts as new. A new account
+	// sending away half of its signup bonus is flagged.
+	newAccountAge          = 7 * 24 * time.Hour
+	newAccountForwardShare = 500
+	// A volume spike is a day of transfers worth spikeFactor times the
+	// sender's daily average over the spikeBaselineDays before.
+	spikeBaselineDays = 30
+	spikeFactor       = 5
+	spikeMinAmount    = 300
+)
+
+type FraudService interface {
+	Scan(ctx context.Context) (int, error)
+	ScreenTransfer(ctx context.Context, fromUserID int, toUserID int, amount int) (*models.FraudFlag, error)
+	Record(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error
+	List(ctx context.Context, status string) ([]models.FraudFlag, error)
+	Review(ctx context.Context, adminID int, flagID int, status string) (*models.FraudFlag, error)
+}
+
+type fraudService struct {
+	userRepo            repository.UserRepo
+	transactionRepo     repository.TransactionRepo
+	notificationRepo    repository.NotificationRepo
+	pendingTransferRepo repository.PendingTransferRepo
+	fraudRepo           repository.FraudRepo
+	db                  *sqlx.DB
+	holdForReview       bool
+}
+
+func NewFraudService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	pendingTransferRepo repository.PendingTransferRepo,
+	fraudRepo repository.FraudRepo,
+	db *sqlx.DB,
+	holdForReview bool,
+) FraudService {
+	return &fraudService{
+		userRepo:            userRepo,
+		transactionRepo:     transactionRepo,
+		notificationRepo:    notificationRepo,
+		pendingTransferRepo: pendingTransferRepo,
+		fraudRepo:           fraudRepo,
+		db:                  db,
+		holdForReview:       holdForReview,
+	}
+}
+
+// Scan runs every rule over the recent transactions and queues a flag for
+// each finding. A finding already flagged within the rule's window is not
+// queued again, even when the earlier flag was dismissed.
+func (s *fraudService) Scan(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	circular, err := s.fraudRepo.FindCircularFlows(ctx, now.Add(-circularFlowWindow), circularFlowMinAmount)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to find circular flows: %w", err)
+	}
+
+	forwards, err := s.fraudRepo.FindNewAccountForwards(ctx, now.Add(-newAccountAge), newAccountForwardShare)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to find new account forwards: %w", err)
+	}
+
+	daySince := now.Add(-24 * time.Hour)
+	spikes, err := s.fraudRepo.FindVolumeSpikes(ctx, daySince,
+		daySince.AddDate(0, 0, -spikeBaselineDays), spikeBaselineDays, spikeFactor, spikeMinAmount)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to find volume spikes: %w", err)
+	}
+
+	findings := []struct {
+		rule   string
+		window time.Duration
+		flags  []models.FraudFlag
+	}{
+		{models.FraudRuleCircularFlow, circularFlowWindow, circular},
+		{models.FraudRuleNewAccountForward, newAccountAge, forwards},
+		{models.FraudRuleVolumeSpike, 24 * time.Hour, spikes},
+	}
+
+	flagged := 0
+	var errs []error
+	for _, finding := range findings {
+		for i := range finding.flags {
+			flag := &finding.flags[i]
+			flag.Rule = finding.rule
+			flag.Details = fraudDetails(finding.rule, flag.Amount)
+			flag.Status = models.FraudFlagStatusOpen
+
+			created, err := s.fraudRepo.CreateIfNew(ctx, flag, now.Add(-finding.window))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("services: failed to flag user %d: %w", flag.UserID, err))
+				continue
+			}
+			if created {
+				flagged++
+			}
+		}
+	}
+
+	return flagged, errors.Join(errs...)
+}
+
+// ScreenTransfer checks a transfer against the rules before the coins move.
+// It returns the flag to hold the transfer under, or nil when the transfer
+// looks fine or holding for review is turned off.
+func (s *fraudService) ScreenTransfer(
+	ctx context.Context, fromUserID int, toUserID int, amount int) (*models.FraudFlag, error) {
+	if !s.holdForReview {
+		return nil, nil
+	}
+
+	now := time.Now()
+	daySince := now.Add(-24 * time.Hour)
+	activity, err := s.fraudRepo.GetSenderActivity(ctx, fromUserID, toUserID,
+		now.Add(-circularFlowWindow), daySince, daySince.AddDate(0, 0, -spikeBaselineDays))
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get sender activity: %w", err)
+	}
+
+	flag := &models.FraudFlag{
+		UserID:         fromUserID,
+		CounterpartyID: &toUserID,
+		Amount:         amount,
+		Status:         models.FraudFlagStatusOpen,
+	}
+
+	sentLastDay := activity.SentLastDay + amount
+	switch {
+	case activity.ReceivedFromReceiver >= circularFlowMinAmount && amount >= circularFlowMinAmount:
+		flag.Rule = models.FraudRuleCircularFlow
+		flag.Details = fraudDetails(flag.Rule, min(amount, activity.ReceivedFromReceiver))
+	case now.Sub(activity.CreatedAt) < newAccountAge && activity.SentSinceSignup+amount >= newAccountForwardShare:
+		flag.Rule = models.FraudRuleNewAccountForward
+		flag.Details = fraudDetails(flag.Rule, activity.SentSinceSignup+amount)
+	case sentLastDay >= spikeMinAmount && sentLastDay > spikeFactor*activity.SentBefore/spikeBaselineDays:
+		flag.Rule = models.FraudRuleVolumeSpike
+		flag.Details = fraudDetails(flag.Rule, sentLastDay)
+	default:
+		return nil, nil
+	}
+
+	return flag, nil
+}
+
+func fraudDetails(rule string, amount int) string {
+	switch rule {
+	case models.FraudRuleCircularFlow:
+		return fmt.Sprintf("at least %d coins went each way between the users in the last %d days",
+			amount, int(circularFlowWindow.Hours()/24))
+	case models.FraudRuleNewAccountForward:
+		return fmt.Sprintf("account younger than %d days sent away %d coins",
+			int(newAccountAge.Hours()/24), amount)
+	case models.FraudRuleVolumeSpike:
+		return fmt.Sprintf("sent %d coins in a day, over %d times the daily average of the %d days before",
+			amount, spikeFactor, spikeBaselineDays)
+	}
+	return ""
+}
+
+// Record queues the flag of a transfer held for review.
+func (s *fraudService) Record(ctx context.Context, tx *sqlx.Tx, flag *models.FraudFlag) error {
+	if err := s.fraudRepo.Create(ctx, tx, flag); err != nil {
+		return fmt.Errorf("services: failed to create fraud flag: %w", err)
+	}
+	return nil
+}
+
+func (s *fraudService) List(ctx context.Context, status string) ([]models.FraudFlag, error) {
+	if status != "" && !isFraudFlagStatus(status) {
+		return nil, ErrInvalidFraudReview
+	}
+
+	flags, err := s.fraudRepo.GetAll(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get fraud flags: %w", err)
+	}
+	if flags == nil {
+		flags = []models.FraudFlag{}
+	}
+	return flags, nil
+}
+
+func isFraudFlagStatus(status string) bool {
+	switch status {
+	case models.FraudFlagStatusOpen, models.FraudFlagStatusConfirmed, models.FraudFlagStatusDismissed:
+		return true
+	}
+	return false
+}
+
+// Review closes an open flag. A transfer held under a dismissed flag goes
+// through to the receiver; one held under a confirmed flag goes back to the
+// sender.
+func (s *fraudService) Review(
+	ctx context.Context, adminID int, flagID int, status string) (flag *models.FraudFlag, err error) {
+	if status != models.FraudFlagStatusConfirmed && status != models.FraudFlagStatusDismissed {
+		return nil, ErrInvalidFraudReview
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	flag, err = s.fraudRepo.GetByIDForUpdate(ctx, tx, flagID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock fraud flag: %w", err)
+	}
+	if flag == nil {
+		return nil, ErrFraudFlagNotFound
+	}
+	if flag.Status != models.FraudFlagStatusOpen {
+		return nil, ErrFraudFlagReviewed
+	}
+
+	if flag.PendingTransferID != nil {
+		if err := s.settleHeld(ctx, tx, *flag.PendingTransferID, status); err != nil {
+			return nil, err
+		}
+	}
+
+	flag.Status = status
+	flag.ReviewedBy = &adminID
+
+	if err := s.fraudRepo.Review(ctx, tx, flag); err != nil {
+		return nil, fmt.Errorf("services: failed to review fraud flag: %w", err)
+	}
+
+	return flag, nil
+}
+
+func (s *fraudService) settleHeld(ctx context.Context, tx *sqlx.Tx, transferID int, status string) error {
+	transfer, err := s.pendingTransferRepo.GetByIDForUpdate(ctx, tx, transferID)
+	if err != nil {
+		return fmt.Errorf("services: failed to lock pending transfer: %w", err)
+	}
+	if transfer == nil || transfer.Status != models.PendingTransferStatusReview {
+		return nil
+	}
+
+	notification := &models.Notification{
+		UserID: transfer.SenderID,
+		Type:   models.NotificationTypeTransferReviewed,
+	}
+
+	if status == models.FraudFlagStatusDismissed {
+		if err := s.userRepo.Credit(ctx, tx, transfer.ReceiverID, transfer.Amount,
+			models.CoinSourceTransfer); err != nil {
+			return fmt.Errorf("services: failed to update balance of toUser: %w", err)
+		}
+
+		transaction := &models.Transaction{
+			SenderID:   transfer.SenderID,
+			ReceiverID: transfer.ReceiverID,
+			Amount:     transfer.Amount,
+			Type:       models.TransactionTypeTransfer,
+			BatchID:    transfer.BatchID,
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("services: failed to create transaction: %w", err)
+		}
+
+		transfer.Status = models.PendingTransferStatusAccepted
+		transfer.TransactionID = &transaction.ID
+		notification.Message = fmt.Sprintf("your transfer of %d coins to %s passed the review and was delivered",
+			transfer.Amount, transfer.Receiver)
+	} else {
+		if err := s.userRepo.Release(ctx, tx, transfer.SenderID, transfer.Amount,
+			models.CoinSourceRelease, transfer.CoinHold()); err != nil {
+			return fmt.Errorf("services: failed to return transfer: %w", err)
+		}
+
+		transfer.Status = models.PendingTransferStatusRejected
+		notification.Message = fmt.Sprintf("your transfer of %d coins to %s was rejected after a review, "+
+			"the coins were returned", transfer.Amount, transfer.Receiver)
+	}
+
+	if err := s.pendingTransferRepo.Resolve(ctx, tx, transfer); err != nil {
+		return fmt.Errorf("services: failed to resolve pending transfer: %w", err)
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify sender: %w", err)
+	}
+	return nil
+}
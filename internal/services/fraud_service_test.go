@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFraudService_Scan(t *testing.T) {
+	mockFraudRepo := new(mocks.FraudRepo)
+	fraudService := NewFraudService(nil, nil, nil, nil, mockFraudRepo, nil, false)
+	ctx := context.Background()
+
+	mockFraudRepo.On("FindCircularFlows", ctx, mock.Anything, circularFlowMinAmount).
+		Return([]models.FraudFlag{{UserID: 1, CounterpartyID: intPtr(2), Amount: 200}}, nil).Once()
+	mockFraudRepo.On("FindNewAccountForwards", ctx, mock.Anything, newAccountForwardShare).
+		Return([]models.FraudFlag{{UserID: 5, CounterpartyID: intPtr(1), Amount: 900}}, nil).Once()
+	mockFraudRepo.On("FindVolumeSpikes", ctx, mock.Anything, mock.Anything,
+		spikeBaselineDays, spikeFactor, spikeMinAmount).
+		Return([]models.FraudFlag{{UserID: 3, Amount: 700}}, nil).Once()
+
+	mockFraudRepo.On("CreateIfNew", ctx, mock.MatchedBy(func(f *models.FraudFlag) bool {
+		return f.Rule == models.FraudRuleCircularFlow && f.UserID == 1 && f.Status == models.FraudFlagStatusOpen
+	}), mock.Anything).Return(true, nil).Once()
+	mockFraudRepo.On("CreateIfNew", ctx, mock.MatchedBy(func(f *models.FraudFlag) bool {
+		return f.Rule == models.FraudRuleNewAccountForward && f.UserID == 5
+	}), mock.Anything).Return(false, nil).Once()
+	mockFraudRepo.On("CreateIfNew", ctx, mock.MatchedBy(func(f *models.FraudFlag) bool {
+		return f.Rule == models.FraudRuleVolumeSpike && f.UserID == 3 && f.Details != ""
+	}), mock.Anything).Return(true, nil).Once()
+
+	flagged, err := fraudService.Scan(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, flagged)
+
+	mockFraudRepo.AssertExpectations(t)
+}
+
+func TestFraudService_ScreenTransfer(t *testing.T) {
+	established := time.Now().AddDate(-1, 0, 0)
+
+	tests := []struct {
+		name         string
+		amount       int
+		activity     *models.SenderActivity
+		expectedRule string
+	}{
+		{
+			name:     "Usual transfer",
+			amount:   50,
+			activity: &models.SenderActivity{CreatedAt: established, SentLastDay: 50, SentBefore: 900},
+		},
+		{
+			name:   "Coins sent back",
+			amount: 100,
+			activity: &models.SenderActivity{CreatedAt: established, ReceivedFromReceiver: 100,
+				SentBefore: 900},
+			expectedRule: models.FraudRuleCircularFlow,
+		},
+		{
+			name:         "New account forwards the bonus",
+			amount:       300,
+			activity:     &models.SenderActivity{CreatedAt: time.Now().Add(-time.Hour), SentSinceSignup: 400},
+			expectedRule: models.FraudRuleNewAccountForward,
+		},
+		{
+			name:         "Volume spike",
+			amount:       200,
+			activity:     &models.SenderActivity{CreatedAt: established, SentLastDay: 200, SentBefore: 300},
+			expectedRule: models.FraudRuleVolumeSpike,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFraudRepo := new(mocks.FraudRepo)
+			fraudService := NewFraudService(nil, nil, nil, nil, mockFraudRepo, nil, true)
+			ctx := context.Background()
+
+			mockFraudRepo.On("GetSenderActivity", ctx, 1, 2, mock.Anything, mock.Anything, mock.Anything).
+				Return(tt.activity, nil).Once()
+
+			flag, err := fraudService.ScreenTransfer(ctx, 1, 2, tt.amount)
+			assert.NoError(t, err)
+			if tt.expectedRule == "" {
+				assert.Nil(t, flag)
+			} else {
+				assert.Equal(t, tt.expectedRule, flag.Rule)
+				assert.Equal(t, 2, *flag.CounterpartyID)
+				assert.Equal(t, tt.amount, flag.Amount)
+			}
+
+			mockFraudRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFraudService_ScreenTransfer_Disabled(t *testing.T) {
+	mockFraudRepo := new(mocks.FraudRepo)
+	fraudService := NewFraudService(nil, nil, nil, nil, mockFraudRepo, nil, false)
+
+	flag, err := fraudService.ScreenTransfer(context.Background(), 1, 2, 1000)
+	assert.NoError(t, err)
+	assert.Nil(t, flag)
+
+	mockFraudRepo.AssertNotCalled(t, "GetSenderActivity",
+		mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFraudService_Review(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         string
+		creditedUser   int
+		source         string
+		transferStatus string
+	}{
+		{
+			name:           "Dismissed flag delivers the transfer",
+			status:         models.FraudFlagStatusDismissed,
+			creditedUser:   2,
+			source:         models.CoinSourceTransfer,
+			transferStatus: models.PendingTransferStatusAccepted,
+		},
+		{
+			name:           "Confirmed flag returns the transfer",
+			status:         models.FraudFlagStatusConfirmed,
+			creditedUser:   1,
+			source:         models.CoinSourceRelease,
+			transferStatus: models.PendingTransferStatusRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectCommit()
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockTransactionRepo := new(mocks.TransactionRepo)
+			mockNotificationRepo := new(mocks.NotificationRepo)
+			mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+			mockFraudRepo := new(mocks.FraudRepo)
+			fraudService := NewFraudService(mockUserRepo, mockTransactionRepo, mockNotificationRepo,
+				mockPendingTransferRepo, mockFraudRepo, sqlxDB, true)
+			ctx := context.Background()
+
+			flag := &models.FraudFlag{ID: 3, UserID: 1, Rule: models.FraudRuleCircularFlow,
+				PendingTransferID: intPtr(7), Status: models.FraudFlagStatusOpen}
+			transfer := &models.PendingTransfer{ID: 7, SenderID: 1, ReceiverID: 2, Receiver: "bob", Amount: 30,
+				Status: models.PendingTransferStatusReview}
+
+			mockFraudRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(flag, nil).Once()
+			mockPendingTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(transfer, nil).Once()
+			if tt.status == models.FraudFlagStatusConfirmed {
+				mockUserRepo.On("Release", ctx, mock.Anything, tt.creditedUser, 30, tt.source,
+					models.CoinHold{Type: models.CoinHoldPendingTransfer, ID: 7}).Return(nil).Once()
+			} else {
+				mockUserRepo.On("Credit", ctx, mock.Anything, tt.creditedUser, 30, tt.source).Return(nil).Once()
+			}
+			mockTransactionRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Maybe()
+			mockPendingTransferRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+				return pt.Status == tt.transferStatus
+			})).Return(nil).Once()
+			mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+				return n.UserID == 1 && n.Type == models.NotificationTypeTransferReviewed
+			})).Return(nil).Once()
+			mockFraudRepo.On("Review", ctx, mock.Anything, mock.MatchedBy(func(f *models.FraudFlag) bool {
+				return f.Status == tt.status && *f.ReviewedBy == 9
+			})).Return(nil).Once()
+
+			reviewed, err := fraudService.Review(ctx, 9, 3, tt.status)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status, reviewed.Status)
+
+			if tt.status == models.FraudFlagStatusConfirmed {
+				mockTransactionRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockUserRepo.AssertExpectations(t)
+			mockPendingTransferRepo.AssertExpectations(t)
+			mockNotificationRepo.AssertExpectations(t)
+			mockFraudRepo.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestFraudService_Review_AlreadyReviewed(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockFraudRepo := new(mocks.FraudRepo)
+	fraudService := NewFraudService(nil, nil, nil, nil, mockFraudRepo, sqlxDB, true)
+	ctx := context.Background()
+
+	mockFraudRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).
+		Return(&models.FraudFlag{ID: 3, Status: models.FraudFlagStatusDismissed}, nil).Once()
+
+	_, err := fraudService.Review(ctx, 9, 3, models.FraudFlagStatusConfirmed)
+	assert.ErrorIs(t, err, ErrFraudFlagReviewed)
+
+	mockFraudRepo.AssertNotCalled(t, "Review", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -5,21 +5,53 @@ import (
 	"fmt"
 	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
+	"time"
 )
 
+// expiryWarningWindow is how long before coins expire /api/info starts
+// warning about them.
+const expiryWarningWindow = 30 * 24 * time.Hour
+
 type InfoService interface {
 	GetUserInfo(ctx context.Context, userID int) (*models.InfoResponse, error)
 }
 
 type infoService struct {
-	userRepo    repository.UserRepo
-	coinService CoinService
+	userRepo            repository.UserRepo
+	notificationRepo    repository.NotificationRepo
+	coinService         CoinService
+	wishlistService     WishlistService
+	preorderRepo        repository.PreorderRepo
+	auctionRepo         repository.AuctionRepo
+	poolRepo            repository.PoolRepo
+	pendingTransferRepo repository.PendingTransferRepo
+	allowanceService    AllowanceService
+	coinLotRepo         repository.CoinLotRepo
 }
 
-func NewInfoService(userRepo repository.UserRepo, coinService CoinService) InfoService {
+func NewInfoService(
+	userRepo repository.UserRepo,
+	notificationRepo repository.NotificationRepo,
+	coinService CoinService,
+	wishlistService WishlistService,
+	preorderRepo repository.PreorderRepo,
+	auctionRepo repository.AuctionRepo,
+	poolRepo repository.PoolRepo,
+	pendingTransferRepo repository.PendingTransferRepo,
+	allowanceService AllowanceService,
+	coinLotRepo repository.CoinLotRepo,
+) InfoService {
 	return &infoService{
-		userRepo:    userRepo,
-		coinService: coinService,
+		userRepo:            userRepo,
+		notificationRepo:    notificationRepo,
+		coinService:         coinService,
+		wishlistService:     wishlistService,
+		preorderRepo:        preorderRepo,
+		auctionRepo:         auctionRepo,
+		poolRepo:            poolRepo,
+		pendingTransferRepo: pendingTransferRepo,
+		allowanceService:    allowanceService,
+		coinLotRepo:         coinLotRepo,
 	}
 }
 
@@ -39,12 +71,64 @@ func (s *infoService) GetUserInfo(ctx context.Context, userID int) (*models.Info
 		return nil, fmt.Errorf("services: failed getting coin history: %v", err)
 	}
 
+	notifications, err := s.notificationRepo.GetUnread(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting notifications: %v", err)
+	}
+
+	preordered, err := s.preorderRepo.GetReservedAmount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting reserved coins: %v", err)
+	}
+
+	bidding, err := s.auctionRepo.GetReservedAmount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting reserved coins: %v", err)
+	}
+
+	pooled, err := s.poolRepo.GetReservedAmount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting reserved coins: %v", err)
+	}
+
+	escrowed, err := s.pendingTransferRepo.GetReservedAmount(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting reserved coins: %v", err)
+	}
+
+	allowance, err := s.allowanceService.Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting allowance: %v", err)
+	}
+
+	expiring, err := s.coinLotRepo.GetExpiring(ctx, userID, time.Now().Add(expiryWarningWindow))
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting expiring coins: %v", err)
+	}
+
+	wishlist, err := s.wishlistService.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed getting wishlist: %v", err)
+	}
+
+	var hints []string
+	for _, entry := range wishlist {
+		if entry.Affordable {
+			hints = append(hints, fmt.Sprintf("You can afford %s now", entry.Item))
+		}
+	}
+
 	inventory := convertInventory(user.Inventory)
 
 	response := &models.InfoResponse{
-		Coins:       user.Balance,
-		Inventory:   inventory,
-		CoinHistory: *coinHistory,
+		Coins:         user.Balance,
+		Reserved:      preordered + bidding + pooled + escrowed,
+		Allowance:     *allowance,
+		ExpiringSoon:  expiring,
+		Inventory:     inventory,
+		CoinHistory:   *coinHistory,
+		Notifications: notifications,
+		WishlistHints: hints,
 	}
 
 	return response, nil
@@ -55,6 +139,8 @@ func convertInventory(inventory []models.UserInventoryItem) []models.UserInvento
 	for i, item := range inventory {
 		result[i] = models.UserInventoryItemResponse{
 			Type:     item.Type.Name,
+			Size:     item.Variant.Size,
+			Color:    item.Variant.Color,
 			Quantity: item.Quantity,
 		}
 	}
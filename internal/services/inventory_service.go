@@ -2,38 +2,85 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrVariantNotFound = errors.New("services: item variant not found")
+	ErrVariantRequired = errors.New("services: item variant must be specified")
 )
 
 type InventoryService interface {
-	Buy(ctx context.Context, userID int, itemName string) error
+	Buy(ctx context.Context, userID int, itemName string, opts models.BuyOptions) error
+	Gift(ctx context.Context, senderID int, recipientID int, itemName string, opts models.BuyOptions, message string) error
+	TransferItem(ctx context.Context, fromUserID int, toUserID int, itemName string, opts models.BuyOptions) error
 }
 
 type inventoryService struct {
-	userRepo        repository.UserRepo
-	itemRepo        repository.ItemRepo
-	transactionRepo repository.TransactionRepo
-	db              *sqlx.DB
+	userRepo         repository.UserRepo
+	itemRepo         repository.ItemRepo
+	transactionRepo  repository.TransactionRepo
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	itemTransferRepo repository.ItemTransferRepo
+	promoCodeRepo    repository.PromoCodeRepo
+	priceRuleRepo    repository.PriceRuleRepo
+	db               *sqlx.DB
 }
 
 func NewInventoryService(
 	userRepo repository.UserRepo,
 	itemRepo repository.ItemRepo,
 	transactionRepo repository.TransactionRepo,
+	orderRepo repository.OrderRepo,
+	notificationRepo repository.NotificationRepo,
+	itemTransferRepo repository.ItemTransferRepo,
+	promoCodeRepo repository.PromoCodeRepo,
+	priceRuleRepo repository.PriceRuleRepo,
 	db *sqlx.DB,
 ) InventoryService {
 	return &inventoryService{
-		userRepo:        userRepo,
-		itemRepo:        itemRepo,
-		transactionRepo: transactionRepo,
-		db:              db,
+		userRepo:         userRepo,
+		itemRepo:         itemRepo,
+		transactionRepo:  transactionRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		itemTransferRepo: itemTransferRepo,
+		promoCodeRepo:    promoCodeRepo,
+		priceRuleRepo:    priceRuleRepo,
+		db:               db,
+	}
+}
+
+func (s *inventoryService) Buy(ctx context.Context, userID int, itemName string, opts models.BuyOptions) error {
+	return s.purchase(ctx, userID, userID, itemName, opts, "")
+}
+
+// Gift debits the sender and delivers the item to the recipient's inventory.
+func (s *inventoryService) Gift(
+	ctx context.Context, senderID int, recipientID int, itemName string, opts models.BuyOptions, message string) error {
+	if senderID == recipientID {
+		return fmt.Errorf("services: cannot gift an item to yourself")
 	}
+	return s.purchase(ctx, senderID, recipientID, itemName, opts, message)
 }
 
-func (s *inventoryService) Buy(ctx context.Context, userID int, itemName string) error {
+func (s *inventoryService) purchase(
+	ctx context.Context, buyerID int, recipientID int,
+	itemName string, opts models.BuyOptions, message string) (err error) {
+	quantity := opts.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return fmt.Errorf("services: invalid quantity")
+	}
+
 	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("services: failed to begin transaction: %w", err)
@@ -47,7 +94,7 @@ func (s *inventoryService) Buy(ctx context.Context, userID int, itemName string)
 		}
 	}()
 
-	user, err := s.userRepo.GetByID(ctx, userID)
+	user, err := s.userRepo.GetByID(ctx, buyerID)
 	if err != nil {
 		return fmt.Errorf("services: failed to get user by id: %w", err)
 	}
@@ -55,6 +102,17 @@ func (s *inventoryService) Buy(ctx context.Context, userID int, itemName string)
 		return fmt.Errorf("services: user not found")
 	}
 
+	isGift := recipientID != buyerID
+	if isGift {
+		recipient, err := s.userRepo.GetByID(ctx, recipientID)
+		if err != nil {
+			return fmt.Errorf("services: failed to get recipient by id: %w", err)
+		}
+		if recipient == nil {
+			return fmt.Errorf("services: recipient not found")
+		}
+	}
+
 	item, err := s.itemRepo.GetItemByName(ctx, itemName)
 	if err != nil {
 		return fmt.Errorf("services: failed to get item by name: %w", err)
@@ -62,28 +120,326 @@ func (s *inventoryService) Buy(ctx context.Context, userID int, itemName string)
 	if item == nil {
 		return fmt.Errorf("services: item not found")
 	}
+	if item.ArchivedAt != nil {
+		return fmt.Errorf("services: item is no longer available")
+	}
 
-	if user.Balance < item.Price {
-		return fmt.Errorf("services: insufficient balance")
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+	if err != nil {
+		return err
 	}
 
-	if err := s.userRepo.UpdateBalance(ctx, tx, userID, -item.Price); err != nil {
-		return fmt.Errorf("services: failed to update user balance: %w", err)
+	rules, err := s.priceRuleRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("services: failed to get price rules: %w", err)
 	}
 
-	if err := s.userRepo.AddOrIncrementItemInventory(ctx, tx, userID, item.ID, 1); err != nil {
+	originalPrice := variant.UnitPrice(*item)
+	unitPrice, rule := models.SalePrice(*item, originalPrice, rules)
+	listTotal := originalPrice * quantity
+	saleTotal := unitPrice * quantity
+
+	var promo *models.PromoCode
+	promoDiscount := 0
+	if opts.PromoCode != "" {
+		promo, err = s.lockPromoCode(ctx, tx, buyerID, *item, opts.PromoCode)
+		if err != nil {
+			return err
+		}
+		promoDiscount = promo.Discount(saleTotal)
+	}
+	total := saleTotal - promoDiscount
+
+	if user.Balance < total {
+		return ErrInsufficientBalance
+	}
+
+	reserved, err := s.itemRepo.DecrementStock(ctx, tx, variant.ID, quantity)
+	if err != nil {
+		return fmt.Errorf("services: failed to reserve stock: %w", err)
+	}
+	if !reserved {
+		return fmt.Errorf("services: item is out of stock")
+	}
+
+	if err := s.userRepo.AddOrIncrementItemInventory(ctx, tx, recipientID, item.ID, variant.ID, quantity); err != nil {
 		return fmt.Errorf("services: failed to add to inventory: %w", err)
 	}
 
 	transaction := &models.Transaction{
 		SenderID:   user.ID,
 		ReceiverID: -1,
-		Amount:     item.Price,
+		Amount:     total,
+		Type:       models.TransactionTypePurchase,
+	}
+	if isGift {
+		transaction.ReceiverID = recipientID
+		transaction.Type = models.TransactionTypeGift
 	}
 
 	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
 		return fmt.Errorf("services: failed to create transaction: %w", err)
 	}
 
+	order := &models.Order{
+		UserID:        user.ID,
+		RecipientID:   recipientID,
+		ItemID:        item.ID,
+		VariantID:     variant.ID,
+		TransactionID: transaction.ID,
+		Quantity:      quantity,
+		UnitPrice:     unitPrice,
+		ListTotal:     listTotal,
+		Discount:      listTotal - total,
+		Total:         total,
+		Message:       message,
+	}
+	if promo != nil {
+		order.PromoCodeID = &promo.ID
+	}
+
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return fmt.Errorf("services: failed to create order: %w", err)
+	}
+
+	if err := s.userRepo.Hold(ctx, tx, buyerID, total, order.CoinHold()); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return ErrInsufficientBalance
+		}
+		return fmt.Errorf("services: failed to update user balance: %w", err)
+	}
+
+	if promo != nil {
+		if err := s.promoCodeRepo.IncrementUsage(ctx, tx, promo.ID); err != nil {
+			return fmt.Errorf("services: failed to redeem promo code: %w", err)
+		}
+
+		redemption := &models.PromoRedemption{
+			PromoCodeID: promo.ID,
+			UserID:      buyerID,
+			OrderID:     order.ID,
+			Discount:    promoDiscount,
+		}
+
+		if err := s.promoCodeRepo.CreateRedemption(ctx, tx, redemption); err != nil {
+			return fmt.Errorf("services: failed to redeem promo code: %w", err)
+		}
+	}
+
+	history := &models.PriceHistory{
+		OrderID:       order.ID,
+		UserID:        buyerID,
+		ItemID:        item.ID,
+		VariantID:     variant.ID,
+		OriginalPrice: originalPrice,
+		SalePrice:     unitPrice,
+		PromoDiscount: promoDiscount,
+		Charged:       total,
+	}
+	if rule != nil {
+		history.PriceRuleID = &rule.ID
+	}
+
+	if err := s.priceRuleRepo.RecordHistory(ctx, tx, history); err != nil {
+		return fmt.Errorf("services: failed to record price history: %w", err)
+	}
+
+	if isGift {
+		text := fmt.Sprintf("%s sent you a gift: %s", user.Username, item.Name)
+		if message != "" {
+			text = fmt.Sprintf("%s (%s)", text, message)
+		}
+
+		notification := &models.Notification{
+			UserID:  recipientID,
+			Type:    models.NotificationTypeGiftReceived,
+			Message: text,
+		}
+
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return fmt.Errorf("services: failed to notify recipient: %w", err)
+		}
+	}
+
 	return nil
 }
+
+// TransferItem moves owned units of an item to another user. Archived items
+// can still be transferred.
+func (s *inventoryService) TransferItem(
+	ctx context.Context, fromUserID int, toUserID int, itemName string, opts models.BuyOptions) (err error) {
+	if fromUserID == toUserID {
+		return fmt.Errorf("services: cannot transfer an item to yourself")
+	}
+
+	quantity := opts.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return fmt.Errorf("services: invalid quantity")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	fromUser, err := s.userRepo.GetByID(ctx, fromUserID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get fromUser by id: %w", err)
+	}
+	if fromUser == nil {
+		return fmt.Errorf("services: fromUser not found")
+	}
+
+	toUser, err := s.userRepo.GetByID(ctx, toUserID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get toUser by id: %w", err)
+	}
+	if toUser == nil {
+		return fmt.Errorf("services: toUser not found")
+	}
+
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return fmt.Errorf("services: item not found")
+	}
+
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+	if err != nil {
+		return err
+	}
+
+	owned, err := s.userRepo.LockInventory(ctx, tx, fromUserID, variant.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to lock inventory: %w", err)
+	}
+	if owned < quantity {
+		return fmt.Errorf("services: not enough items in inventory")
+	}
+
+	removed, err := s.userRepo.RemoveFromInventory(ctx, tx, fromUserID, variant.ID, quantity)
+	if err != nil {
+		return fmt.Errorf("services: failed to remove item from inventory: %w", err)
+	}
+	if !removed {
+		return fmt.Errorf("services: not enough items in inventory")
+	}
+
+	if err := s.userRepo.AddOrIncrementItemInventory(ctx, tx, toUserID, item.ID, variant.ID, quantity); err != nil {
+		return fmt.Errorf("services: failed to add to inventory: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   fromUserID,
+		ReceiverID: toUserID,
+		Amount:     0,
+		Type:       models.TransactionTypeItemTransfer,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	transfer := &models.ItemTransfer{
+		TransactionID: transaction.ID,
+		SenderID:      fromUserID,
+		ReceiverID:    toUserID,
+		ItemID:        item.ID,
+		VariantID:     variant.ID,
+		Quantity:      quantity,
+	}
+
+	if err := s.itemTransferRepo.Create(ctx, tx, transfer); err != nil {
+		return fmt.Errorf("services: failed to record item transfer: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  toUserID,
+		Type:    models.NotificationTypeItemReceived,
+		Message: fmt.Sprintf("%s transferred you %d x %s", fromUser.Username, quantity, item.Name),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify receiver: %w", err)
+	}
+
+	return nil
+}
+
+// lockPromoCode locks the promo code and checks that the buyer may redeem it
+// for the item.
+func (s *inventoryService) lockPromoCode(
+	ctx context.Context, tx *sqlx.Tx, userID int, item models.Item, code string) (*models.PromoCode, error) {
+	promo, err := s.promoCodeRepo.GetByCodeForUpdate(ctx, tx, code)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get promo code: %w", err)
+	}
+	if promo == nil {
+		return nil, ErrPromoCodeNotFound
+	}
+	if !promo.IsActive(time.Now()) {
+		return nil, ErrPromoCodeInactive
+	}
+	if !promo.AppliesTo(item) {
+		return nil, ErrPromoCodeNotApplicable
+	}
+	if promo.MaxUses != nil && promo.UsedCount >= *promo.MaxUses {
+		return nil, ErrPromoCodeExhausted
+	}
+
+	if promo.PerUserLimit != nil {
+		used, err := s.promoCodeRepo.CountUserRedemptions(ctx, tx, promo.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to count promo redemptions: %w", err)
+		}
+		if used >= *promo.PerUserLimit {
+			return nil, ErrPromoCodeUserLimit
+		}
+	}
+
+	return promo, nil
+}
+
+// resolveVariant picks the variant matching the requested size and colour.
+// Items with a single default variant resolve without any options.
+func resolveVariant(
+	ctx context.Context, itemRepo repository.ItemRepo, itemID int, size string, color string,
+) (*models.ItemVariant, error) {
+	variants, err := itemRepo.GetVariants(ctx, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get item variants: %w", err)
+	}
+
+	var matched []models.ItemVariant
+	for _, v := range variants {
+		if size != "" && v.Size != size {
+			continue
+		}
+		if color != "" && v.Color != color {
+			continue
+		}
+		matched = append(matched, v)
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, ErrVariantNotFound
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, ErrVariantRequired
+	}
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestInventoryService_Buy_DefaultVariant(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	user := &models.User{ID: 1, Balance: 100}
+	item := &models.Item{ID: 2, Name: "cup", Price: 20}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "cup").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 2).Return([]models.ItemVariant{{ID: 5, ItemID: 2}}, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 5, 1).Return(true, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 20, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 2, 5, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == -1 && tr.Amount == 20
+	})).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == 1 && o.VariantID == 5 && o.Quantity == 1 && o.UnitPrice == 20 && o.Total == 20
+	})).Return(nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "cup", models.BuyOptions{})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Buy_VariantPriceOverride(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	xlPrice := 100
+	user := &models.User{ID: 1, Balance: 500}
+	item := &models.Item{ID: 1, Name: "t-shirt", Price: 80}
+	variants := []models.ItemVariant{
+		{ID: 10, ItemID: 1, Size: "S"},
+		{ID: 11, ItemID: 1, Size: "XL", Price: &xlPrice},
+	}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "t-shirt").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 1).Return(variants, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 11, 2).Return(true, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 200, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 1, 11, 2).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.VariantID == 11 && o.Quantity == 2 && o.UnitPrice == 100 && o.Total == 200
+	})).Return(nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "t-shirt", models.BuyOptions{Size: "XL", Quantity: 2})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Buy_VariantRequired(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+
+	inventoryService := NewInventoryService(mockUserRepo, mockItemRepo, nil, nil, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	user := &models.User{ID: 1, Balance: 500}
+	item := &models.Item{ID: 1, Name: "t-shirt", Price: 80}
+	variants := []models.ItemVariant{
+		{ID: 10, ItemID: 1, Size: "S"},
+		{ID: 11, ItemID: 1, Size: "XL"},
+	}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "t-shirt").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 1).Return(variants, nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "t-shirt", models.BuyOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "variant must be specified")
+
+	mockItemRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Buy_OutOfStock(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+
+	inventoryService := NewInventoryService(mockUserRepo, mockItemRepo, nil, nil, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	stock := 0
+	user := &models.User{ID: 1, Balance: 500}
+	item := &models.Item{ID: 6, Name: "hoody", Price: 300}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "hoody").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 6).Return([]models.ItemVariant{{ID: 6, ItemID: 6, Stock: &stock}}, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 6, 1).Return(false, nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "hoody", models.BuyOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "out of stock")
+
+	mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockItemRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Gift(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo, mockNotificationRepo, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	sender := &models.User{ID: 1, Username: "alice", Balance: 100}
+	recipient := &models.User{ID: 2, Username: "bob", Balance: 0}
+	item := &models.Item{ID: 2, Name: "cup", Price: 20}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(sender, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(recipient, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "cup").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 2).Return([]models.ItemVariant{{ID: 5, ItemID: 2}}, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 5, 1).Return(true, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 20, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 2, 2, 5, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Amount == 20 && tr.Type == models.TransactionTypeGift
+	})).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == 1 && o.RecipientID == 2 && o.Message == "thanks!"
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 2 && n.Type == models.NotificationTypeGiftReceived &&
+			n.Message == "alice sent you a gift: cup (thanks!)"
+	})).Return(nil).Once()
+
+	err := inventoryService.Gift(ctx, 1, 2, "cup", models.BuyOptions{}, "thanks!")
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Gift_ToYourself(t *testing.T) {
+	inventoryService := NewInventoryService(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	err := inventoryService.Gift(context.Background(), 1, 1, "cup", models.BuyOptions{}, "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot gift an item to yourself")
+}
+
+func TestInventoryService_Buy_ArchivedItem(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+
+	inventoryService := NewInventoryService(mockUserRepo, mockItemRepo, nil, nil, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	archivedAt := time.Now()
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 500}, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "book").
+		Return(&models.Item{ID: 3, Name: "book", Price: 50, ArchivedAt: &archivedAt}, nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "book", models.BuyOptions{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no longer available")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_TransferItem(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockItemTransferRepo := new(mocks.ItemTransferRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, nil, mockNotificationRepo, mockItemTransferRepo,
+		nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	archivedAt := time.Now()
+	item := &models.Item{ID: 3, Name: "book", Price: 50, ArchivedAt: &archivedAt}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Username: "alice"}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "book").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 3).Return([]models.ItemVariant{{ID: 3, ItemID: 3}}, nil).Once()
+	mockUserRepo.On("LockInventory", ctx, mock.Anything, 1, 3).Return(2, nil).Once()
+	mockUserRepo.On("RemoveFromInventory", ctx, mock.Anything, 1, 3, 2).Return(true, nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 2, 3, 3, 2).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Type == models.TransactionTypeItemTransfer
+	})).Return(nil).Once()
+	mockItemTransferRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(it *models.ItemTransfer) bool {
+		return it.SenderID == 1 && it.ReceiverID == 2 && it.VariantID == 3 && it.Quantity == 2
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 2 && n.Type == models.NotificationTypeItemReceived
+	})).Return(nil).Once()
+
+	err := inventoryService.TransferItem(ctx, 1, 2, "book", models.BuyOptions{Quantity: 2})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockItemTransferRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_TransferItem_NotEnoughItems(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+
+	inventoryService := NewInventoryService(mockUserRepo, mockItemRepo, nil, nil, nil, nil, nil, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Username: "alice"}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "cup").Return(&models.Item{ID: 2, Name: "cup", Price: 20}, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 2).Return([]models.ItemVariant{{ID: 2, ItemID: 2}}, nil).Once()
+	mockUserRepo.On("LockInventory", ctx, mock.Anything, 1, 2).Return(1, nil).Once()
+
+	err := inventoryService.TransferItem(ctx, 1, 2, "cup", models.BuyOptions{Quantity: 3})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not enough items")
+
+	mockUserRepo.AssertNotCalled(t, "RemoveFromInventory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Buy_PromoCode(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockPromoCodeRepo := new(mocks.PromoCodeRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo, nil, nil, mockPromoCodeRepo, noPriceRules(), sqlxDB)
+	ctx := context.Background()
+
+	perUser := 1
+	user := &models.User{ID: 1, Balance: 500}
+	item := &models.Item{ID: 6, Name: "hoody", Price: 300, Category: "apparel"}
+	promo := &models.PromoCode{ID: 3, Code: "FRIDAY", DiscountType: models.DiscountTypePercent,
+		DiscountValue: 25, Categories: []string{"apparel"}, PerUserLimit: &perUser}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "hoody").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 6).Return([]models.ItemVariant{{ID: 6, ItemID: 6}}, nil).Once()
+	mockPromoCodeRepo.On("GetByCodeForUpdate", ctx, mock.Anything, "FRIDAY").Return(promo, nil).Once()
+	mockPromoCodeRepo.On("CountUserRedemptions", ctx, mock.Anything, 3, 1).Return(0, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 6, 1).Return(true, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 225, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 6, 6, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.Amount == 225
+	})).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.ListTotal == 300 && o.Discount == 75 && o.Total == 225 && *o.PromoCodeID == 3
+	})).Return(nil).Once()
+	mockPromoCodeRepo.On("IncrementUsage", ctx, mock.Anything, 3).Return(nil).Once()
+	mockPromoCodeRepo.On("CreateRedemption", ctx, mock.Anything, mock.MatchedBy(func(r *models.PromoRedemption) bool {
+		return r.PromoCodeID == 3 && r.UserID == 1 && r.Discount == 75
+	})).Return(nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "hoody", models.BuyOptions{PromoCode: "FRIDAY"})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockPromoCodeRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestInventoryService_Buy_PromoCodeRejected(t *testing.T) {
+	maxUses := 10
+	perUser := 1
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name        string
+		promo       *models.PromoCode
+		redemptions int
+		expectedErr error
+	}{
+		{
+			name:        "Unknown code",
+			expectedErr: ErrPromoCodeNotFound,
+		},
+		{
+			name:        "Expired code",
+			promo:       &models.PromoCode{ID: 3, DiscountType: models.DiscountTypeFixed, DiscountValue: 10, EndsAt: &past},
+			expectedErr: ErrPromoCodeInactive,
+		},
+		{
+			name: "Item outside whitelist",
+			promo: &models.PromoCode{ID: 3, DiscountType: models.DiscountTypeFixed, DiscountValue: 10,
+				Categories: []string{"books"}},
+			expectedErr: ErrPromoCodeNotApplicable,
+		},
+		{
+			name: "Usage cap reached",
+			promo: &models.PromoCode{ID: 3, DiscountType: models.DiscountTypeFixed, DiscountValue: 10,
+				MaxUses: &maxUses, UsedCount: 10},
+			expectedErr: ErrPromoCodeExhausted,
+		},
+		{
+			name: "Per-user limit reached",
+			promo: &models.PromoCode{ID: 3, DiscountType: models.DiscountTypeFixed, DiscountValue: 10,
+				PerUserLimit: &perUser},
+			redemptions: 1,
+			expectedErr: ErrPromoCodeUserLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockItemRepo := new(mocks.ItemRepo)
+			mockPromoCodeRepo := new(mocks.PromoCodeRepo)
+
+			inventoryService := NewInventoryService(
+				mockUserRepo, mockItemRepo, nil, nil, nil, nil, mockPromoCodeRepo, noPriceRules(), sqlxDB)
+			ctx := context.Background()
+
+			user := &models.User{ID: 1, Balance: 500}
+			item := &models.Item{ID: 2, Name: "cup", Price: 20, Category: "accessories"}
+
+			mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+			mockItemRepo.On("GetItemByName", ctx, "cup").Return(item, nil).Once()
+			mockItemRepo.On("GetVariants", ctx, 2).Return([]models.ItemVariant{{ID: 5, ItemID: 2}}, nil).Once()
+			mockPromoCodeRepo.On("GetByCodeForUpdate", ctx, mock.Anything, "CODE").Return(tt.promo, nil).Once()
+			mockPromoCodeRepo.On("CountUserRedemptions", ctx, mock.Anything, 3, 1).Return(tt.redemptions, nil).Maybe()
+
+			err := inventoryService.Buy(ctx, 1, "cup", models.BuyOptions{PromoCode: "CODE"})
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			mockItemRepo.AssertNotCalled(t, "DecrementStock", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestInventoryService_Buy_FlashSale(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockPriceRuleRepo := new(mocks.PriceRuleRepo)
+
+	inventoryService := NewInventoryService(
+		mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo, nil, nil, nil, mockPriceRuleRepo, sqlxDB)
+	ctx := context.Background()
+
+	itemID := 6
+	salePrice := 200
+	category := "apparel"
+	percent := 10
+	rules := []models.PriceRule{
+		{ID: 1, Category: &category, DiscountPercent: &percent},
+		{ID: 2, ItemID: &itemID, Price: &salePrice},
+	}
+
+	user := &models.User{ID: 1, Balance: 500}
+	item := &models.Item{ID: 6, Name: "hoody", Price: 300, Category: "apparel"}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(user, nil).Once()
+	mockItemRepo.On("GetItemByName", ctx, "hoody").Return(item, nil).Once()
+	mockItemRepo.On("GetVariants", ctx, 6).Return([]models.ItemVariant{{ID: 6, ItemID: 6}}, nil).Once()
+	mockPriceRuleRepo.On("GetActive", ctx, mock.Anything).Return(rules, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 6, 1).Return(true, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 200, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 6, 6, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UnitPrice == 200 && o.ListTotal == 300 && o.Discount == 100 && o.Total == 200
+	})).Return(nil).Once()
+	mockPriceRuleRepo.On("RecordHistory", ctx, mock.Anything, mock.MatchedBy(func(h *models.PriceHistory) bool {
+		return h.OriginalPrice == 300 && h.SalePrice == 200 && *h.PriceRuleID == 2 && h.Charged == 200
+	})).Return(nil).Once()
+
+	err := inventoryService.Buy(ctx, 1, "hoody", models.BuyOptions{})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockPriceRuleRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+// noPriceRules stubs a price rule repository without any active sales.
+func noPriceRules() *mocks.PriceRuleRepo {
+	m := new(mocks.PriceRuleRepo)
+	m.On("GetActive", context.Background(), mock.Anything).Return([]models.PriceRule(nil), nil).Maybe()
+	m.On("RecordHistory", context.Background(), mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidIssuance          = errors.New("services: invalid issuance")
+	ErrGrantAlreadyPaid         = errors.New("services: grant for this period was already paid")
+	ErrAdjustmentExceedsBalance = errors.New("services: adjustment exceeds user balance")
+)
+
+// periodLayout is the YYYY-MM form monthly periods, such as grants and
+// giving allowances, are keyed by.
+const periodLayout = "2006-01"
+
+type IssuanceService interface {
+	Issue(ctx context.Context, adminID int, toUser string, amount int, reason string) (*models.Issuance, error)
+	Adjust(ctx context.Context, adminID int, username string, amount int, reason string) (*models.Issuance, error)
+	Grant(ctx context.Context, adminID int, amount int, period string, reason string) (*models.Issuance, error)
+	List(ctx context.Context) ([]models.Issuance, error)
+}
+
+type issuanceService struct {
+	userRepo         repository.UserRepo
+	transactionRepo  repository.TransactionRepo
+	notificationRepo repository.NotificationRepo
+	issuanceRepo     repository.IssuanceRepo
+	auditService     AuditService
+	db               *sqlx.DB
+}
+
+func NewIssuanceService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	issuanceRepo repository.IssuanceRepo,
+	auditService AuditService,
+	db *sqlx.DB,
+) IssuanceService {
+	return &issuanceService{
+		userRepo:         userRepo,
+		transactionRepo:  transactionRepo,
+		notificationRepo: notificationRepo,
+		issuanceRepo:     issuanceRepo,
+		auditService:     auditService,
+		db:               db,
+	}
+}
+
+// Issue mints new coins for a single user, for example a bonus.
+func (s *issuanceService) Issue(
+	ctx context.Context, adminID int, toUser string, amount int, reason string) (*models.Issuance, error) {
+	if amount < 1 {
+		return nil, ErrInvalidIssuance
+	}
+	return s.issueToUser(ctx, models.IssuanceKindIssuance, adminID, toUser, amount, reason)
+}
+
+// Adjust corrects a user's balance. A positive amount is issued to the
+// user, a negative one is withdrawn back to the issuance account.
+func (s *issuanceService) Adjust(
+	ctx context.Context, adminID int, username string, amount int, reason string) (*models.Issuance, error) {
+	if amount == 0 {
+		return nil, ErrInvalidIssuance
+	}
+	return s.issueToUser(ctx, models.IssuanceKindAdjustment, adminID, username, amount, reason)
+}
+
+func (s *issuanceService) issueToUser(ctx context.Context,
+	kind string, adminID int, username string, amount int, reason string) (issuance *models.Issuance, err error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrInvalidIssuance
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if user == nil || user.ID < 0 {
+		return nil, ErrUserNotFound
+	}
+	if user.Balance+amount < 0 {
+		return nil, ErrAdjustmentExceedsBalance
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	issuance = &models.Issuance{
+		Kind:       kind,
+		Reason:     reason,
+		AdminID:    &adminID,
+		Total:      amount,
+		Recipients: 1,
+	}
+
+	if err := s.issuanceRepo.Create(ctx, tx, issuance); err != nil {
+		return nil, fmt.Errorf("services: failed to create issuance: %w", err)
+	}
+
+	var message string
+	switch {
+	case kind == models.IssuanceKindIssuance:
+		message = fmt.Sprintf("You received %d coins: %s", amount, reason)
+	case amount > 0:
+		message = fmt.Sprintf("Your balance was adjusted by +%d coins: %s", amount, reason)
+	default:
+		message = fmt.Sprintf("Your balance was adjusted by %d coins: %s", amount, reason)
+	}
+
+	if err := s.credit(ctx, tx, issuance, user.ID, amount, message); err != nil {
+		return nil, err
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionCoinsIssued,
+		ActorID:    &adminID,
+		TargetType: models.AuditTargetUser,
+		TargetID:   strconv.Itoa(user.ID),
+		Before:     models.AuditState(map[string]int{"balance": user.Balance}),
+		After: models.AuditState(map[string]any{
+			"balance": user.Balance + amount, "issuanceId": issuance.ID, "reason": reason,
+		}),
+	}
+	if kind == models.IssuanceKindAdjustment {
+		entry.Action = models.AuditActionBalanceAdjusted
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return nil, fmt.Errorf("services: failed to audit issuance: %w", err)
+	}
+
+	return issuance, nil
+}
+
+// Grant pays the same amount to every user once per period. An empty
+// period means the current month.
+func (s *issuanceService) Grant(
+	ctx context.Context, adminID int, amount int, period string, reason string) (issuance *models.Issuance, err error) {
+	reason = strings.TrimSpace(reason)
+	if amount < 1 || reason == "" {
+		return nil, ErrInvalidIssuance
+	}
+
+	if period == "" {
+		period = time.Now().Format(periodLayout)
+	}
+	if _, err := time.Parse(periodLayout, period); err != nil {
+		return nil, ErrInvalidIssuance
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	paid, err := s.issuanceRepo.GetGrantByPeriod(ctx, tx, period)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get grant: %w", err)
+	}
+	if paid != nil {
+		return nil, ErrGrantAlreadyPaid
+	}
+
+	userIDs, err := s.issuanceRepo.GetGrantRecipientIDs(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get grant recipients: %w", err)
+	}
+
+	issuance = &models.Issuance{
+		Kind:       models.IssuanceKindGrant,
+		Reason:     reason,
+		AdminID:    &adminID,
+		Period:     &period,
+		Total:      amount * len(userIDs),
+		Recipients: len(userIDs),
+	}
+
+	if err := s.issuanceRepo.Create(ctx, tx, issuance); err != nil {
+		return nil, fmt.Errorf("services: failed to create issuance: %w", err)
+	}
+
+	message := fmt.Sprintf("You received the %s grant of %d coins: %s", period, amount, reason)
+	for _, userID := range userIDs {
+		if err := s.credit(ctx, tx, issuance, userID, amount, message); err != nil {
+			return nil, err
+		}
+	}
+
+	entry := &models.AuditEntry{
+		Action:     models.AuditActionGrantPaid,
+		ActorID:    &adminID,
+		TargetType: models.AuditTargetGrant,
+		TargetID:   period,
+		After: models.AuditState(map[string]any{
+			"amount": amount, "recipients": issuance.Recipients, "issuanceId": issuance.ID, "reason": reason,
+		}),
+	}
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return nil, fmt.Errorf("services: failed to audit grant: %w", err)
+	}
+
+	return issuance, nil
+}
+
+// credit moves amount between the issuance account and the user and
+// records the ledger entry. A negative amount is withdrawn from the user.
+func (s *issuanceService) credit(ctx context.Context,
+	tx *sqlx.Tx, issuance *models.Issuance, userID int, amount int, message string) error {
+	if amount < 0 {
+		if err := s.userRepo.Debit(ctx, tx, userID, -amount); err != nil {
+			return fmt.Errorf("services: failed to update balance: %w", err)
+		}
+	} else if err := s.userRepo.Credit(ctx, tx, userID, amount, issuance.Kind); err != nil {
+		return fmt.Errorf("services: failed to update balance: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   models.IssuanceAccountID,
+		ReceiverID: userID,
+		Amount:     amount,
+		Type:       issuance.Kind,
+		IssuanceID: &issuance.ID,
+	}
+	if amount < 0 {
+		transaction.SenderID, transaction.ReceiverID = userID, models.IssuanceAccountID
+		transaction.Amount = -amount
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    models.NotificationTypeCoinsIssued,
+		Message: message,
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify user: %w", err)
+	}
+
+	return nil
+}
+
+func (s *issuanceService) List(ctx context.Context) ([]models.Issuance, error) {
+	issuances, err := s.issuanceRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get issuances: %w", err)
+	}
+	return issuances, nil
+}
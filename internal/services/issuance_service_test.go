@@ -0,0 +1,198 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIssuanceService_Issue(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockIssuanceRepo := new(mocks.IssuanceRepo)
+	mockAuditService := new(mocks.AuditService)
+	issuanceService := NewIssuanceService(
+		mockUserRepo, mockTransactionRepo, mockNotificationRepo, mockIssuanceRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob", Balance: 10}, nil).Once()
+	mockIssuanceRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(i *models.Issuance) bool {
+		return i.Kind == models.IssuanceKindIssuance && *i.AdminID == 9 && i.Total == 50 && i.Reason == "Q3 bonus"
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Issuance).ID = 4
+	}).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, 50, models.CoinSourceIssuance).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == models.IssuanceAccountID && tr.ReceiverID == 2 && tr.Amount == 50 &&
+			tr.Type == models.TransactionTypeIssuance && *tr.IssuanceID == 4
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 2 && n.Type == models.NotificationTypeCoinsIssued
+	})).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionCoinsIssued && *e.ActorID == 9 && e.TargetID == "2" &&
+			string(e.Before) == `{"balance":10}`
+	})).Return(nil).Once()
+
+	issuance, err := issuanceService.Issue(ctx, 9, "bob", 50, " Q3 bonus ")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, issuance.ID)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	mockIssuanceRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestIssuanceService_Issue_Invalid(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	issuanceService := NewIssuanceService(mockUserRepo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	_, err := issuanceService.Issue(ctx, 9, "bob", 0, "bonus")
+	assert.ErrorIs(t, err, ErrInvalidIssuance)
+
+	_, err = issuanceService.Issue(ctx, 9, "bob", 10, "  ")
+	assert.ErrorIs(t, err, ErrInvalidIssuance)
+
+	mockUserRepo.On("GetByUsername", ctx, "system").Return(&models.User{ID: -1, Username: "system"}, nil).Once()
+	_, err = issuanceService.Issue(ctx, 9, "system", 10, "bonus")
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestIssuanceService_Adjust_Negative(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockIssuanceRepo := new(mocks.IssuanceRepo)
+	mockAuditService := new(mocks.AuditService)
+	issuanceService := NewIssuanceService(
+		mockUserRepo, mockTransactionRepo, mockNotificationRepo, mockIssuanceRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob", Balance: 100}, nil).Once()
+	mockIssuanceRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 2, 30).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 2 && tr.ReceiverID == models.IssuanceAccountID && tr.Amount == 30 &&
+			tr.Type == models.TransactionTypeAdjustment
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionBalanceAdjusted && string(e.Before) == `{"balance":100}` &&
+			strings.HasPrefix(string(e.After), `{"balance":70,`)
+	})).Return(nil).Once()
+
+	_, err := issuanceService.Adjust(ctx, 9, "bob", -30, "duplicate bonus")
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	mockIssuanceRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestIssuanceService_Adjust_ExceedsBalance(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	issuanceService := NewIssuanceService(mockUserRepo, nil, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob", Balance: 20}, nil).Once()
+
+	_, err := issuanceService.Adjust(ctx, 9, "bob", -30, "duplicate bonus")
+	assert.ErrorIs(t, err, ErrAdjustmentExceedsBalance)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestIssuanceService_Grant(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockIssuanceRepo := new(mocks.IssuanceRepo)
+	mockAuditService := new(mocks.AuditService)
+	issuanceService := NewIssuanceService(
+		mockUserRepo, mockTransactionRepo, mockNotificationRepo, mockIssuanceRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	mockIssuanceRepo.On("GetGrantByPeriod", ctx, mock.Anything, "2026-10").Return(nil, nil).Once()
+	mockIssuanceRepo.On("GetGrantRecipientIDs", ctx, mock.Anything).Return([]int{1, 2, 3}, nil).Once()
+	mockIssuanceRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(i *models.Issuance) bool {
+		return i.Kind == models.IssuanceKindGrant && *i.Period == "2026-10" && i.Total == 300 && i.Recipients == 3
+	})).Return(nil).Once()
+	for _, userID := range []int{1, 2, 3} {
+		mockUserRepo.On("Credit", ctx, mock.Anything, userID, 100, models.CoinSourceGrant).Return(nil).Once()
+	}
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == models.IssuanceAccountID && tr.Type == models.TransactionTypeGrant && tr.Amount == 100
+	})).Return(nil).Times(3)
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Times(3)
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionGrantPaid && e.TargetType == models.AuditTargetGrant &&
+			e.TargetID == "2026-10"
+	})).Return(nil).Once()
+
+	issuance, err := issuanceService.Grant(ctx, 9, 100, "2026-10", "October grant")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, issuance.Recipients)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	mockIssuanceRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestIssuanceService_Grant_AlreadyPaid(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockIssuanceRepo := new(mocks.IssuanceRepo)
+	issuanceService := NewIssuanceService(nil, nil, nil, mockIssuanceRepo, nil, sqlxDB)
+	ctx := context.Background()
+
+	period := "2026-10"
+	mockIssuanceRepo.On("GetGrantByPeriod", ctx, mock.Anything, period).
+		Return(&models.Issuance{ID: 1, Kind: models.IssuanceKindGrant, Period: &period}, nil).Once()
+
+	_, err := issuanceService.Grant(ctx, 9, 100, period, "October grant")
+	assert.ErrorIs(t, err, ErrGrantAlreadyPaid)
+
+	_, err = issuanceService.Grant(ctx, 9, 100, "October", "October grant")
+	assert.ErrorIs(t, err, ErrInvalidIssuance)
+
+	mockIssuanceRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
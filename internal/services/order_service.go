@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strings"
+)
+
+var (
+	ErrInvalidOrderStatus     = errors.New("services: invalid order status")
+	ErrInvalidOrderTransition = errors.New("services: order status transition not allowed")
+	ErrInvalidDelivery        = errors.New("services: invalid delivery details")
+	ErrDeliveryLocked         = errors.New("services: delivery can no longer be changed")
+)
+
+type OrderService interface {
+	GetUserOrders(ctx context.Context, userID int) ([]models.Order, error)
+	GetByStatus(ctx context.Context, status string) ([]models.Order, error)
+	SetDelivery(ctx context.Context, userID int, orderID int, delivery models.Delivery) (*models.Order, error)
+	UpdateStatus(ctx context.Context, orderID int, status string) (*models.Order, error)
+	GetPickList(ctx context.Context) ([]models.PickListEntry, error)
+}
+
+type orderService struct {
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	db               *sqlx.DB
+	refunder         orderRefunder
+}
+
+func NewOrderService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	orderRepo repository.OrderRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	poolRepo repository.PoolRepo,
+	db *sqlx.DB,
+) OrderService {
+	return &orderService{
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
+		refunder: orderRefunder{
+			userRepo:        userRepo,
+			itemRepo:        itemRepo,
+			orderRepo:       orderRepo,
+			transactionRepo: transactionRepo,
+			poolRepo:        poolRepo,
+		},
+	}
+}
+
+func (s *orderService) GetUserOrders(ctx context.Context, userID int) ([]models.Order, error) {
+	orders, err := s.orderRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (s *orderService) GetByStatus(ctx context.Context, status string) ([]models.Order, error) {
+	if !models.IsValidOrderStatus(status) {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	orders, err := s.orderRepo.GetByStatus(ctx, status)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get orders: %w", err)
+	}
+	return orders, nil
+}
+
+// SetDelivery records where the buyer or gift recipient wants to receive the
+// order. It can be changed until the order is packed.
+func (s *orderService) SetDelivery(
+	ctx context.Context, userID int, orderID int, delivery models.Delivery) (order *models.Order, err error) {
+	delivery.Office = strings.TrimSpace(delivery.Office)
+	delivery.ShippingAddress = strings.TrimSpace(delivery.ShippingAddress)
+
+	switch delivery.Method {
+	case models.DeliveryMethodPickup:
+		if delivery.Office == "" {
+			return nil, ErrInvalidDelivery
+		}
+		delivery.ShippingAddress = ""
+	case models.DeliveryMethodShipping:
+		if delivery.ShippingAddress == "" {
+			return nil, ErrInvalidDelivery
+		}
+		delivery.Office = ""
+	default:
+		return nil, ErrInvalidDelivery
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	order, err = s.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock order: %w", err)
+	}
+	if order == nil || (order.UserID != userID && order.RecipientID != userID) {
+		return nil, ErrOrderNotFound
+	}
+	if order.Status != models.OrderStatusPlaced && order.Status != models.OrderStatusApproved {
+		return nil, ErrDeliveryLocked
+	}
+
+	if err := s.orderRepo.UpdateDelivery(ctx, tx, orderID, delivery); err != nil {
+		return nil, fmt.Errorf("services: failed to update delivery: %w", err)
+	}
+
+	order.DeliveryMethod = delivery.Method
+	order.Office = delivery.Office
+	order.ShippingAddress = delivery.ShippingAddress
+
+	return order, nil
+}
+
+// UpdateStatus moves an order along the fulfillment workflow. Cancelling an
+// order refunds the units that have not been returned yet.
+func (s *orderService) UpdateStatus(ctx context.Context, orderID int, status string) (order *models.Order, err error) {
+	if !models.IsValidOrderStatus(status) {
+		return nil, ErrInvalidOrderStatus
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	order, err = s.orderRepo.GetByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock order: %w", err)
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if !order.CanTransitionTo(status) {
+		return nil, ErrInvalidOrderTransition
+	}
+
+	switch status {
+	case models.OrderStatusReadyForPickup:
+		if order.DeliveryMethod != models.DeliveryMethodPickup || order.Office == "" {
+			return nil, ErrInvalidDelivery
+		}
+	case models.OrderStatusShipped:
+		if order.DeliveryMethod != models.DeliveryMethodShipping || order.ShippingAddress == "" {
+			return nil, ErrInvalidDelivery
+		}
+	case models.OrderStatusCancelled:
+		if remaining := order.Quantity - order.RefundedQuantity; remaining > 0 {
+			if _, _, err := s.refunder.refund(ctx, tx, order, remaining); err != nil {
+				return nil, err
+			}
+			order.RefundedQuantity = order.Quantity
+		}
+	}
+
+	if err := s.orderRepo.UpdateStatus(ctx, tx, orderID, status); err != nil {
+		return nil, fmt.Errorf("services: failed to update order status: %w", err)
+	}
+	order.Status = status
+
+	if message := orderStatusMessage(order); message != "" {
+		notification := &models.Notification{
+			UserID:  order.RecipientID,
+			Type:    models.NotificationTypeOrderStatus,
+			Message: message,
+		}
+
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return nil, fmt.Errorf("services: failed to notify recipient: %w", err)
+		}
+	}
+
+	return order, nil
+}
+
+func (s *orderService) GetPickList(ctx context.Context) ([]models.PickListEntry, error) {
+	entries, err := s.orderRepo.GetPickList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get pick list: %w", err)
+	}
+	return entries, nil
+}
+
+func orderStatusMessage(order *models.Order) string {
+	switch order.Status {
+	case models.OrderStatusReadyForPickup:
+		return fmt.Sprintf("Order #%d is ready for pickup at %s", order.ID, order.Office)
+	case models.OrderStatusShipped:
+		return fmt.Sprintf("Order #%d has been shipped", order.ID)
+	case models.OrderStatusCancelled:
+		return fmt.Sprintf("Order #%d has been cancelled", order.ID)
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOrderService_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       *models.Order
+		status      string
+		notify      bool
+		expectedErr error
+	}{
+		{
+			name:   "Approve placed order",
+			order:  &models.Order{ID: 1, Status: models.OrderStatusPlaced},
+			status: models.OrderStatusApproved,
+		},
+		{
+			name: "Packed pickup order ready for pickup",
+			order: &models.Order{ID: 1, Status: models.OrderStatusPacked,
+				DeliveryMethod: models.DeliveryMethodPickup, Office: "Moscow"},
+			status: models.OrderStatusReadyForPickup,
+			notify: true,
+		},
+		{
+			name:        "Skip packing",
+			order:       &models.Order{ID: 1, Status: models.OrderStatusApproved},
+			status:      models.OrderStatusShipped,
+			expectedErr: ErrInvalidOrderTransition,
+		},
+		{
+			name:        "Cancel delivered order",
+			order:       &models.Order{ID: 1, Status: models.OrderStatusDelivered},
+			status:      models.OrderStatusCancelled,
+			expectedErr: ErrInvalidOrderTransition,
+		},
+		{
+			name: "Ship pickup order",
+			order: &models.Order{ID: 1, Status: models.OrderStatusPacked,
+				DeliveryMethod: models.DeliveryMethodPickup, Office: "Moscow"},
+			status:      models.OrderStatusShipped,
+			expectedErr: ErrInvalidDelivery,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockOrderRepo := new(mocks.OrderRepo)
+			mockNotificationRepo := new(mocks.NotificationRepo)
+			orderService := NewOrderService(nil, nil, mockOrderRepo, nil, mockNotificationRepo, nil, sqlxDB)
+			ctx := context.Background()
+
+			mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(tt.order, nil).Once()
+			mockOrderRepo.On("UpdateStatus", ctx, mock.Anything, 1, tt.status).Return(nil).Maybe()
+			mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+			order, err := orderService.UpdateStatus(ctx, 1, tt.status)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockOrderRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.status, order.Status)
+			}
+			if tt.notify {
+				mockNotificationRepo.AssertNumberOfCalls(t, "Create", 1)
+			} else {
+				mockNotificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrderService_Cancel_RefundsRemainingUnits(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+
+	orderService := NewOrderService(
+		mockUserRepo, mockItemRepo, mockOrderRepo, mockTransactionRepo, mockNotificationRepo, mockPoolRepo, sqlxDB)
+	ctx := context.Background()
+
+	order := &models.Order{ID: 3, UserID: 1, RecipientID: 2, VariantID: 5, TransactionID: 42,
+		Quantity: 3, RefundedQuantity: 1, UnitPrice: 20, Total: 60, Status: models.OrderStatusApproved}
+
+	mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(order, nil).Once()
+	mockUserRepo.On("RemoveFromInventory", ctx, mock.Anything, 2, 5, 2).Return(true, nil).Once()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 5, 2).Return(nil).Once()
+	mockPoolRepo.On("GetSpentContributionsByOrder", ctx, mock.Anything, 3).Return(nil, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 1, 40, models.CoinSourceRefund, mock.Anything).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.ReceiverID == 1 && tr.Amount == 40 && tr.Type == models.TransactionTypeRefund
+	})).Return(nil).Once()
+	mockOrderRepo.On("AddRefundedQuantity", ctx, mock.Anything, 3, 2).Return(nil).Once()
+	mockOrderRepo.On("UpdateStatus", ctx, mock.Anything, 3, models.OrderStatusCancelled).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 2 && n.Type == models.NotificationTypeOrderStatus
+	})).Return(nil).Once()
+
+	cancelled, err := orderService.UpdateStatus(ctx, 3, models.OrderStatusCancelled)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cancelled.RefundedQuantity)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestOrderService_SetDelivery(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       *models.Order
+		delivery    models.Delivery
+		expectedErr error
+	}{
+		{
+			name:     "Pickup office for placed order",
+			order:    &models.Order{ID: 1, UserID: 1, RecipientID: 1, Status: models.OrderStatusPlaced},
+			delivery: models.Delivery{Method: models.DeliveryMethodPickup, Office: "Moscow"},
+		},
+		{
+			name:     "Gift recipient chooses shipping",
+			order:    &models.Order{ID: 1, UserID: 2, RecipientID: 1, Status: models.OrderStatusApproved},
+			delivery: models.Delivery{Method: models.DeliveryMethodShipping, ShippingAddress: "Lenina 1"},
+		},
+		{
+			name:        "Order already packed",
+			order:       &models.Order{ID: 1, UserID: 1, RecipientID: 1, Status: models.OrderStatusPacked},
+			delivery:    models.Delivery{Method: models.DeliveryMethodPickup, Office: "Moscow"},
+			expectedErr: ErrDeliveryLocked,
+		},
+		{
+			name:        "Order of another user",
+			order:       &models.Order{ID: 1, UserID: 2, RecipientID: 3, Status: models.OrderStatusPlaced},
+			delivery:    models.Delivery{Method: models.DeliveryMethodPickup, Office: "Moscow"},
+			expectedErr: ErrOrderNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockOrderRepo := new(mocks.OrderRepo)
+			orderService := NewOrderService(nil, nil, mockOrderRepo, nil, nil, nil, sqlxDB)
+			ctx := context.Background()
+
+			mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(tt.order, nil).Once()
+			mockOrderRepo.On("UpdateDelivery", ctx, mock.Anything, 1, tt.delivery).Return(nil).Maybe()
+
+			order, err := orderService.SetDelivery(ctx, 1, 1, tt.delivery)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.delivery.Method, order.DeliveryMethod)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOrderService_SetDelivery_MissingAddress(t *testing.T) {
+	orderService := NewOrderService(nil, nil, nil, nil, nil, nil, nil)
+
+	_, err := orderService.SetDelivery(context.Background(), 1, 1, models.Delivery{Method: models.DeliveryMethodShipping})
+	assert.ErrorIs(t, err, ErrInvalidDelivery)
+}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPoolNotFound            = errors.New("services: pool not found")
+	ErrInvalidPool             = errors.New("services: invalid pool")
+	ErrPoolItemUnavailable     = errors.New("services: item is out of stock")
+	ErrPoolClosed              = errors.New("services: pool is closed")
+	ErrInvalidContribution     = errors.New("services: invalid contribution amount")
+	ErrPoolContributionTooHigh = errors.New("services: contribution exceeds the remaining target")
+)
+
+type PoolService interface {
+	Create(ctx context.Context, creatorID int, pool *models.Pool, itemName string, opts models.BuyOptions) error
+	List(ctx context.Context) ([]models.Pool, error)
+	Contribute(ctx context.Context, userID int, poolID int, amount int) (*models.PoolContribution, error)
+	ExpireOverdue(ctx context.Context) (int, error)
+}
+
+type poolService struct {
+	userRepo         repository.UserRepo
+	itemRepo         repository.ItemRepo
+	transactionRepo  repository.TransactionRepo
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	priceRuleRepo    repository.PriceRuleRepo
+	poolRepo         repository.PoolRepo
+	db               *sqlx.DB
+}
+
+func NewPoolService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	transactionRepo repository.TransactionRepo,
+	orderRepo repository.OrderRepo,
+	notificationRepo repository.NotificationRepo,
+	priceRuleRepo repository.PriceRuleRepo,
+	poolRepo repository.PoolRepo,
+	db *sqlx.DB,
+) PoolService {
+	return &poolService{
+		userRepo:         userRepo,
+		itemRepo:         itemRepo,
+		transactionRepo:  transactionRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		priceRuleRepo:    priceRuleRepo,
+		poolRepo:         poolRepo,
+		db:               db,
+	}
+}
+
+// Create opens a pool for one unit of an item variant. The target is the
+// current sale price and the unit is taken out of stock until the pool is
+// funded or expires.
+func (s *poolService) Create(
+	ctx context.Context, creatorID int, pool *models.Pool, itemName string, opts models.BuyOptions) (err error) {
+	pool.Title = strings.TrimSpace(pool.Title)
+	if pool.Title == "" || !pool.Deadline.After(time.Now()) {
+		return ErrInvalidPool
+	}
+
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil || item.ArchivedAt != nil {
+		return ErrItemNotFound
+	}
+
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+	if err != nil {
+		return err
+	}
+
+	rules, err := s.priceRuleRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("services: failed to get price rules: %w", err)
+	}
+
+	pool.Target, _ = models.SalePrice(*item, variant.UnitPrice(*item), rules)
+	pool.ItemID = item.ID
+	pool.VariantID = variant.ID
+	pool.Item = item.Name
+	pool.Size = variant.Size
+	pool.Color = variant.Color
+	pool.Status = models.PoolStatusOpen
+	pool.CreatorID = creatorID
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	reserved, err := s.itemRepo.DecrementStock(ctx, tx, variant.ID, 1)
+	if err != nil {
+		return fmt.Errorf("services: failed to reserve stock: %w", err)
+	}
+	if !reserved {
+		return ErrPoolItemUnavailable
+	}
+
+	if err := s.poolRepo.Create(ctx, tx, pool); err != nil {
+		return fmt.Errorf("services: failed to create pool: %w", err)
+	}
+
+	return nil
+}
+
+func (s *poolService) List(ctx context.Context) ([]models.Pool, error) {
+	pools, err := s.poolRepo.GetOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get pools: %w", err)
+	}
+	return pools, nil
+}
+
+// Contribute holds the amount from the contributor's balance. The
+// contribution that reaches the target triggers the purchase.
+func (s *poolService) Contribute(
+	ctx context.Context, userID int, poolID int, amount int) (contribution *models.PoolContribution, err error) {
+	if amount < 1 {
+		return nil, ErrInvalidContribution
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	pool, err := s.poolRepo.GetByIDForUpdate(ctx, tx, poolID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock pool: %w", err)
+	}
+	if pool == nil {
+		return nil, ErrPoolNotFound
+	}
+	if pool.Status != models.PoolStatusOpen || !time.Now().Before(pool.Deadline) {
+		return nil, ErrPoolClosed
+	}
+	if amount > pool.Remaining() {
+		return nil, ErrPoolContributionTooHigh
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by id: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("services: user not found")
+	}
+	if user.Balance < amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	contribution = &models.PoolContribution{
+		PoolID: pool.ID,
+		UserID: userID,
+		Amount: amount,
+		Status: models.ContributionStatusHeld,
+	}
+
+	if err := s.poolRepo.CreateContribution(ctx, tx, contribution); err != nil {
+		return nil, fmt.Errorf("services: failed to create contribution: %w", err)
+	}
+
+	if err := s.userRepo.Hold(ctx, tx, userID, amount, contribution.CoinHold()); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("services: failed to hold contribution: %w", err)
+	}
+
+	pool.Raised += amount
+	if pool.Remaining() == 0 {
+		if err := s.purchase(ctx, tx, pool); err != nil {
+			return nil, err
+		}
+		contribution.Status = models.ContributionStatusSpent
+	}
+
+	return contribution, nil
+}
+
+// purchase turns every held contribution into a payment to the store, one
+// transaction per contribution, and delivers the item to the pool creator.
+func (s *poolService) purchase(ctx context.Context, tx *sqlx.Tx, pool *models.Pool) error {
+	contributions, err := s.poolRepo.GetHeldContributions(ctx, tx, pool.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get contributions: %w", err)
+	}
+
+	var transaction *models.Transaction
+	for i := range contributions {
+		contribution := &contributions[i]
+
+		transaction = &models.Transaction{
+			SenderID:   contribution.UserID,
+			ReceiverID: -1,
+			Amount:     contribution.Amount,
+			Type:       models.TransactionTypePoolPurchase,
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("services: failed to create transaction: %w", err)
+		}
+
+		contribution.Status = models.ContributionStatusSpent
+		contribution.TransactionID = &transaction.ID
+
+		if err := s.poolRepo.ResolveContribution(ctx, tx, contribution); err != nil {
+			return fmt.Errorf("services: failed to resolve contribution: %w", err)
+		}
+	}
+
+	err = s.userRepo.AddOrIncrementItemInventory(ctx, tx, pool.CreatorID, pool.ItemID, pool.VariantID, 1)
+	if err != nil {
+		return fmt.Errorf("services: failed to add to inventory: %w", err)
+	}
+
+	order := &models.Order{
+		UserID:        pool.CreatorID,
+		RecipientID:   pool.CreatorID,
+		ItemID:        pool.ItemID,
+		VariantID:     pool.VariantID,
+		TransactionID: transaction.ID,
+		Quantity:      1,
+		UnitPrice:     pool.Target,
+		ListTotal:     pool.Target,
+		Total:         pool.Target,
+	}
+
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return fmt.Errorf("services: failed to create order: %w", err)
+	}
+
+	pool.Status = models.PoolStatusFunded
+	pool.OrderID = &order.ID
+
+	if err := s.poolRepo.Close(ctx, tx, pool); err != nil {
+		return fmt.Errorf("services: failed to close pool: %w", err)
+	}
+
+	message := fmt.Sprintf("The pool %q reached its target and %s has been ordered", pool.Title, pool.Item)
+	return s.notifyContributors(ctx, tx, contributions, models.NotificationTypePoolFunded, message)
+}
+
+// ExpireOverdue refunds every pool whose deadline passed before the target
+// was reached, each in its own transaction.
+func (s *poolService) ExpireOverdue(ctx context.Context) (int, error) {
+	ids, err := s.poolRepo.GetExpiredIDs(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get expired pools: %w", err)
+	}
+
+	expired := 0
+	var errs []error
+	for _, id := range ids {
+		ok, err := s.expire(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+func (s *poolService) expire(ctx context.Context, poolID int) (expired bool, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	pool, err := s.poolRepo.GetByIDForUpdate(ctx, tx, poolID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to lock pool: %w", err)
+	}
+	if pool == nil || pool.Status != models.PoolStatusOpen || time.Now().Before(pool.Deadline) {
+		return false, nil
+	}
+
+	contributions, err := s.poolRepo.GetHeldContributions(ctx, tx, pool.ID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to get contributions: %w", err)
+	}
+
+	for i := range contributions {
+		contribution := &contributions[i]
+
+		err := s.userRepo.Release(ctx, tx, contribution.UserID, contribution.Amount,
+			models.CoinSourceRelease, contribution.CoinHold())
+		if err != nil {
+			return false, fmt.Errorf("services: failed to refund contribution: %w", err)
+		}
+
+		contribution.Status = models.ContributionStatusRefunded
+
+		if err := s.poolRepo.ResolveContribution(ctx, tx, contribution); err != nil {
+			return false, fmt.Errorf("services: failed to resolve contribution: %w", err)
+		}
+	}
+
+	if err := s.itemRepo.IncrementStock(ctx, tx, pool.VariantID, 1); err != nil {
+		return false, fmt.Errorf("services: failed to restock pool item: %w", err)
+	}
+
+	pool.Status = models.PoolStatusExpired
+
+	if err := s.poolRepo.Close(ctx, tx, pool); err != nil {
+		return false, fmt.Errorf("services: failed to close pool: %w", err)
+	}
+
+	message := fmt.Sprintf("The pool %q expired before reaching its target, your coins were returned", pool.Title)
+	if err := s.notifyContributors(ctx, tx, contributions, models.NotificationTypePoolExpired, message); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// notifyContributors sends one notification to every distinct contributor.
+func (s *poolService) notifyContributors(ctx context.Context, tx *sqlx.Tx,
+	contributions []models.PoolContribution, notificationType string, message string) error {
+	notified := make(map[int]bool)
+	for _, contribution := range contributions {
+		if notified[contribution.UserID] {
+			continue
+		}
+		notified[contribution.UserID] = true
+
+		notification := &models.Notification{
+			UserID:  contribution.UserID,
+			Type:    notificationType,
+			Message: message,
+		}
+
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return fmt.Errorf("services: failed to notify contributor: %w", err)
+		}
+	}
+	return nil
+}
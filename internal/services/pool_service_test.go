@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPoolService_Contribute_TooHigh(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+	poolService := NewPoolService(mockUserRepo, nil, nil, nil, nil, nil, mockPoolRepo, sqlxDB)
+	ctx := context.Background()
+
+	pool := &models.Pool{ID: 1, Target: 500, Raised: 450, Status: models.PoolStatusOpen,
+		Deadline: time.Now().Add(time.Hour)}
+	mockPoolRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(pool, nil).Once()
+
+	contribution, err := poolService.Contribute(ctx, 7, 1, 100)
+	assert.ErrorIs(t, err, ErrPoolContributionTooHigh)
+	assert.Nil(t, contribution)
+
+	mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPoolService_Contribute_ReachesTarget(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+	poolService := NewPoolService(mockUserRepo, nil, mockTransactionRepo, mockOrderRepo,
+		mockNotificationRepo, nil, mockPoolRepo, sqlxDB)
+	ctx := context.Background()
+
+	pool := &models.Pool{ID: 1, Title: "Team room", ItemID: 3, VariantID: 5, Item: "powerbank", Target: 500,
+		Raised: 400, Status: models.PoolStatusOpen, CreatorID: 2, Deadline: time.Now().Add(time.Hour)}
+	held := []models.PoolContribution{
+		{ID: 1, PoolID: 1, UserID: 2, Amount: 300, Status: models.ContributionStatusHeld},
+		{ID: 2, PoolID: 1, UserID: 8, Amount: 100, Status: models.ContributionStatusHeld},
+		{ID: 3, PoolID: 1, UserID: 2, Amount: 100, Status: models.ContributionStatusHeld},
+	}
+
+	mockPoolRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(pool, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Balance: 150}, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 2, 100, mock.Anything).Return(nil).Once()
+	mockPoolRepo.On("CreateContribution", ctx, mock.Anything, mock.MatchedBy(func(c *models.PoolContribution) bool {
+		return c.PoolID == 1 && c.UserID == 2 && c.Amount == 100 && c.Status == models.ContributionStatusHeld
+	})).Return(nil).Once()
+	mockPoolRepo.On("GetHeldContributions", ctx, mock.Anything, 1).Return(held, nil).Once()
+	for _, c := range held {
+		c := c
+		mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+			return tr.SenderID == c.UserID && tr.ReceiverID == -1 && tr.Amount == c.Amount &&
+				tr.Type == models.TransactionTypePoolPurchase
+		})).Return(nil).Once()
+	}
+	mockPoolRepo.On("ResolveContribution", ctx, mock.Anything, mock.MatchedBy(func(c *models.PoolContribution) bool {
+		return c.Status == models.ContributionStatusSpent
+	})).Return(nil).Times(3)
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 2, 3, 5, 1).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == 2 && o.RecipientID == 2 && o.VariantID == 5 && o.Total == 500
+	})).Return(nil).Once()
+	mockPoolRepo.On("Close", ctx, mock.Anything, mock.MatchedBy(func(p *models.Pool) bool {
+		return p.Status == models.PoolStatusFunded && p.OrderID != nil
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Type == models.NotificationTypePoolFunded
+	})).Return(nil).Twice()
+
+	contribution, err := poolService.Contribute(ctx, 2, 1, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ContributionStatusSpent, contribution.Status)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockPoolRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPoolService_ExpireOverdue(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+	poolService := NewPoolService(mockUserRepo, mockItemRepo, nil, nil, mockNotificationRepo, nil,
+		mockPoolRepo, sqlxDB)
+	ctx := context.Background()
+
+	pool := &models.Pool{ID: 1, Title: "Team room", VariantID: 5, Target: 500, Raised: 250,
+		Status: models.PoolStatusOpen, Deadline: time.Now().Add(-time.Minute)}
+	held := []models.PoolContribution{
+		{ID: 1, PoolID: 1, UserID: 2, Amount: 200, Status: models.ContributionStatusHeld},
+		{ID: 2, PoolID: 1, UserID: 8, Amount: 50, Status: models.ContributionStatusHeld},
+	}
+
+	mockPoolRepo.On("GetExpiredIDs", ctx, mock.Anything).Return([]int{1}, nil).Once()
+	mockPoolRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(pool, nil).Once()
+	mockPoolRepo.On("GetHeldContributions", ctx, mock.Anything, 1).Return(held, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 2, 200, models.CoinSourceRelease, mock.Anything).Return(nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 8, 50, models.CoinSourceRelease, mock.Anything).Return(nil).Once()
+	mockPoolRepo.On("ResolveContribution", ctx, mock.Anything, mock.MatchedBy(func(c *models.PoolContribution) bool {
+		return c.Status == models.ContributionStatusRefunded
+	})).Return(nil).Twice()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 5, 1).Return(nil).Once()
+	mockPoolRepo.On("Close", ctx, mock.Anything, mock.MatchedBy(func(p *models.Pool) bool {
+		return p.Status == models.PoolStatusExpired
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Type == models.NotificationTypePoolExpired
+	})).Return(nil).Twice()
+
+	expired, err := poolService.ExpireOverdue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockPoolRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
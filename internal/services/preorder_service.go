@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrPreorderNotFound      = errors.New("services: preorder not found")
+	ErrPreorderNotAllowed    = errors.New("services: item cannot be pre-ordered")
+	ErrPreorderItemAvailable = errors.New("services: item is available, buy it instead")
+	ErrPreorderResolved      = errors.New("services: preorder is already fulfilled or cancelled")
+)
+
+type PreorderService interface {
+	Place(ctx context.Context, userID int, itemName string, opts models.BuyOptions) (*models.Preorder, error)
+	Cancel(ctx context.Context, userID int, preorderID int) (*models.Preorder, error)
+	List(ctx context.Context, userID int) ([]models.Preorder, error)
+	Capture(ctx context.Context, tx *sqlx.Tx, variantID int) (int, error)
+}
+
+type preorderService struct {
+	userRepo         repository.UserRepo
+	itemRepo         repository.ItemRepo
+	transactionRepo  repository.TransactionRepo
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	priceRuleRepo    repository.PriceRuleRepo
+	preorderRepo     repository.PreorderRepo
+	db               *sqlx.DB
+}
+
+func NewPreorderService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	transactionRepo repository.TransactionRepo,
+	orderRepo repository.OrderRepo,
+	notificationRepo repository.NotificationRepo,
+	priceRuleRepo repository.PriceRuleRepo,
+	preorderRepo repository.PreorderRepo,
+	db *sqlx.DB,
+) PreorderService {
+	return &preorderService{
+		userRepo:         userRepo,
+		itemRepo:         itemRepo,
+		transactionRepo:  transactionRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		priceRuleRepo:    priceRuleRepo,
+		preorderRepo:     preorderRepo,
+		db:               db,
+	}
+}
+
+// Place takes the price of an unavailable item from the user's balance and
+// holds it until the item arrives or the pre-order is cancelled.
+func (s *preorderService) Place(
+	ctx context.Context, userID int, itemName string, opts models.BuyOptions) (preorder *models.Preorder, err error) {
+	quantity := opts.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("services: invalid quantity")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by id: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("services: user not found")
+	}
+
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return nil, ErrItemNotFound
+	}
+	if !item.PreorderEnabled || item.ArchivedAt != nil {
+		return nil, ErrPreorderNotAllowed
+	}
+
+	variant, err := resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+	if err != nil {
+		return nil, err
+	}
+	if variant.Stock == nil || *variant.Stock >= quantity {
+		return nil, ErrPreorderItemAvailable
+	}
+
+	rules, err := s.priceRuleRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get price rules: %w", err)
+	}
+
+	originalPrice := variant.UnitPrice(*item)
+	unitPrice, _ := models.SalePrice(*item, originalPrice, rules)
+	amount := unitPrice * quantity
+
+	if user.Balance < amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	preorder = &models.Preorder{
+		UserID:        userID,
+		ItemID:        item.ID,
+		VariantID:     variant.ID,
+		Item:          item.Name,
+		Size:          variant.Size,
+		Color:         variant.Color,
+		Quantity:      quantity,
+		OriginalPrice: originalPrice,
+		UnitPrice:     unitPrice,
+		Amount:        amount,
+		Status:        models.PreorderStatusPlaced,
+	}
+
+	if err := s.preorderRepo.Create(ctx, tx, preorder); err != nil {
+		return nil, fmt.Errorf("services: failed to create preorder: %w", err)
+	}
+
+	hold := models.CoinHold{Type: models.CoinHoldPreorder, ID: preorder.ID}
+	if err := s.userRepo.Hold(ctx, tx, userID, amount, hold); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("services: failed to hold coins: %w", err)
+	}
+
+	return preorder, nil
+}
+
+// Cancel releases the held coins back to the user's balance.
+func (s *preorderService) Cancel(ctx context.Context, userID int, preorderID int) (preorder *models.Preorder, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	preorder, err = s.preorderRepo.GetByIDForUpdate(ctx, tx, preorderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock preorder: %w", err)
+	}
+	if preorder == nil || preorder.UserID != userID {
+		return nil, ErrPreorderNotFound
+	}
+	if preorder.Status != models.PreorderStatusPlaced {
+		return nil, ErrPreorderResolved
+	}
+
+	hold := models.CoinHold{Type: models.CoinHoldPreorder, ID: preorder.ID}
+	if err := s.userRepo.Release(ctx, tx, userID, preorder.Amount, models.CoinSourceRelease, hold); err != nil {
+		return nil, fmt.Errorf("services: failed to release coins: %w", err)
+	}
+
+	preorder.Status = models.PreorderStatusCancelled
+
+	if err := s.preorderRepo.Resolve(ctx, tx, preorder); err != nil {
+		return nil, fmt.Errorf("services: failed to update preorder: %w", err)
+	}
+
+	return preorder, nil
+}
+
+func (s *preorderService) List(ctx context.Context, userID int) ([]models.Preorder, error) {
+	preorders, err := s.preorderRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get preorders: %w", err)
+	}
+	return preorders, nil
+}
+
+// Capture turns open pre-orders of a variant into orders, oldest first, for
+// as long as there is stock. The held coins become the purchase payment.
+// It runs in the caller's transaction, so the pre-orders get the new stock
+// before anyone else can buy it. It returns the number of pre-orders
+// fulfilled.
+func (s *preorderService) Capture(ctx context.Context, tx *sqlx.Tx, variantID int) (captured int, err error) {
+	preorders, err := s.preorderRepo.GetPlacedByVariantForUpdate(ctx, tx, variantID)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get preorders: %w", err)
+	}
+
+	for i := range preorders {
+		fulfilled, err := s.capture(ctx, tx, &preorders[i])
+		if err != nil {
+			return 0, err
+		}
+		if !fulfilled {
+			break
+		}
+		captured++
+	}
+
+	return captured, nil
+}
+
+func (s *preorderService) capture(ctx context.Context, tx *sqlx.Tx, preorder *models.Preorder) (bool, error) {
+	reserved, err := s.itemRepo.DecrementStock(ctx, tx, preorder.VariantID, preorder.Quantity)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to reserve stock: %w", err)
+	}
+	if !reserved {
+		return false, nil
+	}
+
+	err = s.userRepo.AddOrIncrementItemInventory(
+		ctx, tx, preorder.UserID, preorder.ItemID, preorder.VariantID, preorder.Quantity)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to add to inventory: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   preorder.UserID,
+		ReceiverID: -1,
+		Amount:     preorder.Amount,
+		Type:       models.TransactionTypePurchase,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return false, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	listTotal := preorder.OriginalPrice * preorder.Quantity
+	order := &models.Order{
+		UserID:        preorder.UserID,
+		RecipientID:   preorder.UserID,
+		ItemID:        preorder.ItemID,
+		VariantID:     preorder.VariantID,
+		TransactionID: transaction.ID,
+		Quantity:      preorder.Quantity,
+		UnitPrice:     preorder.UnitPrice,
+		ListTotal:     listTotal,
+		Discount:      listTotal - preorder.Amount,
+		Total:         preorder.Amount,
+	}
+
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return false, fmt.Errorf("services: failed to create order: %w", err)
+	}
+
+	err = s.userRepo.MoveHold(ctx, tx, preorder.UserID,
+		models.CoinHold{Type: models.CoinHoldPreorder, ID: preorder.ID},
+		order.CoinHold())
+	if err != nil {
+		return false, fmt.Errorf("services: failed to move held coins to the order: %w", err)
+	}
+
+	history := &models.PriceHistory{
+		OrderID:       order.ID,
+		UserID:        preorder.UserID,
+		ItemID:        preorder.ItemID,
+		VariantID:     preorder.VariantID,
+		OriginalPrice: preorder.OriginalPrice,
+		SalePrice:     preorder.UnitPrice,
+		Charged:       preorder.Amount,
+	}
+
+	if err := s.priceRuleRepo.RecordHistory(ctx, tx, history); err != nil {
+		return false, fmt.Errorf("services: failed to record price history: %w", err)
+	}
+
+	preorder.Status = models.PreorderStatusFulfilled
+	preorder.OrderID = &order.ID
+
+	if err := s.preorderRepo.Resolve(ctx, tx, preorder); err != nil {
+		return false, fmt.Errorf("services: failed to update preorder: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  preorder.UserID,
+		Type:    models.NotificationTypePreorderFulfilled,
+		Message: fmt.Sprintf("Your pre-order of %d x %s has been fulfilled", preorder.Quantity, preorder.Item),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return false, fmt.Errorf("services: failed to notify user: %w", err)
+	}
+
+	return true, nil
+}
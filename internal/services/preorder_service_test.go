@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPreorderService_Place(t *testing.T) {
+	zero := 0
+	ten := 10
+
+	tests := []struct {
+		name        string
+		item        *models.Item
+		stock       *int
+		expectedErr error
+	}{
+		{
+			name:  "Sold out item open for pre-order",
+			item:  &models.Item{ID: 10, Name: "pink-hoody", Price: 500, PreorderEnabled: true},
+			stock: &zero,
+		},
+		{
+			name:        "Item not open for pre-order",
+			item:        &models.Item{ID: 10, Name: "pink-hoody", Price: 500},
+			stock:       &zero,
+			expectedErr: ErrPreorderNotAllowed,
+		},
+		{
+			name:        "Item in stock",
+			item:        &models.Item{ID: 10, Name: "pink-hoody", Price: 500, PreorderEnabled: true},
+			stock:       &ten,
+			expectedErr: ErrPreorderItemAvailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockItemRepo := new(mocks.ItemRepo)
+			mockPreorderRepo := new(mocks.PreorderRepo)
+
+			preorderService := NewPreorderService(
+				mockUserRepo, mockItemRepo, nil, nil, nil, noPriceRules(), mockPreorderRepo, sqlxDB)
+			ctx := context.Background()
+
+			mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 1000}, nil).Once()
+			mockItemRepo.On("GetItemByName", ctx, "pink-hoody").Return(tt.item, nil).Once()
+			mockItemRepo.On("GetVariants", ctx, 10).
+				Return([]models.ItemVariant{{ID: 12, ItemID: 10, Stock: tt.stock}}, nil).Maybe()
+			mockUserRepo.On("Hold", ctx, mock.Anything, 1, 500, mock.Anything).Return(nil).Maybe()
+			mockPreorderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(p *models.Preorder) bool {
+				return p.VariantID == 12 && p.Amount == 500 && p.Status == models.PreorderStatusPlaced
+			})).Return(nil).Maybe()
+
+			preorder, err := preorderService.Place(ctx, 1, "pink-hoody", models.BuyOptions{})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 500, preorder.Amount)
+				mockUserRepo.AssertExpectations(t)
+				mockPreorderRepo.AssertExpectations(t)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPreorderService_Cancel_ReleasesCoins(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockPreorderRepo := new(mocks.PreorderRepo)
+
+	preorderService := NewPreorderService(mockUserRepo, nil, nil, nil, nil, nil, mockPreorderRepo, sqlxDB)
+	ctx := context.Background()
+
+	preorder := &models.Preorder{ID: 4, UserID: 1, Amount: 500, Status: models.PreorderStatusPlaced}
+
+	mockPreorderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 4).Return(preorder, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 1, 500, models.CoinSourceRelease,
+		models.CoinHold{Type: models.CoinHoldPreorder, ID: 4}).Return(nil).Once()
+	mockPreorderRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(p *models.Preorder) bool {
+		return p.Status == models.PreorderStatusCancelled
+	})).Return(nil).Once()
+
+	cancelled, err := preorderService.Cancel(ctx, 1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PreorderStatusCancelled, cancelled.Status)
+
+	mockUserRepo.AssertExpectations(t)
+	mockPreorderRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPreorderService_Cancel_AlreadyFulfilled(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockPreorderRepo := new(mocks.PreorderRepo)
+	preorderService := NewPreorderService(nil, nil, nil, nil, nil, nil, mockPreorderRepo, sqlxDB)
+	ctx := context.Background()
+
+	preorder := &models.Preorder{ID: 4, UserID: 1, Amount: 500, Status: models.PreorderStatusFulfilled}
+	mockPreorderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 4).Return(preorder, nil).Once()
+
+	_, err := preorderService.Cancel(ctx, 1, 4)
+	assert.ErrorIs(t, err, ErrPreorderResolved)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestPreorderService_Capture_OldestFirstWhileStockLasts(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPreorderRepo := new(mocks.PreorderRepo)
+
+	preorderService := NewPreorderService(mockUserRepo, mockItemRepo, mockTransactionRepo, mockOrderRepo,
+		mockNotificationRepo, noPriceRules(), mockPreorderRepo, sqlxDB)
+	ctx := context.Background()
+
+	preorders := []models.Preorder{
+		{ID: 1, UserID: 1, ItemID: 10, VariantID: 12, Quantity: 1, OriginalPrice: 500, UnitPrice: 500,
+			Amount: 500, Status: models.PreorderStatusPlaced},
+		{ID: 2, UserID: 2, ItemID: 10, VariantID: 12, Quantity: 2, OriginalPrice: 500, UnitPrice: 500,
+			Amount: 1000, Status: models.PreorderStatusPlaced},
+	}
+
+	mockPreorderRepo.On("GetPlacedByVariantForUpdate", ctx, mock.Anything, 12).Return(preorders, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 12, 1).Return(true, nil).Once()
+	mockItemRepo.On("DecrementStock", ctx, mock.Anything, 12, 2).Return(false, nil).Once()
+	mockUserRepo.On("AddOrIncrementItemInventory", ctx, mock.Anything, 1, 10, 12, 1).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.Amount == 500 && tr.Type == models.TransactionTypePurchase
+	})).Return(nil).Once()
+	mockOrderRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(o *models.Order) bool {
+		return o.UserID == 1 && o.Total == 500
+	})).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Order).ID = 30
+	}).Return(nil).Once()
+	mockUserRepo.On("MoveHold", ctx, mock.Anything, 1, models.CoinHold{Type: models.CoinHoldPreorder, ID: 1},
+		models.CoinHold{Type: models.CoinHoldOrder, ID: 30}).Return(nil).Once()
+	mockPreorderRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(p *models.Preorder) bool {
+		return p.ID == 1 && p.Status == models.PreorderStatusFulfilled
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+	tx, err := sqlxDB.Beginx()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when beginning a transaction", err)
+	}
+
+	captured, err := preorderService.Capture(ctx, tx, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, captured)
+
+	mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockItemRepo.AssertExpectations(t)
+	mockPreorderRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"strings"
+)
+
+var (
+	ErrInvalidPromoCode       = errors.New("services: invalid promo code")
+	ErrPromoCodeNotFound      = errors.New("services: promo code not found")
+	ErrPromoCodeInactive      = errors.New("services: promo code is not active")
+	ErrPromoCodeExhausted     = errors.New("services: promo code usage limit reached")
+	ErrPromoCodeUserLimit     = errors.New("services: promo code already used the maximum number of times")
+	ErrPromoCodeNotApplicable = errors.New("services: promo code does not apply to this item")
+)
+
+type PromoService interface {
+	Create(ctx context.Context, promo *models.PromoCode) error
+	GetAll(ctx context.Context) ([]models.PromoCode, error)
+}
+
+type promoService struct {
+	promoCodeRepo repository.PromoCodeRepo
+}
+
+func NewPromoService(promoCodeRepo repository.PromoCodeRepo) PromoService {
+	return &promoService{promoCodeRepo: promoCodeRepo}
+}
+
+func (s *promoService) Create(ctx context.Context, promo *models.PromoCode) error {
+	promo.Code = strings.TrimSpace(promo.Code)
+	if promo.Code == "" || promo.DiscountValue <= 0 {
+		return ErrInvalidPromoCode
+	}
+
+	switch promo.DiscountType {
+	case models.DiscountTypePercent:
+		if promo.DiscountValue > 100 {
+			return ErrInvalidPromoCode
+		}
+	case models.DiscountTypeFixed:
+	default:
+		return ErrInvalidPromoCode
+	}
+
+	if (promo.MaxUses != nil && *promo.MaxUses < 1) || (promo.PerUserLimit != nil && *promo.PerUserLimit < 1) {
+		return ErrInvalidPromoCode
+	}
+	if promo.StartsAt != nil && promo.EndsAt != nil && !promo.EndsAt.After(*promo.StartsAt) {
+		return ErrInvalidPromoCode
+	}
+
+	if promo.ItemIDs == nil {
+		promo.ItemIDs = []int64{}
+	}
+	if promo.Categories == nil {
+		promo.Categories = []string{}
+	}
+
+	if err := s.promoCodeRepo.Create(ctx, promo); err != nil {
+		return fmt.Errorf("services: failed to create promo code: %w", err)
+	}
+	return nil
+}
+
+func (s *promoService) GetAll(ctx context.Context) ([]models.PromoCode, error) {
+	promos, err := s.promoCodeRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get promo codes: %w", err)
+	}
+	return promos, nil
+}
@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRaffleNotFound        = errors.New("services: raffle not found")
+	ErrInvalidRaffle         = errors.New("services: invalid raffle")
+	ErrRaffleItemUnavailable = errors.New("services: prize item is out of stock")
+	ErrRaffleClosed          = errors.New("services: raffle is closed")
+	ErrInvalidTicketQuantity = errors.New("services: invalid ticket quantity")
+	ErrRaffleTicketLimit     = errors.New("services: raffle ticket limit reached")
+)
+
+type RaffleService interface {
+	Create(ctx context.Context, adminID int, raffle *models.Raffle, prizeItem string, opts models.BuyOptions) error
+	List(ctx context.Context) ([]models.Raffle, error)
+	BuyTickets(ctx context.Context, userID int, raffleID int, quantity int) (*models.RaffleTicket, error)
+	DrawClosed(ctx context.Context) (int, error)
+}
+
+type raffleService struct {
+	userRepo         repository.UserRepo
+	itemRepo         repository.ItemRepo
+	transactionRepo  repository.TransactionRepo
+	orderRepo        repository.OrderRepo
+	notificationRepo repository.NotificationRepo
+	raffleRepo       repository.RaffleRepo
+	db               *sqlx.DB
+}
+
+func NewRaffleService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	transactionRepo repository.TransactionRepo,
+	orderRepo repository.OrderRepo,
+	notificationRepo repository.NotificationRepo,
+	raffleRepo repository.RaffleRepo,
+	db *sqlx.DB,
+) RaffleService {
+	return &raffleService{
+		userRepo:         userRepo,
+		itemRepo:         itemRepo,
+		transactionRepo:  transactionRepo,
+		orderRepo:        orderRepo,
+		notificationRepo: notificationRepo,
+		raffleRepo:       raffleRepo,
+		db:               db,
+	}
+}
+
+// Create opens a raffle whose prize is either one unit of an item variant or
+// a number of coins. An item prize is taken out of stock until the draw. The
+// seed is generated here and only its hash is published until the draw.
+func (s *raffleService) Create(
+	ctx context.Context, adminID int, raffle *models.Raffle, prizeItem string, opts models.BuyOptions) (err error) {
+	raffle.Title = strings.TrimSpace(raffle.Title)
+	if raffle.Title == "" || raffle.TicketPrice < 1 || raffle.MaxTicketsPerUser < 0 || raffle.PrizeCoins < 0 ||
+		!raffle.ClosesAt.After(time.Now()) || (prizeItem == "") == (raffle.PrizeCoins == 0) {
+		return ErrInvalidRaffle
+	}
+
+	var variant *models.ItemVariant
+	if prizeItem != "" {
+		item, err := s.itemRepo.GetItemByName(ctx, prizeItem)
+		if err != nil {
+			return fmt.Errorf("services: failed to get item by name: %w", err)
+		}
+		if item == nil {
+			return ErrItemNotFound
+		}
+
+		variant, err = resolveVariant(ctx, s.itemRepo, item.ID, opts.Size, opts.Color)
+		if err != nil {
+			return err
+		}
+
+		raffle.PrizeItemID = &item.ID
+		raffle.PrizeVariantID = &variant.ID
+		raffle.PrizeItem = &item.Name
+		raffle.PrizeSize = &variant.Size
+		raffle.PrizeColor = &variant.Color
+	}
+
+	seed := make([]byte, 32)
+	if _, err := rand.Read(seed); err != nil {
+		return fmt.Errorf("services: failed to generate raffle seed: %w", err)
+	}
+
+	raffle.Seed = hex.EncodeToString(seed)
+	raffle.SeedHash = models.CommitSeed(raffle.Seed)
+	raffle.Status = models.RaffleStatusOpen
+	raffle.CreatedBy = &adminID
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	if variant != nil {
+		reserved, err := s.itemRepo.DecrementStock(ctx, tx, variant.ID, 1)
+		if err != nil {
+			return fmt.Errorf("services: failed to reserve stock: %w", err)
+		}
+		if !reserved {
+			return ErrRaffleItemUnavailable
+		}
+	}
+
+	if err := s.raffleRepo.Create(ctx, tx, raffle); err != nil {
+		return fmt.Errorf("services: failed to create raffle: %w", err)
+	}
+
+	raffle.Seed = ""
+
+	return nil
+}
+
+// List returns all raffles. Seeds of raffles that have not been drawn yet are
+// withheld.
+func (s *raffleService) List(ctx context.Context) ([]models.Raffle, error) {
+	raffles, err := s.raffleRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get raffles: %w", err)
+	}
+
+	for i := range raffles {
+		if raffles[i].Status != models.RaffleStatusDrawn {
+			raffles[i].Seed = ""
+		}
+	}
+
+	return raffles, nil
+}
+
+func (s *raffleService) BuyTickets(
+	ctx context.Context, userID int, raffleID int, quantity int) (ticket *models.RaffleTicket, err error) {
+	if quantity < 1 {
+		return nil, ErrInvalidTicketQuantity
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	raffle, err := s.raffleRepo.GetByIDForUpdate(ctx, tx, raffleID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock raffle: %w", err)
+	}
+	if raffle == nil {
+		return nil, ErrRaffleNotFound
+	}
+	if raffle.Status != models.RaffleStatusOpen || !time.Now().Before(raffle.ClosesAt) {
+		return nil, ErrRaffleClosed
+	}
+
+	if raffle.MaxTicketsPerUser > 0 {
+		owned, err := s.raffleRepo.CountUserTickets(ctx, tx, raffle.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to count tickets: %w", err)
+		}
+		if owned+quantity > raffle.MaxTicketsPerUser {
+			return nil, ErrRaffleTicketLimit
+		}
+	}
+
+	cost := raffle.TicketPrice * quantity
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by id: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("services: user not found")
+	}
+	if user.Balance < cost {
+		return nil, ErrInsufficientBalance
+	}
+
+	if err := s.userRepo.Debit(ctx, tx, userID, cost); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("services: failed to update balance: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   userID,
+		ReceiverID: -1,
+		Amount:     cost,
+		Type:       models.TransactionTypeRaffleTicket,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	ticket = &models.RaffleTicket{
+		RaffleID:      raffle.ID,
+		UserID:        userID,
+		Quantity:      quantity,
+		TransactionID: transaction.ID,
+	}
+
+	if err := s.raffleRepo.CreateTicket(ctx, tx, ticket); err != nil {
+		return nil, fmt.Errorf("services: failed to create ticket: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// DrawClosed draws every raffle past its close time, each in its own
+// transaction.
+func (s *raffleService) DrawClosed(ctx context.Context) (int, error) {
+	ids, err := s.raffleRepo.GetClosedIDs(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get closed raffles: %w", err)
+	}
+
+	drawn := 0
+	var errs []error
+	for _, id := range ids {
+		ok, err := s.draw(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			drawn++
+		}
+	}
+
+	return drawn, errors.Join(errs...)
+}
+
+// draw picks the winning ticket from the committed seed and hands out the
+// prize. A raffle without tickets is drawn without a winner and its item
+// prize goes back to stock.
+func (s *raffleService) draw(ctx context.Context, raffleID int) (drawn bool, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	raffle, err := s.raffleRepo.GetByIDForUpdate(ctx, tx, raffleID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to lock raffle: %w", err)
+	}
+	if raffle == nil || raffle.Status != models.RaffleStatusOpen || time.Now().Before(raffle.ClosesAt) {
+		return false, nil
+	}
+
+	raffle.Status = models.RaffleStatusDrawn
+
+	if raffle.TicketsSold == 0 {
+		if raffle.PrizeVariantID != nil {
+			if err := s.itemRepo.IncrementStock(ctx, tx, *raffle.PrizeVariantID, 1); err != nil {
+				return false, fmt.Errorf("services: failed to restock raffle prize: %w", err)
+			}
+		}
+		if err := s.raffleRepo.Draw(ctx, tx, raffle); err != nil {
+			return false, fmt.Errorf("services: failed to draw raffle: %w", err)
+		}
+		return true, nil
+	}
+
+	tickets, err := s.raffleRepo.GetTickets(ctx, tx, raffle.ID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to get tickets: %w", err)
+	}
+
+	number := models.DrawTicket(raffle.Seed, raffle.TicketsSold)
+	winnerID, ok := models.TicketHolder(tickets, number)
+	if !ok {
+		return false, fmt.Errorf("services: no holder for ticket %d of raffle %d", number, raffle.ID)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   -1,
+		ReceiverID: winnerID,
+		Amount:     raffle.PrizeCoins,
+		Type:       models.TransactionTypeRafflePrize,
+	}
+
+	if raffle.PrizeCoins > 0 {
+		if err := s.userRepo.Credit(ctx, tx, winnerID, raffle.PrizeCoins, models.CoinSourceRafflePrize); err != nil {
+			return false, fmt.Errorf("services: failed to pay raffle prize: %w", err)
+		}
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return false, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	prize := fmt.Sprintf("%d coins", raffle.PrizeCoins)
+	if raffle.PrizeVariantID != nil {
+		err = s.userRepo.AddOrIncrementItemInventory(ctx, tx, winnerID, *raffle.PrizeItemID, *raffle.PrizeVariantID, 1)
+		if err != nil {
+			return false, fmt.Errorf("services: failed to add to inventory: %w", err)
+		}
+
+		order := &models.Order{
+			UserID:        winnerID,
+			RecipientID:   winnerID,
+			ItemID:        *raffle.PrizeItemID,
+			VariantID:     *raffle.PrizeVariantID,
+			TransactionID: transaction.ID,
+			Quantity:      1,
+		}
+
+		if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+			return false, fmt.Errorf("services: failed to create order: %w", err)
+		}
+
+		prize = *raffle.PrizeItem
+	}
+
+	raffle.WinningTicket = &number
+	raffle.WinnerID = &winnerID
+
+	if err := s.raffleRepo.Draw(ctx, tx, raffle); err != nil {
+		return false, fmt.Errorf("services: failed to draw raffle: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  winnerID,
+		Type:    models.NotificationTypeRaffleWon,
+		Message: fmt.Sprintf("Your ticket #%d won %s in the raffle %q", number, prize, raffle.Title),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return false, fmt.Errorf("services: failed to notify winner: %w", err)
+	}
+
+	return true, nil
+}
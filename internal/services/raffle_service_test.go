@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRaffleService_BuyTickets(t *testing.T) {
+	tests := []struct {
+		name        string
+		raffle      *models.Raffle
+		owned       int
+		quantity    int
+		expectedErr error
+	}{
+		{
+			name:     "Within ticket cap",
+			raffle:   &models.Raffle{ID: 1, TicketPrice: 10, MaxTicketsPerUser: 5, Status: models.RaffleStatusOpen},
+			owned:    2,
+			quantity: 3,
+		},
+		{
+			name:        "Over ticket cap",
+			raffle:      &models.Raffle{ID: 1, TicketPrice: 10, MaxTicketsPerUser: 5, Status: models.RaffleStatusOpen},
+			owned:       4,
+			quantity:    2,
+			expectedErr: ErrRaffleTicketLimit,
+		},
+		{
+			name:        "Drawn raffle",
+			raffle:      &models.Raffle{ID: 1, TicketPrice: 10, Status: models.RaffleStatusDrawn},
+			quantity:    1,
+			expectedErr: ErrRaffleClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockTransactionRepo := new(mocks.TransactionRepo)
+			mockRaffleRepo := new(mocks.RaffleRepo)
+			raffleService := NewRaffleService(
+				mockUserRepo, nil, mockTransactionRepo, nil, nil, mockRaffleRepo, sqlxDB)
+			ctx := context.Background()
+
+			tt.raffle.ClosesAt = time.Now().Add(time.Hour)
+			cost := tt.raffle.TicketPrice * tt.quantity
+
+			mockRaffleRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(tt.raffle, nil).Once()
+			mockRaffleRepo.On("CountUserTickets", ctx, mock.Anything, 1, 7).Return(tt.owned, nil).Maybe()
+			mockUserRepo.On("GetByID", ctx, 7).Return(&models.User{ID: 7, Balance: 100}, nil).Maybe()
+			mockUserRepo.On("Debit", ctx, mock.Anything, 7, cost).Return(nil).Maybe()
+			mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+				return tr.SenderID == 7 && tr.ReceiverID == -1 && tr.Amount == cost &&
+					tr.Type == models.TransactionTypeRaffleTicket
+			})).Return(nil).Maybe()
+			mockRaffleRepo.On("CreateTicket", ctx, mock.Anything, mock.MatchedBy(func(rt *models.RaffleTicket) bool {
+				return rt.RaffleID == 1 && rt.UserID == 7 && rt.Quantity == tt.quantity
+			})).Return(nil).Maybe()
+
+			ticket, err := raffleService.BuyTickets(ctx, 7, 1, tt.quantity)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, ticket)
+				mockUserRepo.AssertNotCalled(t, "Debit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.quantity, ticket.Quantity)
+				mockRaffleRepo.AssertExpectations(t)
+				mockTransactionRepo.AssertExpectations(t)
+			}
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRaffleService_DrawClosed(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockRaffleRepo := new(mocks.RaffleRepo)
+	raffleService := NewRaffleService(mockUserRepo, mockItemRepo, mockTransactionRepo, nil,
+		mockNotificationRepo, mockRaffleRepo, sqlxDB)
+	ctx := context.Background()
+
+	closed := time.Now().Add(-time.Minute)
+	seed := "c0ffee"
+	coins := &models.Raffle{ID: 1, Title: "Conference", PrizeCoins: 500, Seed: seed,
+		SeedHash: models.CommitSeed(seed), Status: models.RaffleStatusOpen, TicketsSold: 6, ClosesAt: closed}
+	empty := &models.Raffle{ID: 2, Title: "Hoody", PrizeItemID: intPtr(3), PrizeVariantID: intPtr(5),
+		Status: models.RaffleStatusOpen, ClosesAt: closed}
+
+	tickets := []models.RaffleTicket{
+		{ID: 1, RaffleID: 1, UserID: 7, Quantity: 2},
+		{ID: 2, RaffleID: 1, UserID: 8, Quantity: 3},
+		{ID: 3, RaffleID: 1, UserID: 7, Quantity: 1},
+	}
+	number := models.DrawTicket(seed, 6)
+	winnerID, _ := models.TicketHolder(tickets, number)
+
+	mockRaffleRepo.On("GetClosedIDs", ctx, mock.Anything).Return([]int{1, 2}, nil).Once()
+
+	mockRaffleRepo.On("GetByIDForUpdate", ctx, mock.Anything, 1).Return(coins, nil).Once()
+	mockRaffleRepo.On("GetTickets", ctx, mock.Anything, 1).Return(tickets, nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, winnerID, 500, models.CoinSourceRafflePrize).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == -1 && tr.ReceiverID == winnerID && tr.Amount == 500 &&
+			tr.Type == models.TransactionTypeRafflePrize
+	})).Return(nil).Once()
+	mockRaffleRepo.On("Draw", ctx, mock.Anything, mock.MatchedBy(func(r *models.Raffle) bool {
+		return r.ID == 1 && r.Status == models.RaffleStatusDrawn && *r.WinningTicket == number &&
+			*r.WinnerID == winnerID
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == winnerID && n.Type == models.NotificationTypeRaffleWon
+	})).Return(nil).Once()
+
+	mockRaffleRepo.On("GetByIDForUpdate", ctx, mock.Anything, 2).Return(empty, nil).Once()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 5, 1).Return(nil).Once()
+	mockRaffleRepo.On("Draw", ctx, mock.Anything, mock.MatchedBy(func(r *models.Raffle) bool {
+		return r.ID == 2 && r.Status == models.RaffleStatusDrawn && r.WinnerID == nil
+	})).Return(nil).Once()
+
+	drawn, err := raffleService.DrawClosed(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, drawn)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockRaffleRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRaffleService_List_HidesUndrawnSeeds(t *testing.T) {
+	mockRaffleRepo := new(mocks.RaffleRepo)
+	raffleService := NewRaffleService(nil, nil, nil, nil, nil, mockRaffleRepo, nil)
+	ctx := context.Background()
+
+	mockRaffleRepo.On("GetAll", ctx).Return([]models.Raffle{
+		{ID: 1, Seed: "a", Status: models.RaffleStatusOpen},
+		{ID: 2, Seed: "b", Status: models.RaffleStatusDrawn},
+	}, nil).Once()
+
+	raffles, err := raffleService.List(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, raffles[0].Seed)
+	assert.Equal(t, "b", raffles[1].Seed)
+}
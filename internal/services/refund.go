@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+)
+
+// orderRefunder returns units of an order to stock and credits the buyer.
+// It is shared by approved returns and order cancellations.
+type orderRefunder struct {
+	userRepo        repository.UserRepo
+	itemRepo        repository.ItemRepo
+	orderRepo       repository.OrderRepo
+	transactionRepo repository.TransactionRepo
+	poolRepo        repository.PoolRepo
+}
+
+// refund takes quantity units out of the recipient's inventory, restocks them,
+// credits whoever paid and records refund transactions linked to the
+// payments. An order bought by a pool is refunded to the contributors, each
+// in proportion to what they put in. It returns the refunded amount and, for
+// an order with a single payer, the refund transaction.
+// The order must be locked by the caller.
+func (r orderRefunder) refund(
+	ctx context.Context, tx *sqlx.Tx, order *models.Order, quantity int) (int, *models.Transaction, error) {
+	removed, err := r.userRepo.RemoveFromInventory(ctx, tx, order.RecipientID, order.VariantID, quantity)
+	if err != nil {
+		return 0, nil, fmt.Errorf("services: failed to remove returned items: %w", err)
+	}
+	if !removed {
+		return 0, nil, ErrReturnedItemsNotOwned
+	}
+
+	if err := r.itemRepo.IncrementStock(ctx, tx, order.VariantID, quantity); err != nil {
+		return 0, nil, fmt.Errorf("services: failed to restock returned items: %w", err)
+	}
+
+	amount := order.RefundFor(quantity)
+
+	contributions, err := r.poolRepo.GetSpentContributionsByOrder(ctx, tx, order.ID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("services: failed to get pool contributions: %w", err)
+	}
+
+	var transaction *models.Transaction
+	if len(contributions) > 0 {
+		fullyRefunded := order.RefundedQuantity+quantity == order.Quantity
+		if err := r.refundContributions(ctx, tx, contributions, amount, fullyRefunded); err != nil {
+			return 0, nil, err
+		}
+	} else {
+		transaction, err = r.credit(ctx, tx, order.UserID, amount, &order.TransactionID, order.CoinHold())
+		if err != nil {
+			return 0, nil, err
+		}
+	}
+
+	if err := r.orderRepo.AddRefundedQuantity(ctx, tx, order.ID, quantity); err != nil {
+		return 0, nil, fmt.Errorf("services: failed to update order: %w", err)
+	}
+
+	return amount, transaction, nil
+}
+
+// refundContributions splits amount between the pool contributors in
+// proportion to their contributions. The rounding remainder goes to the last
+// contributor, so the shares add up to amount.
+func (r orderRefunder) refundContributions(
+	ctx context.Context, tx *sqlx.Tx, contributions []models.PoolContribution, amount int, fullyRefunded bool) error {
+	total := 0
+	for _, contribution := range contributions {
+		total += contribution.Amount
+	}
+
+	left := amount
+	for i := range contributions {
+		contribution := &contributions[i]
+
+		share := left
+		if i < len(contributions)-1 && total > 0 {
+			share = amount * contribution.Amount / total
+		}
+		left -= share
+
+		if share > 0 {
+			_, err := r.credit(ctx, tx, contribution.UserID, share, contribution.TransactionID, contribution.CoinHold())
+			if err != nil {
+				return err
+			}
+		}
+
+		if fullyRefunded {
+			contribution.Status = models.ContributionStatusRefunded
+			if err := r.poolRepo.ResolveContribution(ctx, tx, contribution); err != nil {
+				return fmt.Errorf("services: failed to resolve contribution: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// credit pays a refund from the store back into the lots the payment was
+// held from and records it against the payment.
+func (r orderRefunder) credit(ctx context.Context,
+	tx *sqlx.Tx, userID int, amount int, paymentID *int, hold models.CoinHold) (*models.Transaction, error) {
+	if err := r.userRepo.Release(ctx, tx, userID, amount, models.CoinSourceRefund, hold); err != nil {
+		return nil, fmt.Errorf("services: failed to credit refund: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:    -1,
+		ReceiverID:  userID,
+		Amount:      amount,
+		Type:        models.TransactionTypeRefund,
+		ReferenceID: paymentID,
+	}
+
+	if err := r.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("services: failed to create refund transaction: %w", err)
+	}
+	return transaction, nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrOrderNotFound         = errors.New("services: order not found")
+	ErrReturnNotFound        = errors.New("services: return not found")
+	ErrReturnWindowExpired   = errors.New("services: return window expired")
+	ErrInvalidReturnQuantity = errors.New("services: invalid return quantity")
+	ErrReturnAlreadyReviewed = errors.New("services: return already reviewed")
+	ErrReturnedItemsNotOwned = errors.New("services: returned items are no longer in inventory")
+	ErrGiftNotReturnable     = errors.New("services: gifts cannot be returned")
+)
+
+type ReturnService interface {
+	RequestReturn(ctx context.Context, userID int, orderID int, quantity int, reason string) (*models.Return, error)
+	GetPendingReturns(ctx context.Context) ([]models.Return, error)
+	Approve(ctx context.Context, reviewerID int, returnID int) (*models.Return, error)
+	Reject(ctx context.Context, reviewerID int, returnID int) (*models.Return, error)
+}
+
+type returnService struct {
+	userRepo        repository.UserRepo
+	itemRepo        repository.ItemRepo
+	orderRepo       repository.OrderRepo
+	returnRepo      repository.ReturnRepo
+	transactionRepo repository.TransactionRepo
+	db              *sqlx.DB
+	window          time.Duration
+	refunder        orderRefunder
+}
+
+func NewReturnService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	orderRepo repository.OrderRepo,
+	returnRepo repository.ReturnRepo,
+	transactionRepo repository.TransactionRepo,
+	poolRepo repository.PoolRepo,
+	db *sqlx.DB,
+	window time.Duration,
+) ReturnService {
+	return &returnService{
+		userRepo:        userRepo,
+		itemRepo:        itemRepo,
+		orderRepo:       orderRepo,
+		returnRepo:      returnRepo,
+		transactionRepo: transactionRepo,
+		db:              db,
+		window:          window,
+		refunder: orderRefunder{
+			userRepo:        userRepo,
+			itemRepo:        itemRepo,
+			orderRepo:       orderRepo,
+			transactionRepo: transactionRepo,
+			poolRepo:        poolRepo,
+		},
+	}
+}
+
+func (s *returnService) RequestReturn(
+	ctx context.Context, userID int, orderID int, quantity int, reason string) (*models.Return, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get order: %w", err)
+	}
+	if order == nil || order.UserID != userID {
+		return nil, ErrOrderNotFound
+	}
+
+	// The recipient holds a gift and the buyer paid for it, so neither can
+	// return it alone.
+	if order.IsGift() {
+		return nil, ErrGiftNotReturnable
+	}
+
+	if time.Since(order.CreatedAt) > s.window {
+		return nil, ErrReturnWindowExpired
+	}
+
+	pending, err := s.returnRepo.GetPendingQuantity(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get pending returns: %w", err)
+	}
+
+	returnable := order.Quantity - order.RefundedQuantity - pending
+	if quantity < 1 || quantity > returnable {
+		return nil, ErrInvalidReturnQuantity
+	}
+
+	ret := &models.Return{
+		OrderID:  orderID,
+		UserID:   userID,
+		Quantity: quantity,
+		Reason:   reason,
+		Status:   models.ReturnStatusRequested,
+	}
+
+	if err := s.returnRepo.Create(ctx, ret); err != nil {
+		return nil, fmt.Errorf("services: failed to create return: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (s *returnService) GetPendingReturns(ctx context.Context) ([]models.Return, error) {
+	returns, err := s.returnRepo.GetByStatus(ctx, models.ReturnStatusRequested)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get pending returns: %w", err)
+	}
+	return returns, nil
+}
+
+// Approve takes the returned units out of the user's inventory, credits the
+// refund and records a refund transaction linked to the original purchase.
+func (s *returnService) Approve(ctx context.Context, reviewerID int, returnID int) (ret *models.Return, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	ret, err = s.lockPendingReturn(ctx, tx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orderRepo.GetByIDForUpdate(ctx, tx, ret.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock order: %w", err)
+	}
+	if order == nil {
+		return nil, ErrOrderNotFound
+	}
+	if order.Quantity-order.RefundedQuantity < ret.Quantity {
+		return nil, ErrInvalidReturnQuantity
+	}
+
+	amount, transaction, err := s.refunder.refund(ctx, tx, order, ret.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Status = models.ReturnStatusApproved
+	ret.ReviewerID = &reviewerID
+	ret.RefundAmount = amount
+	if transaction != nil {
+		ret.TransactionID = &transaction.ID
+	}
+
+	if err := s.returnRepo.Review(ctx, tx, ret); err != nil {
+		return nil, fmt.Errorf("services: failed to update return: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (s *returnService) Reject(ctx context.Context, reviewerID int, returnID int) (ret *models.Return, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	ret, err = s.lockPendingReturn(ctx, tx, returnID)
+	if err != nil {
+		return nil, err
+	}
+
+	ret.Status = models.ReturnStatusRejected
+	ret.ReviewerID = &reviewerID
+
+	if err := s.returnRepo.Review(ctx, tx, ret); err != nil {
+		return nil, fmt.Errorf("services: failed to update return: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (s *returnService) lockPendingReturn(ctx context.Context, tx *sqlx.Tx, returnID int) (*models.Return, error) {
+	ret, err := s.returnRepo.GetByIDForUpdate(ctx, tx, returnID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock return: %w", err)
+	}
+	if ret == nil {
+		return nil, ErrReturnNotFound
+	}
+	if ret.Status != models.ReturnStatusRequested {
+		return nil, ErrReturnAlreadyReviewed
+	}
+	return ret, nil
+}
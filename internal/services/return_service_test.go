@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReturnService_RequestReturn(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       *models.Order
+		pending     int
+		quantity    int
+		expectedErr error
+	}{
+		{
+			name:     "Partial return of multi-unit order",
+			order:    &models.Order{ID: 1, UserID: 1, RecipientID: 1, Quantity: 3, RefundedQuantity: 1, CreatedAt: time.Now()},
+			quantity: 2,
+		},
+		{
+			name:        "Order of another user",
+			order:       &models.Order{ID: 1, UserID: 2, RecipientID: 2, Quantity: 1, CreatedAt: time.Now()},
+			quantity:    1,
+			expectedErr: ErrOrderNotFound,
+		},
+		{
+			name:        "Gift order",
+			order:       &models.Order{ID: 1, UserID: 1, RecipientID: 2, Quantity: 1, CreatedAt: time.Now()},
+			quantity:    1,
+			expectedErr: ErrGiftNotReturnable,
+		},
+		{
+			name:        "Return window expired",
+			order:       &models.Order{ID: 1, UserID: 1, RecipientID: 1, Quantity: 1, CreatedAt: time.Now().Add(-30 * 24 * time.Hour)},
+			quantity:    1,
+			expectedErr: ErrReturnWindowExpired,
+		},
+		{
+			name:        "Quantity exceeds returnable units",
+			order:       &models.Order{ID: 1, UserID: 1, RecipientID: 1, Quantity: 3, RefundedQuantity: 1, CreatedAt: time.Now()},
+			pending:     1,
+			quantity:    2,
+			expectedErr: ErrInvalidReturnQuantity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrderRepo := new(mocks.OrderRepo)
+			mockReturnRepo := new(mocks.ReturnRepo)
+			returnService := NewReturnService(nil, nil, mockOrderRepo, mockReturnRepo, nil, nil, nil, 14*24*time.Hour)
+			ctx := context.Background()
+
+			mockOrderRepo.On("GetByID", ctx, 1).Return(tt.order, nil).Once()
+			mockReturnRepo.On("GetPendingQuantity", ctx, 1).Return(tt.pending, nil).Maybe()
+			mockReturnRepo.On("Create", ctx, mock.MatchedBy(func(r *models.Return) bool {
+				return r.OrderID == 1 && r.Quantity == tt.quantity && r.Status == models.ReturnStatusRequested
+			})).Return(nil).Maybe()
+
+			ret, err := returnService.RequestReturn(ctx, 1, 1, tt.quantity, "wrong size")
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				assert.Nil(t, ret)
+				mockReturnRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.quantity, ret.Quantity)
+			}
+		})
+	}
+}
+
+func TestReturnService_Approve(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockReturnRepo := new(mocks.ReturnRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+
+	returnService := NewReturnService(
+		mockUserRepo, mockItemRepo, mockOrderRepo, mockReturnRepo, mockTransactionRepo, mockPoolRepo, sqlxDB,
+		time.Hour)
+	ctx := context.Background()
+
+	ret := &models.Return{ID: 7, OrderID: 3, UserID: 1, Quantity: 2, Status: models.ReturnStatusRequested}
+	order := &models.Order{ID: 3, UserID: 1, RecipientID: 1, VariantID: 5, TransactionID: 42, Quantity: 3, UnitPrice: 20, Total: 60}
+
+	mockReturnRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(ret, nil).Once()
+	mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(order, nil).Once()
+	mockUserRepo.On("RemoveFromInventory", ctx, mock.Anything, 1, 5, 2).Return(true, nil).Once()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 5, 2).Return(nil).Once()
+	mockPoolRepo.On("GetSpentContributionsByOrder", ctx, mock.Anything, 3).Return(nil, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 1, 40, models.CoinSourceRefund,
+		models.CoinHold{Type: models.CoinHoldOrder, ID: 3}).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == -1 && tr.ReceiverID == 1 && tr.Amount == 40 &&
+			tr.Type == models.TransactionTypeRefund && tr.ReferenceID != nil && *tr.ReferenceID == 42
+	})).Return(nil).Once()
+	mockOrderRepo.On("AddRefundedQuantity", ctx, mock.Anything, 3, 2).Return(nil).Once()
+	mockReturnRepo.On("Review", ctx, mock.Anything, mock.MatchedBy(func(r *models.Return) bool {
+		return r.Status == models.ReturnStatusApproved && r.RefundAmount == 40 && *r.ReviewerID == 9
+	})).Return(nil).Once()
+
+	approved, err := returnService.Approve(ctx, 9, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 40, approved.RefundAmount)
+
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockReturnRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestReturnService_Approve_PoolOrder(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockItemRepo := new(mocks.ItemRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockReturnRepo := new(mocks.ReturnRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockPoolRepo := new(mocks.PoolRepo)
+
+	returnService := NewReturnService(
+		mockUserRepo, mockItemRepo, mockOrderRepo, mockReturnRepo, mockTransactionRepo, mockPoolRepo, sqlxDB,
+		time.Hour)
+	ctx := context.Background()
+
+	ret := &models.Return{ID: 7, OrderID: 3, UserID: 1, Quantity: 1, Status: models.ReturnStatusRequested}
+	order := &models.Order{ID: 3, UserID: 1, RecipientID: 1, VariantID: 5, TransactionID: 42, Quantity: 1, UnitPrice: 500, Total: 500}
+	contributions := []models.PoolContribution{
+		{ID: 11, PoolID: 4, UserID: 1, Amount: 300, Status: models.ContributionStatusSpent, TransactionID: intPtr(51)},
+		{ID: 12, PoolID: 4, UserID: 2, Amount: 200, Status: models.ContributionStatusSpent, TransactionID: intPtr(52)},
+	}
+
+	mockReturnRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(ret, nil).Once()
+	mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(order, nil).Once()
+	mockUserRepo.On("RemoveFromInventory", ctx, mock.Anything, 1, 5, 1).Return(true, nil).Once()
+	mockItemRepo.On("IncrementStock", ctx, mock.Anything, 5, 1).Return(nil).Once()
+	mockPoolRepo.On("GetSpentContributionsByOrder", ctx, mock.Anything, 3).Return(contributions, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 1, 300, models.CoinSourceRefund,
+		models.CoinHold{Type: models.CoinHoldPoolContribution, ID: 11}).Return(nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 2, 200, models.CoinSourceRefund,
+		models.CoinHold{Type: models.CoinHoldPoolContribution, ID: 12}).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.ReceiverID == 1 && tr.Amount == 300 && tr.ReferenceID != nil && *tr.ReferenceID == 51
+	})).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.ReceiverID == 2 && tr.Amount == 200 && tr.ReferenceID != nil && *tr.ReferenceID == 52
+	})).Return(nil).Once()
+	mockPoolRepo.On("ResolveContribution", ctx, mock.Anything, mock.MatchedBy(func(c *models.PoolContribution) bool {
+		return c.Status == models.ContributionStatusRefunded
+	})).Return(nil).Twice()
+	mockOrderRepo.On("AddRefundedQuantity", ctx, mock.Anything, 3, 1).Return(nil).Once()
+	mockReturnRepo.On("Review", ctx, mock.Anything, mock.MatchedBy(func(r *models.Return) bool {
+		return r.Status == models.ReturnStatusApproved && r.RefundAmount == 500 && r.TransactionID == nil
+	})).Return(nil).Once()
+
+	approved, err := returnService.Approve(ctx, 9, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 500, approved.RefundAmount)
+
+	mockUserRepo.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, 1, 500, mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	mockItemRepo.AssertExpectations(t)
+	mockOrderRepo.AssertExpectations(t)
+	mockReturnRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockPoolRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestReturnService_Approve_ItemsNotOwned(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockOrderRepo := new(mocks.OrderRepo)
+	mockReturnRepo := new(mocks.ReturnRepo)
+
+	returnService := NewReturnService(mockUserRepo, nil, mockOrderRepo, mockReturnRepo, nil, nil, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	ret := &models.Return{ID: 7, OrderID: 3, UserID: 1, Quantity: 1, Status: models.ReturnStatusRequested}
+	order := &models.Order{ID: 3, UserID: 1, RecipientID: 1, VariantID: 5, Quantity: 1, UnitPrice: 20}
+
+	mockReturnRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(ret, nil).Once()
+	mockOrderRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(order, nil).Once()
+	mockUserRepo.On("RemoveFromInventory", ctx, mock.Anything, 1, 5, 1).Return(false, nil).Once()
+
+	_, err := returnService.Approve(ctx, 9, 7)
+	assert.ErrorIs(t, err, ErrReturnedItemsNotOwned)
+
+	mockUserRepo.AssertNotCalled(t, "Debit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestReturnService_Reject_AlreadyReviewed(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockReturnRepo := new(mocks.ReturnRepo)
+	returnService := NewReturnService(nil, nil, nil, mockReturnRepo, nil, nil, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	ret := &models.Return{ID: 7, Status: models.ReturnStatusApproved}
+	mockReturnRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(ret, nil).Once()
+
+	_, err := returnService.Reject(ctx, 9, 7)
+	assert.ErrorIs(t, err, ErrReturnAlreadyReviewed)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
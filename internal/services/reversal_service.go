@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidReversal           = errors.New("services: invalid reversal")
+	ErrTransactionNotFound       = errors.New("services: transaction not found")
+	ErrNotReversible             = errors.New("services: only coin transfers can be reversed")
+	ErrAlreadyReversed           = errors.New("services: transaction is already reversed")
+	ErrReversalPending           = errors.New("services: transaction already has a reversal waiting for approval")
+	ErrReversalInsufficientFunds = errors.New("services: receiver has already spent the coins")
+	ErrReversalNotFound          = errors.New("services: reversal not found")
+	ErrReversalResolved          = errors.New("services: reversal is no longer pending")
+	ErrReversalSelfApproval      = errors.New("services: reversal must be approved by another admin")
+)
+
+type ReversalService interface {
+	Reverse(ctx context.Context, adminID int, transactionID int, reason string,
+		allowNegative bool) (*models.Reversal, error)
+	Approve(ctx context.Context, adminID int, reversalID int) (*models.Reversal, error)
+	Reject(ctx context.Context, adminID int, reversalID int) (*models.Reversal, error)
+	List(ctx context.Context) ([]models.Reversal, error)
+}
+
+type reversalService struct {
+	userRepo         repository.UserRepo
+	transactionRepo  repository.TransactionRepo
+	notificationRepo repository.NotificationRepo
+	reversalRepo     repository.ReversalRepo
+	auditService     AuditService
+	db               *sqlx.DB
+}
+
+func NewReversalService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	reversalRepo repository.ReversalRepo,
+	auditService AuditService,
+	db *sqlx.DB,
+) ReversalService {
+	return &reversalService{
+		userRepo:         userRepo,
+		transactionRepo:  transactionRepo,
+		notificationRepo: notificationRepo,
+		reversalRepo:     reversalRepo,
+		auditService:     auditService,
+		db:               db,
+	}
+}
+
+// Reverse moves the coins of a transfer back to the sender with a
+// compensating transaction. When the receiver no longer has the coins the
+// reversal fails, unless allowNegative is set: then it waits for another
+// admin to approve taking the receiver's balance below zero.
+func (s *reversalService) Reverse(ctx context.Context, adminID int, transactionID int, reason string,
+	allowNegative bool) (reversal *models.Reversal, err error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrInvalidReversal
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	transaction, err := s.lockReversible(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := s.reversalRepo.HasPending(ctx, tx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to check pending reversals: %w", err)
+	}
+	if pending {
+		return nil, ErrReversalPending
+	}
+
+	reversal = &models.Reversal{
+		TransactionID: transactionID,
+		Reason:        reason,
+		AllowNegative: allowNegative,
+		RequestedBy:   &adminID,
+		Amount:        transaction.Amount,
+	}
+
+	if reversal.FromUser, err = s.userRepo.GetUsernameByID(ctx, transaction.SenderID); err != nil {
+		return nil, fmt.Errorf("services: failed to get sender username: %w", err)
+	}
+	if reversal.ToUser, err = s.userRepo.GetUsernameByID(ctx, transaction.ReceiverID); err != nil {
+		return nil, fmt.Errorf("services: failed to get receiver username: %w", err)
+	}
+
+	balance, err := s.reversalRepo.LockBalance(ctx, tx, transaction.ReceiverID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock receiver balance: %w", err)
+	}
+
+	if balance < transaction.Amount {
+		if !allowNegative {
+			return nil, ErrReversalInsufficientFunds
+		}
+		reversal.Status = models.ReversalStatusPending
+	} else if err := s.apply(ctx, tx, transaction, reversal); err != nil {
+		return nil, err
+	}
+
+	if err := s.reversalRepo.Create(ctx, tx, reversal); err != nil {
+		return nil, fmt.Errorf("services: failed to create reversal: %w", err)
+	}
+
+	action := models.AuditActionReversalCompleted
+	if reversal.Status == models.ReversalStatusPending {
+		action = models.AuditActionReversalRequested
+	}
+	if err := s.audit(ctx, tx, adminID, action, reversal, ""); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// Approve carries out a pending reversal, even if the receiver's balance
+// goes below zero. The admin who asked for the reversal cannot approve it.
+func (s *reversalService) Approve(
+	ctx context.Context, adminID int, reversalID int) (reversal *models.Reversal, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	reversal, err = s.lockPending(ctx, tx, reversalID)
+	if err != nil {
+		return nil, err
+	}
+	if reversal.RequestedBy != nil && *reversal.RequestedBy == adminID {
+		return nil, ErrReversalSelfApproval
+	}
+
+	transaction, err := s.lockReversible(ctx, tx, reversal.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.reversalRepo.LockBalance(ctx, tx, transaction.ReceiverID); err != nil {
+		return nil, fmt.Errorf("services: failed to lock receiver balance: %w", err)
+	}
+
+	if err := s.apply(ctx, tx, transaction, reversal); err != nil {
+		return nil, err
+	}
+
+	reversal.ApprovedBy = &adminID
+
+	if err := s.reversalRepo.Resolve(ctx, tx, reversal); err != nil {
+		return nil, fmt.Errorf("services: failed to resolve reversal: %w", err)
+	}
+
+	if err := s.audit(ctx, tx, adminID, models.AuditActionReversalCompleted, reversal,
+		models.ReversalStatusPending); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+func (s *reversalService) Reject(
+	ctx context.Context, adminID int, reversalID int) (reversal *models.Reversal, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	reversal, err = s.lockPending(ctx, tx, reversalID)
+	if err != nil {
+		return nil, err
+	}
+
+	reversal.Status = models.ReversalStatusRejected
+	reversal.ApprovedBy = &adminID
+
+	if err := s.reversalRepo.Resolve(ctx, tx, reversal); err != nil {
+		return nil, fmt.Errorf("services: failed to resolve reversal: %w", err)
+	}
+
+	if err := s.audit(ctx, tx, adminID, models.AuditActionReversalRejected, reversal,
+		models.ReversalStatusPending); err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+func (s *reversalService) List(ctx context.Context) ([]models.Reversal, error) {
+	reversals, err := s.reversalRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get reversals: %w", err)
+	}
+	if reversals == nil {
+		reversals = []models.Reversal{}
+	}
+	return reversals, nil
+}
+
+// audit records what happened to the reversal of a transfer. An empty
+// previous status means the reversal was just created.
+func (s *reversalService) audit(ctx context.Context, tx *sqlx.Tx, adminID int, action string,
+	reversal *models.Reversal, previousStatus string) error {
+	entry := &models.AuditEntry{
+		Action:     action,
+		ActorID:    &adminID,
+		TargetType: models.AuditTargetTransaction,
+		TargetID:   strconv.Itoa(reversal.TransactionID),
+		After: models.AuditState(map[string]any{
+			"reversalId":            reversal.ID,
+			"status":                reversal.Status,
+			"amount":                reversal.Amount,
+			"reason":                reversal.Reason,
+			"allowNegative":         reversal.AllowNegative,
+			"reversalTransactionId": reversal.ReversalTransactionID,
+		}),
+	}
+	if previousStatus != "" {
+		entry.Before = models.AuditState(map[string]string{"status": previousStatus})
+	}
+
+	if err := s.auditService.Record(ctx, tx, entry); err != nil {
+		return fmt.Errorf("services: failed to audit reversal: %w", err)
+	}
+	return nil
+}
+
+// lockReversible locks a transfer that was not reversed yet.
+func (s *reversalService) lockReversible(
+	ctx context.Context, tx *sqlx.Tx, transactionID int) (*models.Transaction, error) {
+	transaction, err := s.reversalRepo.GetTransactionForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock transaction: %w", err)
+	}
+	if transaction == nil {
+		return nil, ErrTransactionNotFound
+	}
+	if transaction.Type != models.TransactionTypeTransfer {
+		return nil, ErrNotReversible
+	}
+	if transaction.ReversedBy != nil {
+		return nil, ErrAlreadyReversed
+	}
+	return transaction, nil
+}
+
+func (s *reversalService) lockPending(ctx context.Context, tx *sqlx.Tx, reversalID int) (*models.Reversal, error) {
+	reversal, err := s.reversalRepo.GetByIDForUpdate(ctx, tx, reversalID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock reversal: %w", err)
+	}
+	if reversal == nil {
+		return nil, ErrReversalNotFound
+	}
+	if reversal.Status != models.ReversalStatusPending {
+		return nil, ErrReversalResolved
+	}
+	return reversal, nil
+}
+
+// apply moves the coins back and records the compensating transaction,
+// linked to the transfer it undoes. Only a reversal that allows it may take
+// the receiver's balance below zero.
+func (s *reversalService) apply(
+	ctx context.Context, tx *sqlx.Tx, transaction *models.Transaction, reversal *models.Reversal) error {
+	debit := s.userRepo.Debit
+	if reversal.AllowNegative {
+		debit = s.userRepo.Overdraw
+	}
+	if err := debit(ctx, tx, transaction.ReceiverID, transaction.Amount); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return ErrReversalInsufficientFunds
+		}
+		return fmt.Errorf("services: failed to update balance of receiver: %w", err)
+	}
+
+	if err := s.userRepo.Credit(ctx, tx, transaction.SenderID, transaction.Amount,
+		models.CoinSourceReversal); err != nil {
+		return fmt.Errorf("services: failed to update balance of sender: %w", err)
+	}
+
+	compensating := &models.Transaction{
+		SenderID:   transaction.ReceiverID,
+		ReceiverID: transaction.SenderID,
+		Amount:     transaction.Amount,
+		Type:       models.TransactionTypeReversal,
+		ReversalOf: &transaction.ID,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, compensating); err != nil {
+		return fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	reversal.Status = models.ReversalStatusCompleted
+	reversal.ReversalTransactionID = &compensating.ID
+
+	notifications := []*models.Notification{
+		{
+			UserID: transaction.SenderID,
+			Type:   models.NotificationTypeTransferReversed,
+			Message: fmt.Sprintf("your transfer of %d coins to %s was reversed and the coins returned: %s",
+				transaction.Amount, reversal.ToUser, reversal.Reason),
+		},
+		{
+			UserID: transaction.ReceiverID,
+			Type:   models.NotificationTypeTransferReversed,
+			Message: fmt.Sprintf("the %d coins %s sent you were reversed: %s",
+				transaction.Amount, reversal.FromUser, reversal.Reason),
+		},
+	}
+
+	for _, notification := range notifications {
+		if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+			return fmt.Errorf("services: failed to notify user %d: %w", notification.UserID, err)
+		}
+	}
+	return nil
+}
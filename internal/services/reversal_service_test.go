@@ -0,0 +1,239 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReversalService_Reverse(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockReversalRepo := new(mocks.ReversalRepo)
+	mockAuditService := new(mocks.AuditService)
+	reversalService := NewReversalService(mockUserRepo, mockTransactionRepo, mockNotificationRepo,
+		mockReversalRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	transaction := &models.Transaction{ID: 5, SenderID: 1, ReceiverID: 2, Amount: 500,
+		Type: models.TransactionTypeTransfer}
+
+	mockReversalRepo.On("GetTransactionForUpdate", ctx, mock.Anything, 5).Return(transaction, nil).Once()
+	mockReversalRepo.On("HasPending", ctx, mock.Anything, 5).Return(false, nil).Once()
+	mockUserRepo.On("GetUsernameByID", ctx, 1).Return("alice", nil).Once()
+	mockUserRepo.On("GetUsernameByID", ctx, 2).Return("bob", nil).Once()
+	mockReversalRepo.On("LockBalance", ctx, mock.Anything, 2).Return(600, nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 2, 500).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 1, 500, models.CoinSourceReversal).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 2 && tr.ReceiverID == 1 && tr.Amount == 500 &&
+			tr.Type == models.TransactionTypeReversal && *tr.ReversalOf == 5
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.Transaction).ID = 6
+	}).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.Type == models.NotificationTypeTransferReversed
+	})).Return(nil).Twice()
+	mockReversalRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(r *models.Reversal) bool {
+		return r.TransactionID == 5 && r.Status == models.ReversalStatusCompleted &&
+			*r.ReversalTransactionID == 6 && *r.RequestedBy == 9
+	})).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionReversalCompleted && *e.ActorID == 9 && e.TargetID == "5"
+	})).Return(nil).Once()
+
+	reversal, err := reversalService.Reverse(ctx, 9, 5, "sent to the wrong colleague", false)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReversalStatusCompleted, reversal.Status)
+	assert.Equal(t, "bob", reversal.ToUser)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	mockReversalRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestReversalService_Reverse_CoinsSpent(t *testing.T) {
+	tests := []struct {
+		name          string
+		allowNegative bool
+		expectedErr   error
+	}{
+		{name: "Fails without override", expectedErr: ErrReversalInsufficientFunds},
+		{name: "Waits for approval with override", allowNegative: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			if tt.expectedErr != nil {
+				sqlMock.ExpectRollback()
+			} else {
+				sqlMock.ExpectCommit()
+			}
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockReversalRepo := new(mocks.ReversalRepo)
+			mockAuditService := new(mocks.AuditService)
+			reversalService := NewReversalService(mockUserRepo, nil, nil, mockReversalRepo, mockAuditService, sqlxDB)
+			ctx := context.Background()
+
+			transaction := &models.Transaction{ID: 5, SenderID: 1, ReceiverID: 2, Amount: 500,
+				Type: models.TransactionTypeTransfer}
+
+			mockReversalRepo.On("GetTransactionForUpdate", ctx, mock.Anything, 5).Return(transaction, nil).Once()
+			mockReversalRepo.On("HasPending", ctx, mock.Anything, 5).Return(false, nil).Once()
+			mockUserRepo.On("GetUsernameByID", ctx, mock.Anything).Return("user", nil).Twice()
+			mockReversalRepo.On("LockBalance", ctx, mock.Anything, 2).Return(100, nil).Once()
+			mockReversalRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(r *models.Reversal) bool {
+				return r.Status == models.ReversalStatusPending && r.AllowNegative
+			})).Return(nil).Maybe()
+			mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+				return e.Action == models.AuditActionReversalRequested
+			})).Return(nil).Maybe()
+
+			reversal, err := reversalService.Reverse(ctx, 9, 5, "wrong colleague", tt.allowNegative)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				mockReversalRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, models.ReversalStatusPending, reversal.Status)
+				mockAuditService.AssertExpectations(t)
+				mockReversalRepo.AssertExpectations(t)
+			}
+
+			mockUserRepo.AssertNotCalled(t, "Debit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReversalService_Reverse_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		transaction *models.Transaction
+		expectedErr error
+	}{
+		{name: "Transaction not found", expectedErr: ErrTransactionNotFound},
+		{
+			name:        "Purchase",
+			transaction: &models.Transaction{ID: 5, SenderID: 1, ReceiverID: -1, Type: models.TransactionTypePurchase},
+			expectedErr: ErrNotReversible,
+		},
+		{
+			name: "Already reversed",
+			transaction: &models.Transaction{ID: 5, SenderID: 1, ReceiverID: 2, Type: models.TransactionTypeTransfer,
+				ReversedBy: intPtr(6)},
+			expectedErr: ErrAlreadyReversed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectRollback()
+
+			mockReversalRepo := new(mocks.ReversalRepo)
+			reversalService := NewReversalService(nil, nil, nil, mockReversalRepo, nil, sqlxDB)
+			ctx := context.Background()
+
+			mockReversalRepo.On("GetTransactionForUpdate", ctx, mock.Anything, 5).Return(tt.transaction, nil).Once()
+
+			_, err := reversalService.Reverse(ctx, 9, 5, "wrong colleague", false)
+			assert.ErrorIs(t, err, tt.expectedErr)
+
+			mockReversalRepo.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReversalService_Approve(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockReversalRepo := new(mocks.ReversalRepo)
+	mockAuditService := new(mocks.AuditService)
+	reversalService := NewReversalService(mockUserRepo, mockTransactionRepo, mockNotificationRepo,
+		mockReversalRepo, mockAuditService, sqlxDB)
+	ctx := context.Background()
+
+	reversal := &models.Reversal{ID: 3, TransactionID: 5, Reason: "wrong colleague", AllowNegative: true,
+		Status: models.ReversalStatusPending, RequestedBy: intPtr(9), Amount: 500}
+	transaction := &models.Transaction{ID: 5, SenderID: 1, ReceiverID: 2, Amount: 500,
+		Type: models.TransactionTypeTransfer}
+
+	mockReversalRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(reversal, nil).Once()
+	mockReversalRepo.On("GetTransactionForUpdate", ctx, mock.Anything, 5).Return(transaction, nil).Once()
+	mockReversalRepo.On("LockBalance", ctx, mock.Anything, 2).Return(100, nil).Once()
+	// The approved reversal takes the receiver's balance below zero.
+	mockUserRepo.On("Overdraw", ctx, mock.Anything, 2, 500).Return(nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 1, 500, models.CoinSourceReversal).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Twice()
+	mockReversalRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(r *models.Reversal) bool {
+		return r.Status == models.ReversalStatusCompleted && *r.ApprovedBy == 8
+	})).Return(nil).Once()
+	mockAuditService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(e *models.AuditEntry) bool {
+		return e.Action == models.AuditActionReversalCompleted && *e.ActorID == 8 &&
+			string(e.Before) == `{"status":"pending"}`
+	})).Return(nil).Once()
+
+	approved, err := reversalService.Approve(ctx, 8, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReversalStatusCompleted, approved.Status)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockAuditService.AssertExpectations(t)
+	mockReversalRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestReversalService_Approve_SelfApproval(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockReversalRepo := new(mocks.ReversalRepo)
+	reversalService := NewReversalService(nil, nil, nil, mockReversalRepo, nil, sqlxDB)
+	ctx := context.Background()
+
+	mockReversalRepo.On("GetByIDForUpdate", ctx, mock.Anything, 3).Return(&models.Reversal{ID: 3,
+		TransactionID: 5, Status: models.ReversalStatusPending, RequestedBy: intPtr(9)}, nil).Once()
+
+	_, err := reversalService.Approve(ctx, 9, 3)
+	assert.ErrorIs(t, err, ErrReversalSelfApproval)
+
+	mockReversalRepo.AssertNotCalled(t, "GetTransactionForUpdate", mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
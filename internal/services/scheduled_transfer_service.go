@@ -0,0 +1,339 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/cron"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrInvalidSchedule  = errors.New("services: invalid scheduled transfer")
+	ErrScheduleNotFound = errors.New("services: scheduled transfer not found")
+)
+
+const (
+	// maxScheduleFailures is the number of failed runs in a row after which a
+	// recurring transfer is paused.
+	maxScheduleFailures = 3
+	// maxCatchUpRuns caps how many missed runs CatchUpAll makes at once.
+	maxCatchUpRuns = 12
+	// catchUpGrace is how late a run may be before CatchUpSkip drops it.
+	catchUpGrace = time.Hour
+)
+
+type ScheduledTransferService interface {
+	Create(ctx context.Context, senderID int, schedule *models.ScheduledTransfer, toUser string) error
+	List(ctx context.Context, senderID int) ([]models.ScheduledTransfer, error)
+	GetRuns(ctx context.Context, senderID int, scheduleID int) ([]models.ScheduledTransferRun, error)
+	Cancel(ctx context.Context, senderID int, scheduleID int) error
+	RunDue(ctx context.Context) (int, error)
+}
+
+type scheduledTransferService struct {
+	userRepo              repository.UserRepo
+	notificationRepo      repository.NotificationRepo
+	scheduledTransferRepo repository.ScheduledTransferRepo
+	coinService           CoinService
+	db                    *sqlx.DB
+}
+
+func NewScheduledTransferService(
+	userRepo repository.UserRepo,
+	notificationRepo repository.NotificationRepo,
+	scheduledTransferRepo repository.ScheduledTransferRepo,
+	coinService CoinService,
+	db *sqlx.DB,
+) ScheduledTransferService {
+	return &scheduledTransferService{
+		userRepo:              userRepo,
+		notificationRepo:      notificationRepo,
+		scheduledTransferRepo: scheduledTransferRepo,
+		coinService:           coinService,
+		db:                    db,
+	}
+}
+
+// Create schedules a transfer. A one-off transfer sets NextRunAt, a
+// recurring one sets Cron and gets its first run computed here.
+func (s *scheduledTransferService) Create(
+	ctx context.Context, senderID int, schedule *models.ScheduledTransfer, toUser string) error {
+	if schedule.CatchUp == "" {
+		schedule.CatchUp = models.CatchUpLatest
+	}
+	if schedule.Amount < 1 || !models.IsValidCatchUp(schedule.CatchUp) ||
+		(schedule.Cron == nil) == (schedule.NextRunAt == nil) {
+		return ErrInvalidSchedule
+	}
+
+	now := time.Now().UTC()
+	if schedule.Cron != nil {
+		expr, err := cron.Parse(*schedule.Cron)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidSchedule, err)
+		}
+		next := expr.Next(now)
+		if next.IsZero() {
+			return ErrInvalidSchedule
+		}
+		schedule.NextRunAt = &next
+	} else {
+		runAt := schedule.NextRunAt.UTC()
+		if !runAt.After(now) {
+			return ErrInvalidSchedule
+		}
+		schedule.NextRunAt = &runAt
+	}
+
+	receiver, err := s.userRepo.GetByUsername(ctx, toUser)
+	if err != nil {
+		return fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if receiver == nil {
+		return ErrUserNotFound
+	}
+	if receiver.ID == senderID {
+		return ErrInvalidSchedule
+	}
+
+	schedule.SenderID = senderID
+	schedule.ReceiverID = receiver.ID
+	schedule.Receiver = receiver.Username
+	schedule.Status = models.ScheduleStatusActive
+
+	if err := s.scheduledTransferRepo.Create(ctx, schedule); err != nil {
+		return fmt.Errorf("services: failed to create scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (s *scheduledTransferService) List(ctx context.Context, senderID int) ([]models.ScheduledTransfer, error) {
+	schedules, err := s.scheduledTransferRepo.GetBySenderID(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get scheduled transfers: %w", err)
+	}
+	return schedules, nil
+}
+
+func (s *scheduledTransferService) GetRuns(
+	ctx context.Context, senderID int, scheduleID int) ([]models.ScheduledTransferRun, error) {
+	runs, err := s.scheduledTransferRepo.GetRuns(ctx, scheduleID, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get scheduled transfer runs: %w", err)
+	}
+	return runs, nil
+}
+
+func (s *scheduledTransferService) Cancel(ctx context.Context, senderID int, scheduleID int) error {
+	cancelled, err := s.scheduledTransferRepo.Cancel(ctx, scheduleID, senderID)
+	if err != nil {
+		return fmt.Errorf("services: failed to cancel scheduled transfer: %w", err)
+	}
+	if !cancelled {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+// RunDue executes every schedule whose next run has come, each in its own
+// transaction.
+func (s *scheduledTransferService) RunDue(ctx context.Context) (int, error) {
+	ids, err := s.scheduledTransferRepo.GetDueIDs(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get due scheduled transfers: %w", err)
+	}
+
+	sent := 0
+	var errs []error
+	for _, id := range ids {
+		n, err := s.run(ctx, id)
+		sent += n
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// run makes the transfers that are due for one schedule and moves it to its
+// next run. The transfers, the runs and the schedule update commit together
+// in one transaction, and the schedule stays locked until then, so a run is
+// never executed twice.
+func (s *scheduledTransferService) run(ctx context.Context, scheduleID int) (sent int, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	schedule, err := s.scheduledTransferRepo.GetByIDForUpdate(ctx, tx, scheduleID)
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to lock scheduled transfer: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if schedule == nil || schedule.Status != models.ScheduleStatusActive ||
+		schedule.NextRunAt == nil || schedule.NextRunAt.After(now) {
+		return 0, nil
+	}
+
+	due, next, err := dueRuns(schedule, now)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, at := range due {
+		run := &models.ScheduledTransferRun{
+			ScheduleID:   schedule.ID,
+			ScheduledFor: at,
+			Status:       models.ScheduleRunStatusSkipped,
+		}
+
+		if shouldRun(schedule.CatchUp, i == len(due)-1, at, now) {
+			sendErr, err := s.send(ctx, tx, schedule)
+			if err != nil {
+				return 0, err
+			}
+			if sendErr != nil {
+				message := sendErr.Error()
+				run.Status = models.ScheduleRunStatusFailed
+				run.Error = &message
+				schedule.Failures++
+				schedule.LastError = &message
+			} else {
+				run.Status = models.ScheduleRunStatusSucceeded
+				schedule.Failures = 0
+				schedule.LastError = nil
+				sent++
+			}
+			schedule.LastRunAt = &now
+		}
+
+		if err := s.scheduledTransferRepo.CreateRun(ctx, tx, run); err != nil {
+			return 0, fmt.Errorf("services: failed to record scheduled transfer run: %w", err)
+		}
+
+		if run.Status == models.ScheduleRunStatusFailed {
+			if err := s.notifyFailure(ctx, tx, schedule); err != nil {
+				return 0, err
+			}
+			if schedule.Cron == nil || schedule.Failures >= maxScheduleFailures {
+				break
+			}
+		}
+	}
+
+	schedule.NextRunAt = next
+	switch {
+	case schedule.Cron == nil && schedule.Failures > 0:
+		schedule.Status = models.ScheduleStatusFailed
+	case schedule.Failures >= maxScheduleFailures:
+		schedule.Status = models.ScheduleStatusPaused
+	case next == nil:
+		schedule.Status = models.ScheduleStatusCompleted
+	}
+
+	if err := s.scheduledTransferRepo.Update(ctx, tx, schedule); err != nil {
+		return 0, fmt.Errorf("services: failed to update scheduled transfer: %w", err)
+	}
+
+	return sent, nil
+}
+
+// send makes one transfer of the schedule inside a savepoint, so a failed
+// transfer leaves none of its writes behind and the transaction can still
+// record the failure. It returns the transfer error separately from errors
+// of the savepoint itself.
+func (s *scheduledTransferService) send(
+	ctx context.Context, tx *sqlx.Tx, schedule *models.ScheduledTransfer) (sendErr error, err error) {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT scheduled_transfer`); err != nil {
+		return nil, fmt.Errorf("services: failed to create savepoint: %w", err)
+	}
+
+	sendErr = s.coinService.SendTx(ctx, tx, schedule.SenderID, schedule.ReceiverID, schedule.Amount)
+	if sendErr != nil {
+		if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT scheduled_transfer`); err != nil {
+			return nil, fmt.Errorf("services: failed to roll back to savepoint: %w", err)
+		}
+		return sendErr, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT scheduled_transfer`); err != nil {
+		return nil, fmt.Errorf("services: failed to release savepoint: %w", err)
+	}
+	return nil, nil
+}
+
+// dueRuns lists the run times of the schedule that are not after now and
+// returns the next run after them, or nil when the schedule is done.
+func dueRuns(schedule *models.ScheduledTransfer, now time.Time) ([]time.Time, *time.Time, error) {
+	first := schedule.NextRunAt.UTC()
+	if schedule.Cron == nil {
+		return []time.Time{first}, nil, nil
+	}
+
+	expr, err := cron.Parse(*schedule.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("services: scheduled transfer %d has a bad cron expression: %w", schedule.ID, err)
+	}
+
+	due := []time.Time{first}
+	at := expr.Next(first)
+	for !at.IsZero() && !at.After(now) {
+		due = append(due, at)
+		if len(due) > maxCatchUpRuns {
+			due = due[1:]
+		}
+		at = expr.Next(at)
+	}
+
+	if at.IsZero() {
+		return due, nil, nil
+	}
+	return due, &at, nil
+}
+
+// shouldRun applies the catch-up rule to a due run.
+func shouldRun(catchUp string, latest bool, at time.Time, now time.Time) bool {
+	switch catchUp {
+	case models.CatchUpAll:
+		return true
+	case models.CatchUpSkip:
+		return latest && now.Sub(at) <= catchUpGrace
+	default:
+		return latest
+	}
+}
+
+func (s *scheduledTransferService) notifyFailure(
+	ctx context.Context, tx *sqlx.Tx, schedule *models.ScheduledTransfer) error {
+	message := fmt.Sprintf("Scheduled transfer of %d coins to %s failed: %s",
+		schedule.Amount, schedule.Receiver, *schedule.LastError)
+	if schedule.Cron != nil && schedule.Failures >= maxScheduleFailures {
+		message += fmt.Sprintf(". It was paused after %d failures in a row", schedule.Failures)
+	}
+
+	notification := &models.Notification{
+		UserID:  schedule.SenderID,
+		Type:    models.NotificationTypeScheduleFailed,
+		Message: message,
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify sender: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestScheduledTransferService_Create_Invalid(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		schedule *models.ScheduledTransfer
+	}{
+		{name: "No amount", schedule: &models.ScheduledTransfer{NextRunAt: &future}},
+		{name: "Neither time nor cron", schedule: &models.ScheduledTransfer{Amount: 10}},
+		{name: "Both time and cron", schedule: &models.ScheduledTransfer{Amount: 10, NextRunAt: &future, Cron: strPtr("0 9 * * 1")}},
+		{name: "Time in the past", schedule: &models.ScheduledTransfer{Amount: 10, NextRunAt: &past}},
+		{name: "Bad cron", schedule: &models.ScheduledTransfer{Amount: 10, Cron: strPtr("0 25 * * *")}},
+		{name: "Unknown catch-up rule", schedule: &models.ScheduledTransfer{Amount: 10, Cron: strPtr("0 9 * * 1"), CatchUp: "some"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepo)
+			service := NewScheduledTransferService(mockUserRepo, nil, nil, nil, nil)
+
+			err := service.Create(context.Background(), 1, tt.schedule, "bob")
+			assert.ErrorIs(t, err, ErrInvalidSchedule)
+			mockUserRepo.AssertNotCalled(t, "GetByUsername", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestScheduledTransferService_Create_Recurring(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	mockScheduledTransferRepo := new(mocks.ScheduledTransferRepo)
+	service := NewScheduledTransferService(mockUserRepo, nil, mockScheduledTransferRepo, nil, nil)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockScheduledTransferRepo.On("Create", ctx, mock.MatchedBy(func(s *models.ScheduledTransfer) bool {
+		return s.SenderID == 1 && s.ReceiverID == 2 && s.CatchUp == models.CatchUpLatest &&
+			s.Status == models.ScheduleStatusActive && s.NextRunAt != nil && s.NextRunAt.After(time.Now())
+	})).Return(nil).Once()
+
+	schedule := &models.ScheduledTransfer{Amount: 10, Cron: strPtr("0 9 * * 1")}
+	err := service.Create(ctx, 1, schedule, "bob")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Monday, schedule.NextRunAt.Weekday())
+
+	mockUserRepo.AssertExpectations(t)
+	mockScheduledTransferRepo.AssertExpectations(t)
+}
+
+func TestScheduledTransferService_RunDue_CatchUp(t *testing.T) {
+	hourly := "0 * * * *"
+	yearly := "0 0 1 1 *"
+
+	tests := []struct {
+		name    string
+		cron    string
+		catchUp string
+		sends   int
+		runs    []string
+	}{
+		{
+			name:    "Latest sends once for missed runs",
+			cron:    hourly,
+			catchUp: models.CatchUpLatest,
+			sends:   1,
+			runs:    []string{models.ScheduleRunStatusSkipped, models.ScheduleRunStatusSkipped, models.ScheduleRunStatusSucceeded},
+		},
+		{
+			name:    "All sends every missed run",
+			cron:    hourly,
+			catchUp: models.CatchUpAll,
+			sends:   3,
+			runs:    []string{models.ScheduleRunStatusSucceeded, models.ScheduleRunStatusSucceeded, models.ScheduleRunStatusSucceeded},
+		},
+		{
+			name:    "Skip sends a run that is on time",
+			cron:    hourly,
+			catchUp: models.CatchUpSkip,
+			sends:   1,
+			runs:    []string{models.ScheduleRunStatusSkipped, models.ScheduleRunStatusSkipped, models.ScheduleRunStatusSucceeded},
+		},
+		{
+			name:    "Skip drops a run that is too late",
+			cron:    yearly,
+			catchUp: models.CatchUpSkip,
+			sends:   0,
+			runs:    []string{models.ScheduleRunStatusSkipped},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			for i := 0; i < tt.sends; i++ {
+				sqlMock.ExpectExec("SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+				sqlMock.ExpectExec("RELEASE SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			sqlMock.ExpectCommit()
+
+			mockScheduledTransferRepo := new(mocks.ScheduledTransferRepo)
+			mockCoinService := new(mocks.CoinService)
+			service := NewScheduledTransferService(nil, nil, mockScheduledTransferRepo, mockCoinService, sqlxDB)
+			ctx := context.Background()
+
+			nextRunAt := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+			schedule := &models.ScheduledTransfer{
+				ID: 7, SenderID: 1, ReceiverID: 2, Receiver: "bob", Amount: 10,
+				Cron: &tt.cron, CatchUp: tt.catchUp, NextRunAt: &nextRunAt,
+				Status: models.ScheduleStatusActive,
+			}
+
+			var runs []string
+			mockScheduledTransferRepo.On("GetDueIDs", ctx, mock.Anything).Return([]int{7}, nil).Once()
+			mockScheduledTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(schedule, nil).Once()
+			mockScheduledTransferRepo.On("CreateRun", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				runs = append(runs, args.Get(2).(*models.ScheduledTransferRun).Status)
+			}).Return(nil)
+			mockScheduledTransferRepo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(s *models.ScheduledTransfer) bool {
+				return s.Status == models.ScheduleStatusActive && s.NextRunAt.After(time.Now())
+			})).Return(nil).Once()
+			if tt.sends > 0 {
+				mockCoinService.On("SendTx", ctx, mock.Anything, 1, 2, 10).Return(nil).Times(tt.sends)
+			}
+
+			sent, err := service.RunDue(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sends, sent)
+			assert.Equal(t, tt.runs, runs)
+
+			mockScheduledTransferRepo.AssertExpectations(t)
+			mockCoinService.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestScheduledTransferService_RunDue_PausesAfterFailures(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec("ROLLBACK TO SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectCommit()
+
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockScheduledTransferRepo := new(mocks.ScheduledTransferRepo)
+	mockCoinService := new(mocks.CoinService)
+	service := NewScheduledTransferService(nil, mockNotificationRepo, mockScheduledTransferRepo, mockCoinService, sqlxDB)
+	ctx := context.Background()
+
+	nextRunAt := time.Now().UTC().Add(-time.Minute)
+	schedule := &models.ScheduledTransfer{
+		ID: 7, SenderID: 1, ReceiverID: 2, Receiver: "bob", Amount: 10,
+		Cron: strPtr("0 9 * * 1"), CatchUp: models.CatchUpLatest, NextRunAt: &nextRunAt,
+		Status: models.ScheduleStatusActive, Failures: maxScheduleFailures - 1,
+	}
+
+	mockScheduledTransferRepo.On("GetDueIDs", ctx, mock.Anything).Return([]int{7}, nil).Once()
+	mockScheduledTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(schedule, nil).Once()
+	mockCoinService.On("SendTx", ctx, mock.Anything, 1, 2, 10).Return(errors.New("services: insufficient balance")).Once()
+	mockScheduledTransferRepo.On("CreateRun", ctx, mock.Anything, mock.MatchedBy(func(r *models.ScheduledTransferRun) bool {
+		return r.Status == models.ScheduleRunStatusFailed && r.Error != nil
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeScheduleFailed
+	})).Return(nil).Once()
+	mockScheduledTransferRepo.On("Update", ctx, mock.Anything, mock.MatchedBy(func(s *models.ScheduledTransfer) bool {
+		return s.Status == models.ScheduleStatusPaused && s.Failures == maxScheduleFailures && s.LastError != nil
+	})).Return(nil).Once()
+
+	sent, err := service.RunDue(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	mockNotificationRepo.AssertExpectations(t)
+	mockScheduledTransferRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestScheduledTransferService_RunDue_UpdateFails(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectExec("RELEASE SAVEPOINT scheduled_transfer").WillReturnResult(sqlmock.NewResult(0, 0))
+	sqlMock.ExpectRollback()
+
+	mockScheduledTransferRepo := new(mocks.ScheduledTransferRepo)
+	mockCoinService := new(mocks.CoinService)
+	service := NewScheduledTransferService(nil, nil, mockScheduledTransferRepo, mockCoinService, sqlxDB)
+	ctx := context.Background()
+
+	nextRunAt := time.Now().UTC().Add(-time.Minute)
+	schedule := &models.ScheduledTransfer{
+		ID: 7, SenderID: 1, ReceiverID: 2, Receiver: "bob", Amount: 10,
+		NextRunAt: &nextRunAt, CatchUp: models.CatchUpLatest, Status: models.ScheduleStatusActive,
+	}
+
+	mockScheduledTransferRepo.On("GetDueIDs", ctx, mock.Anything).Return([]int{7}, nil).Once()
+	mockScheduledTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 7).Return(schedule, nil).Once()
+	mockCoinService.On("SendTx", ctx, mock.Anything, 1, 2, 10).Return(nil).Once()
+	mockScheduledTransferRepo.On("CreateRun", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	mockScheduledTransferRepo.On("Update", ctx, mock.Anything, mock.Anything).Return(errors.New("db error")).Once()
+
+	sent, err := service.RunDue(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+
+	mockScheduledTransferRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestScheduledTransferService_Cancel_NotFound(t *testing.T) {
+	mockScheduledTransferRepo := new(mocks.ScheduledTransferRepo)
+	service := NewScheduledTransferService(nil, nil, mockScheduledTransferRepo, nil, nil)
+	ctx := context.Background()
+
+	mockScheduledTransferRepo.On("Cancel", ctx, 7, 1).Return(false, nil).Once()
+
+	err := service.Cancel(ctx, 1, 7)
+	assert.ErrorIs(t, err, ErrScheduleNotFound)
+	mockScheduledTransferRepo.AssertExpectations(t)
+}
@@ -0,0 +1,523 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTeam           = errors.New("services: invalid team")
+	ErrTeamExists            = errors.New("services: team name is taken")
+	ErrTeamNotFound          = errors.New("services: team not found")
+	ErrTeamForbidden         = errors.New("services: only team managers and admins can do this")
+	ErrNotTeamMember         = errors.New("services: user is not a member of the team")
+	ErrInvalidTeamTransfer   = errors.New("services: invalid team transfer")
+	ErrNoTeamRecipients      = errors.New("services: team has no other members to send to")
+	ErrTeamInsufficientFunds = errors.New("services: insufficient balance for team transfer")
+	ErrTeamPotInsufficient   = errors.New("services: team pot does not have enough coins")
+	ErrTeamBudgetExceeded    = errors.New("services: payout exceeds the team's monthly budget")
+)
+
+type TeamService interface {
+	Create(ctx context.Context, name string, department string, budget int) (*models.Team, error)
+	List(ctx context.Context) ([]models.Team, error)
+	Get(ctx context.Context, teamID int) (*models.Team, error)
+	SetBudget(ctx context.Context, teamID int, budget int) (*models.Team, error)
+	SetMember(ctx context.Context, actorID int, teamID int, username string, role string) (*models.TeamMember, error)
+	RemoveMember(ctx context.Context, actorID int, teamID int, username string) error
+	BatchTransfers(ctx context.Context, fromUserID int, teamID int, amount int) ([]models.BatchTransfer, error)
+	SendSplit(ctx context.Context, fromUserID int, teamID int, amount int) (*models.BatchResult, error)
+	SendToPot(ctx context.Context, fromUserID int, teamID int, amount int) (*models.Team, error)
+	PayOut(ctx context.Context, actorID int, teamID int, toUser string, amount int) (*models.TeamBudget, error)
+	Budget(ctx context.Context, userID int, teamID int) (*models.TeamBudget, error)
+	Leaderboard(ctx context.Context, period string) ([]models.TeamStanding, error)
+}
+
+type teamService struct {
+	userRepo         repository.UserRepo
+	transactionRepo  repository.TransactionRepo
+	notificationRepo repository.NotificationRepo
+	teamRepo         repository.TeamRepo
+	transferService  TransferService
+	coinService      CoinService
+	db               *sqlx.DB
+}
+
+func NewTeamService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	teamRepo repository.TeamRepo,
+	transferService TransferService,
+	coinService CoinService,
+	db *sqlx.DB,
+) TeamService {
+	return &teamService{
+		userRepo:         userRepo,
+		transactionRepo:  transactionRepo,
+		notificationRepo: notificationRepo,
+		teamRepo:         teamRepo,
+		transferService:  transferService,
+		coinService:      coinService,
+		db:               db,
+	}
+}
+
+func (s *teamService) Create(ctx context.Context, name string, department string, budget int) (*models.Team, error) {
+	team := &models.Team{
+		Name:       strings.TrimSpace(name),
+		Department: strings.TrimSpace(department),
+		Budget:     budget,
+	}
+	if team.Name == "" || team.Budget < 0 {
+		return nil, ErrInvalidTeam
+	}
+
+	created, err := s.teamRepo.Create(ctx, team)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to create team: %w", err)
+	}
+	if !created {
+		return nil, ErrTeamExists
+	}
+	return team, nil
+}
+
+func (s *teamService) List(ctx context.Context) ([]models.Team, error) {
+	teams, err := s.teamRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get teams: %w", err)
+	}
+	if teams == nil {
+		teams = []models.Team{}
+	}
+	return teams, nil
+}
+
+// Get returns the team with its members.
+func (s *teamService) Get(ctx context.Context, teamID int) (*models.Team, error) {
+	team, err := s.getTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+
+	if team.MemberList, err = s.teamRepo.GetMembers(ctx, teamID); err != nil {
+		return nil, fmt.Errorf("services: failed to get team members: %w", err)
+	}
+	if team.MemberList == nil {
+		team.MemberList = []models.TeamMember{}
+	}
+	return team, nil
+}
+
+func (s *teamService) SetBudget(ctx context.Context, teamID int, budget int) (*models.Team, error) {
+	if budget < 0 {
+		return nil, ErrInvalidTeam
+	}
+
+	updated, err := s.teamRepo.SetBudget(ctx, teamID, budget)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to set team budget: %w", err)
+	}
+	if !updated {
+		return nil, ErrTeamNotFound
+	}
+	return s.getTeam(ctx, teamID)
+}
+
+// SetMember adds the user to the team or changes their role in it. Team
+// managers and admins manage membership.
+func (s *teamService) SetMember(ctx context.Context,
+	actorID int, teamID int, username string, role string) (*models.TeamMember, error) {
+	if role == "" {
+		role = models.TeamRoleMember
+	}
+	if !models.IsValidTeamRole(role) {
+		return nil, ErrInvalidTeam
+	}
+
+	if err := s.checkManager(ctx, actorID, teamID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &models.TeamMember{
+		TeamID:   teamID,
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     role,
+	}
+
+	if err := s.teamRepo.SaveMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("services: failed to save team member: %w", err)
+	}
+	return member, nil
+}
+
+func (s *teamService) RemoveMember(ctx context.Context, actorID int, teamID int, username string) error {
+	if err := s.checkManager(ctx, actorID, teamID); err != nil {
+		return err
+	}
+
+	user, err := s.getUser(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.teamRepo.RemoveMember(ctx, teamID, user.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to remove team member: %w", err)
+	}
+	if !removed {
+		return ErrNotTeamMember
+	}
+	return nil
+}
+
+// BatchTransfers lists a transfer of amount to every member of the team
+// but the sender, for a batch send.
+func (s *teamService) BatchTransfers(
+	ctx context.Context, fromUserID int, teamID int, amount int) ([]models.BatchTransfer, error) {
+	if _, err := s.getTeam(ctx, teamID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.teamRepo.GetMembers(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team members: %w", err)
+	}
+
+	transfers := make([]models.BatchTransfer, 0, len(members))
+	for _, member := range members {
+		if member.UserID == fromUserID {
+			continue
+		}
+		transfers = append(transfers, models.BatchTransfer{ToUser: member.Username, Amount: amount})
+	}
+	if len(transfers) == 0 {
+		return nil, ErrNoTeamRecipients
+	}
+	return transfers, nil
+}
+
+// SendSplit splits amount evenly between the members of the team, other
+// than the sender, as a batch send. Coins that do not divide evenly stay
+// with the sender.
+func (s *teamService) SendSplit(
+	ctx context.Context, fromUserID int, teamID int, amount int) (*models.BatchResult, error) {
+	if amount < 1 {
+		return nil, ErrInvalidTeamTransfer
+	}
+
+	transfers, err := s.BatchTransfers(ctx, fromUserID, teamID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	share := amount / len(transfers)
+	if share < 1 {
+		return nil, ErrInvalidTeamTransfer
+	}
+	for i := range transfers {
+		transfers[i].Amount = share
+	}
+
+	return s.transferService.SendBatch(ctx, fromUserID, transfers)
+}
+
+// SendToPot moves coins from the sender into the team pot, for the
+// managers to pay out later.
+func (s *teamService) SendToPot(
+	ctx context.Context, fromUserID int, teamID int, amount int) (team *models.Team, err error) {
+	if amount < 1 {
+		return nil, ErrInvalidTeamTransfer
+	}
+
+	fromUser, err := s.userRepo.GetByID(ctx, fromUserID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get fromUser by id: %w", err)
+	}
+	if fromUser == nil {
+		return nil, fmt.Errorf("services: fromUser not found")
+	}
+	if fromUser.Balance < amount {
+		return nil, ErrTeamInsufficientFunds
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	team, err = s.teamRepo.GetForUpdate(ctx, tx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock team: %w", err)
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+
+	if err := s.coinService.CheckTransferLimits(ctx, tx, fromUserID, models.TeamPotAccountID, amount); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Debit(ctx, tx, fromUserID, amount); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrTeamInsufficientFunds
+		}
+		return nil, fmt.Errorf("services: failed to update balance of fromUser: %w", err)
+	}
+
+	if err := s.teamRepo.UpdatePot(ctx, tx, teamID, amount); err != nil {
+		return nil, fmt.Errorf("services: failed to update team pot: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   fromUserID,
+		ReceiverID: models.TeamPotAccountID,
+		Amount:     amount,
+		Type:       models.TransactionTypeTeamPot,
+		TeamID:     &teamID,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	team.Pot += amount
+	return team, nil
+}
+
+// PayOut gives coins from the team pot to a member. Only team managers and
+// admins pay out, and no more than the team's monthly budget.
+func (s *teamService) PayOut(ctx context.Context,
+	actorID int, teamID int, toUser string, amount int) (budget *models.TeamBudget, err error) {
+	if amount < 1 {
+		return nil, ErrInvalidTeamTransfer
+	}
+
+	if err := s.checkManager(ctx, actorID, teamID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, toUser)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.teamRepo.GetMemberRole(ctx, teamID, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team role: %w", err)
+	}
+	if role == "" {
+		return nil, ErrNotTeamMember
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	team, err := s.teamRepo.GetForUpdate(ctx, tx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock team: %w", err)
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	if team.Pot < amount {
+		return nil, ErrTeamPotInsufficient
+	}
+
+	now := time.Now()
+	periodStart := monthStart(now)
+	paidOut, err := s.teamRepo.GetPaidOut(ctx, tx, teamID, periodStart)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team payouts: %w", err)
+	}
+	if team.Budget > 0 && paidOut+amount > team.Budget {
+		return nil, ErrTeamBudgetExceeded
+	}
+
+	if err := s.teamRepo.UpdatePot(ctx, tx, teamID, -amount); err != nil {
+		return nil, fmt.Errorf("services: failed to update team pot: %w", err)
+	}
+
+	if err := s.userRepo.Credit(ctx, tx, user.ID, amount, models.CoinSourceTeamPayout); err != nil {
+		return nil, fmt.Errorf("services: failed to update balance of toUser: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   models.TeamPotAccountID,
+		ReceiverID: user.ID,
+		Amount:     amount,
+		Type:       models.TransactionTypeTeamPayout,
+		TeamID:     &teamID,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  user.ID,
+		Type:    models.NotificationTypeTeamPayout,
+		Message: fmt.Sprintf("You received %d coins from the %s team pot", amount, team.Name),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return nil, fmt.Errorf("services: failed to notify receiver: %w", err)
+	}
+
+	budget = &models.TeamBudget{
+		TeamID:  teamID,
+		Period:  now.Format(periodLayout),
+		Pot:     team.Pot - amount,
+		Budget:  team.Budget,
+		PaidOut: paidOut + amount,
+	}
+	budget.Remaining = remainingBudget(budget)
+	return budget, nil
+}
+
+// Budget shows the team pot and what is left of this month's budget to the
+// members of the team and to admins.
+func (s *teamService) Budget(ctx context.Context, userID int, teamID int) (*models.TeamBudget, error) {
+	now := time.Now()
+	budget, err := s.teamRepo.GetBudget(ctx, teamID, monthStart(now))
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team budget: %w", err)
+	}
+	if budget == nil {
+		return nil, ErrTeamNotFound
+	}
+
+	role, err := s.teamRepo.GetMemberRole(ctx, teamID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team role: %w", err)
+	}
+	if role == "" {
+		if err := s.checkAdmin(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	budget.Period = now.Format(periodLayout)
+	budget.Remaining = remainingBudget(budget)
+	return budget, nil
+}
+
+// Leaderboard ranks the teams by the coins their members received in the
+// period, YYYY-MM, or in the current month when the period is empty.
+func (s *teamService) Leaderboard(ctx context.Context, period string) ([]models.TeamStanding, error) {
+	if period == "" {
+		period = time.Now().Format(periodLayout)
+	}
+	from, err := time.ParseInLocation(periodLayout, period, time.Local)
+	if err != nil {
+		return nil, ErrInvalidTeam
+	}
+
+	standings, err := s.teamRepo.GetLeaderboard(ctx, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team leaderboard: %w", err)
+	}
+	if standings == nil {
+		standings = []models.TeamStanding{}
+	}
+
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Received == standings[i-1].Received && standings[i].Sent == standings[i-1].Sent {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+	return standings, nil
+}
+
+func (s *teamService) getTeam(ctx context.Context, teamID int) (*models.Team, error) {
+	team, err := s.teamRepo.GetByID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, ErrTeamNotFound
+	}
+	return team, nil
+}
+
+func (s *teamService) getUser(ctx context.Context, username string) (*models.User, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if user == nil || user.ID < 0 {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// checkManager allows managers of the team and admins.
+func (s *teamService) checkManager(ctx context.Context, userID int, teamID int) error {
+	if _, err := s.getTeam(ctx, teamID); err != nil {
+		return err
+	}
+
+	role, err := s.teamRepo.GetMemberRole(ctx, teamID, userID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get team role: %w", err)
+	}
+	if role == models.TeamRoleManager {
+		return nil
+	}
+	return s.checkAdmin(ctx, userID)
+}
+
+func (s *teamService) checkAdmin(ctx context.Context, userID int) error {
+	role, err := s.userRepo.GetRole(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("services: failed to get user role: %w", err)
+	}
+	if role != models.RoleAdmin {
+		return ErrTeamForbidden
+	}
+	return nil
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func remainingBudget(budget *models.TeamBudget) *int {
+	if budget.Budget == 0 {
+		return nil
+	}
+	remaining := max(budget.Budget-budget.PaidOut, 0)
+	return &remaining
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTeamService_SendSplit(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepo)
+	mockTransferService := new(mocks.TransferService)
+	teamService := NewTeamService(nil, nil, nil, mockTeamRepo, mockTransferService, nil, nil)
+	ctx := context.Background()
+
+	mockTeamRepo.On("GetByID", ctx, 3).Return(&models.Team{ID: 3, Name: "platform"}, nil).Once()
+	mockTeamRepo.On("GetMembers", ctx, 3).Return([]models.TeamMember{
+		{UserID: 1, Username: "alice", Role: models.TeamRoleManager},
+		{UserID: 2, Username: "bob", Role: models.TeamRoleMember},
+		{UserID: 4, Username: "carol", Role: models.TeamRoleMember},
+		{UserID: 5, Username: "dave", Role: models.TeamRoleMember},
+	}, nil).Once()
+	expected := []models.BatchTransfer{
+		{ToUser: "alice", Amount: 33},
+		{ToUser: "carol", Amount: 33},
+		{ToUser: "dave", Amount: 33},
+	}
+	mockTransferService.On("SendBatch", ctx, 2, expected).Return(&models.BatchResult{Total: 99}, nil).Once()
+
+	result, err := teamService.SendSplit(ctx, 2, 3, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 99, result.Total)
+
+	mockTeamRepo.AssertExpectations(t)
+	mockTransferService.AssertExpectations(t)
+}
+
+func TestTeamService_SendSplit_TooLittle(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepo)
+	teamService := NewTeamService(nil, nil, nil, mockTeamRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	mockTeamRepo.On("GetByID", ctx, 3).Return(&models.Team{ID: 3}, nil).Once()
+	mockTeamRepo.On("GetMembers", ctx, 3).Return([]models.TeamMember{
+		{UserID: 1, Username: "alice"}, {UserID: 4, Username: "carol"}, {UserID: 5, Username: "dave"},
+	}, nil).Once()
+
+	_, err := teamService.SendSplit(ctx, 2, 3, 2)
+	assert.ErrorIs(t, err, ErrInvalidTeamTransfer)
+
+	mockTeamRepo.AssertExpectations(t)
+}
+
+func TestTeamService_SendToPot(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockTeamRepo := new(mocks.TeamRepo)
+	mockCoinService := new(mocks.CoinService)
+	teamService := NewTeamService(mockUserRepo, mockTransactionRepo, nil, mockTeamRepo, nil, mockCoinService, sqlxDB)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByID", ctx, 2).Return(&models.User{ID: 2, Balance: 500}, nil).Once()
+	mockTeamRepo.On("GetForUpdate", ctx, mock.Anything, 3).Return(&models.Team{ID: 3, Pot: 40}, nil).Once()
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 2, models.TeamPotAccountID, 60).Return(nil).Once()
+	mockUserRepo.On("Debit", ctx, mock.Anything, 2, 60).Return(nil).Once()
+	mockTeamRepo.On("UpdatePot", ctx, mock.Anything, 3, 60).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 2 && tr.ReceiverID == models.TeamPotAccountID && tr.Amount == 60 &&
+			tr.Type == models.TransactionTypeTeamPot && *tr.TeamID == 3
+	})).Return(nil).Once()
+
+	team, err := teamService.SendToPot(ctx, 2, 3, 60)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, team.Pot)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockTeamRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTeamService_PayOut_BudgetExceeded(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTeamRepo := new(mocks.TeamRepo)
+	teamService := NewTeamService(mockUserRepo, nil, nil, mockTeamRepo, nil, nil, sqlxDB)
+	ctx := context.Background()
+
+	mockTeamRepo.On("GetByID", ctx, 3).Return(&models.Team{ID: 3}, nil).Once()
+	mockTeamRepo.On("GetMemberRole", ctx, 3, 1).Return(models.TeamRoleManager, nil).Once()
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockTeamRepo.On("GetMemberRole", ctx, 3, 2).Return(models.TeamRoleMember, nil).Once()
+	mockTeamRepo.On("GetForUpdate", ctx, mock.Anything, 3).
+		Return(&models.Team{ID: 3, Pot: 500, Budget: 200}, nil).Once()
+	mockTeamRepo.On("GetPaidOut", ctx, mock.Anything, 3, mock.Anything).Return(150, nil).Once()
+
+	_, err := teamService.PayOut(ctx, 1, 3, "bob", 60)
+	assert.ErrorIs(t, err, ErrTeamBudgetExceeded)
+
+	mockTeamRepo.AssertNotCalled(t, "UpdatePot", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+	mockTeamRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTeamService_SetMember_Forbidden(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	mockTeamRepo := new(mocks.TeamRepo)
+	teamService := NewTeamService(mockUserRepo, nil, nil, mockTeamRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	mockTeamRepo.On("GetByID", ctx, 3).Return(&models.Team{ID: 3}, nil).Once()
+	mockTeamRepo.On("GetMemberRole", ctx, 3, 2).Return(models.TeamRoleMember, nil).Once()
+	mockUserRepo.On("GetRole", ctx, 2).Return(models.RoleEmployee, nil).Once()
+
+	_, err := teamService.SetMember(ctx, 2, 3, "carol", models.TeamRoleManager)
+	assert.ErrorIs(t, err, ErrTeamForbidden)
+
+	mockTeamRepo.AssertNotCalled(t, "SaveMember", mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	mockTeamRepo.AssertExpectations(t)
+}
+
+func TestTeamService_Leaderboard_Ties(t *testing.T) {
+	mockTeamRepo := new(mocks.TeamRepo)
+	teamService := NewTeamService(nil, nil, nil, mockTeamRepo, nil, nil, nil)
+	ctx := context.Background()
+
+	mockTeamRepo.On("GetLeaderboard", ctx, mock.Anything, mock.Anything).Return([]models.TeamStanding{
+		{TeamID: 1, Received: 300, Sent: 10},
+		{TeamID: 2, Received: 300, Sent: 10},
+		{TeamID: 3, Received: 100},
+	}, nil).Once()
+
+	standings, err := teamService.Leaderboard(ctx, "2026-10")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 1, 3}, []int{standings[0].Rank, standings[1].Rank, standings[2].Rank})
+
+	_, err = teamService.Leaderboard(ctx, "October")
+	assert.ErrorIs(t, err, ErrInvalidTeam)
+
+	mockTeamRepo.AssertExpectations(t)
+}
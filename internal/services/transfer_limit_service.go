@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+)
+
+var (
+	ErrInvalidTransferLimit  = errors.New("services: invalid transfer limit override")
+	ErrTransferLimitNotFound = errors.New("services: transfer limit override not found")
+)
+
+// TransferLimitService lets admins see the default transfer limits and
+// override them for single users or whole roles.
+type TransferLimitService interface {
+	List(ctx context.Context) (*models.TransferLimitsInfo, error)
+	SetForUser(ctx context.Context, username string, override *models.TransferLimitOverride) error
+	SetForRole(ctx context.Context, role string, override *models.TransferLimitOverride) error
+	Delete(ctx context.Context, overrideID int) error
+}
+
+type transferLimitService struct {
+	userRepo          repository.UserRepo
+	transferLimitRepo repository.TransferLimitRepo
+	defaults          models.TransferLimits
+}
+
+func NewTransferLimitService(
+	userRepo repository.UserRepo,
+	transferLimitRepo repository.TransferLimitRepo,
+	defaults models.TransferLimits,
+) TransferLimitService {
+	return &transferLimitService{
+		userRepo:          userRepo,
+		transferLimitRepo: transferLimitRepo,
+		defaults:          defaults,
+	}
+}
+
+func (s *transferLimitService) List(ctx context.Context) (*models.TransferLimitsInfo, error) {
+	overrides, err := s.transferLimitRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get transfer limit overrides: %w", err)
+	}
+	if overrides == nil {
+		overrides = []models.TransferLimitOverride{}
+	}
+
+	return &models.TransferLimitsInfo{Defaults: s.defaults, Overrides: overrides}, nil
+}
+
+func (s *transferLimitService) SetForUser(
+	ctx context.Context, username string, override *models.TransferLimitOverride) error {
+	if !validOverride(override) {
+		return ErrInvalidTransferLimit
+	}
+
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("services: failed to get user by username: %w", err)
+	}
+	if user == nil || user.ID < 0 {
+		return ErrUserNotFound
+	}
+
+	override.UserID = &user.ID
+	override.Username = &user.Username
+	override.Role = nil
+
+	if err := s.transferLimitRepo.Save(ctx, override); err != nil {
+		return fmt.Errorf("services: failed to save transfer limit override: %w", err)
+	}
+	return nil
+}
+
+func (s *transferLimitService) SetForRole(
+	ctx context.Context, role string, override *models.TransferLimitOverride) error {
+	if !validOverride(override) || !models.IsValidRole(role) {
+		return ErrInvalidTransferLimit
+	}
+
+	override.UserID = nil
+	override.Username = nil
+	override.Role = &role
+
+	if err := s.transferLimitRepo.Save(ctx, override); err != nil {
+		return fmt.Errorf("services: failed to save transfer limit override: %w", err)
+	}
+	return nil
+}
+
+func (s *transferLimitService) Delete(ctx context.Context, overrideID int) error {
+	deleted, err := s.transferLimitRepo.Delete(ctx, overrideID)
+	if err != nil {
+		return fmt.Errorf("services: failed to delete transfer limit override: %w", err)
+	}
+	if !deleted {
+		return ErrTransferLimitNotFound
+	}
+	return nil
+}
+
+// validOverride rejects negative limits. Zero lifts the limit.
+func validOverride(override *models.TransferLimitOverride) bool {
+	for _, limit := range []*int{override.MaxPerTransfer, override.MaxPerDay,
+		override.MaxPerRecipientWeek, override.MaxRecipientsPerHour} {
+		if limit != nil && *limit < 0 {
+			return false
+		}
+	}
+	return true
+}
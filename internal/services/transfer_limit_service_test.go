@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferLimitService_SetForUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransferLimitRepo := new(mocks.TransferLimitRepo)
+	limitService := NewTransferLimitService(mockUserRepo, mockTransferLimitRepo, defaultTransferLimits)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockTransferLimitRepo.On("Save", ctx, mock.MatchedBy(func(o *models.TransferLimitOverride) bool {
+		return *o.UserID == 2 && o.Role == nil && *o.MaxPerDay == 5000 && o.MaxPerTransfer == nil
+	})).Return(nil).Once()
+
+	err := limitService.SetForUser(ctx, "bob", &models.TransferLimitOverride{MaxPerDay: intPtr(5000)})
+	assert.NoError(t, err)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransferLimitRepo.AssertExpectations(t)
+}
+
+func TestTransferLimitService_SetForRole_Invalid(t *testing.T) {
+	mockTransferLimitRepo := new(mocks.TransferLimitRepo)
+	limitService := NewTransferLimitService(nil, mockTransferLimitRepo, defaultTransferLimits)
+	ctx := context.Background()
+
+	err := limitService.SetForRole(ctx, "intern", &models.TransferLimitOverride{MaxPerDay: intPtr(10)})
+	assert.ErrorIs(t, err, ErrInvalidTransferLimit)
+
+	err = limitService.SetForRole(ctx, models.RoleMerchManager, &models.TransferLimitOverride{MaxPerDay: intPtr(-1)})
+	assert.ErrorIs(t, err, ErrInvalidTransferLimit)
+
+	mockTransferLimitRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,592 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"github.com/jmoiron/sqlx"
+	"time"
+)
+
+var (
+	ErrInvalidTransfer         = errors.New("services: invalid transfer")
+	ErrPendingTransferNotFound = errors.New("services: pending transfer not found")
+	ErrTransferResolved        = errors.New("services: transfer is no longer pending")
+	ErrInvalidBatch            = errors.New("services: invalid batch transfer")
+	ErrBatchInsufficientFunds  = errors.New("services: insufficient balance for batch transfer")
+)
+
+const maxBatchRecipients = 100
+
+type TransferService interface {
+	Send(ctx context.Context, fromUserID int, toUserID int, amount int, hold bool) (*models.PendingTransfer, error)
+	SendBatch(ctx context.Context, fromUserID int, transfers []models.BatchTransfer) (*models.BatchResult, error)
+	List(ctx context.Context, userID int) (*models.PendingTransfers, error)
+	Accept(ctx context.Context, userID int, transferID int) (*models.PendingTransfer, error)
+	Decline(ctx context.Context, userID int, transferID int) (*models.PendingTransfer, error)
+	ReturnExpired(ctx context.Context) (int, error)
+	SetAutoAccept(ctx context.Context, userID int, autoAccept bool) error
+}
+
+type transferService struct {
+	userRepo            repository.UserRepo
+	transactionRepo     repository.TransactionRepo
+	notificationRepo    repository.NotificationRepo
+	pendingTransferRepo repository.PendingTransferRepo
+	coinService         CoinService
+	fraudService        FraudService
+	db                  *sqlx.DB
+	acceptWindow        time.Duration
+}
+
+func NewTransferService(
+	userRepo repository.UserRepo,
+	transactionRepo repository.TransactionRepo,
+	notificationRepo repository.NotificationRepo,
+	pendingTransferRepo repository.PendingTransferRepo,
+	coinService CoinService,
+	fraudService FraudService,
+	db *sqlx.DB,
+	acceptWindow time.Duration,
+) TransferService {
+	return &transferService{
+		userRepo:            userRepo,
+		transactionRepo:     transactionRepo,
+		notificationRepo:    notificationRepo,
+		pendingTransferRepo: pendingTransferRepo,
+		coinService:         coinService,
+		fraudService:        fraudService,
+		db:                  db,
+		acceptWindow:        acceptWindow,
+	}
+}
+
+// Send transfers coins right away when the receiver auto-accepts transfers
+// and the sender did not ask for a hold. Otherwise the coins are moved into
+// escrow and the pending transfer is returned. Transfers flagged by the
+// fraud screening are held for an admin review.
+func (s *transferService) Send(
+	ctx context.Context, fromUserID int, toUserID int, amount int, hold bool) (*models.PendingTransfer, error) {
+	if amount < 1 || fromUserID == toUserID {
+		return nil, ErrInvalidTransfer
+	}
+
+	flag, err := s.fraudService.ScreenTransfer(ctx, fromUserID, toUserID, amount)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to screen transfer: %w", err)
+	}
+
+	if !hold && flag == nil {
+		autoAccept, err := s.userRepo.GetAutoAcceptTransfers(ctx, toUserID)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to get transfer setting: %w", err)
+		}
+		if autoAccept {
+			return nil, s.coinService.Send(ctx, fromUserID, toUserID, amount)
+		}
+	}
+
+	return s.hold(ctx, fromUserID, toUserID, amount, flag)
+}
+
+func (s *transferService) hold(ctx context.Context, fromUserID int, toUserID int, amount int,
+	flag *models.FraudFlag) (transfer *models.PendingTransfer, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	fromUser, err := s.userRepo.GetByID(ctx, fromUserID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get fromUser by id: %w", err)
+	}
+	if fromUser == nil {
+		return nil, fmt.Errorf("services: fromUser not found")
+	}
+	if fromUser.Balance < amount {
+		return nil, ErrInsufficientBalance
+	}
+
+	if err := s.coinService.CheckTransferLimits(ctx, tx, fromUserID, toUserID, amount); err != nil {
+		return nil, err
+	}
+
+	transfer = &models.PendingTransfer{
+		SenderID:   fromUserID,
+		Sender:     fromUser.Username,
+		ReceiverID: toUserID,
+		Amount:     amount,
+		Status:     models.PendingTransferStatusPending,
+		ExpiresAt:  time.Now().Add(s.acceptWindow),
+	}
+	if flag != nil {
+		transfer.Status = models.PendingTransferStatusReview
+	}
+
+	if err := s.pendingTransferRepo.Create(ctx, tx, transfer); err != nil {
+		return nil, fmt.Errorf("services: failed to create pending transfer: %w", err)
+	}
+
+	if err := s.userRepo.Hold(ctx, tx, fromUserID, amount, transfer.CoinHold()); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrInsufficientBalance
+		}
+		return nil, fmt.Errorf("services: failed to hold transfer: %w", err)
+	}
+
+	transfer.Receiver, err = s.userRepo.GetUsernameByID(ctx, toUserID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get receiver username: %w", err)
+	}
+
+	if flag != nil {
+		if err := s.holdForReview(ctx, tx, transfer, flag); err != nil {
+			return nil, err
+		}
+		return transfer, nil
+	}
+
+	notification := &models.Notification{
+		UserID: toUserID,
+		Type:   models.NotificationTypeTransferPending,
+		Message: fmt.Sprintf("%s sent you %d coins, accept them before %s",
+			fromUser.Username, amount, transfer.ExpiresAt.Format(time.RFC3339)),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return nil, fmt.Errorf("services: failed to notify receiver: %w", err)
+	}
+
+	return transfer, nil
+}
+
+// holdForReview queues the flag of a transfer held in review and tells the
+// sender about the hold. The receiver hears nothing until the review.
+func (s *transferService) holdForReview(
+	ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer, flag *models.FraudFlag) error {
+	flag.PendingTransferID = &transfer.ID
+	if err := s.fraudService.Record(ctx, tx, flag); err != nil {
+		return err
+	}
+
+	notification := &models.Notification{
+		UserID: transfer.SenderID,
+		Type:   models.NotificationTypeTransferHeld,
+		Message: fmt.Sprintf("your transfer of %d coins to %s is held for a review",
+			transfer.Amount, transfer.Receiver),
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify sender: %w", err)
+	}
+	return nil
+}
+
+// SendBatch validates every recipient first and then runs all transfers in
+// one database transaction, so the batch either fully succeeds or changes
+// nothing. Recipients who do not auto-accept transfers get a pending
+// transfer instead, and transfers flagged by the fraud screening are held
+// for a review. On a validation error the returned result explains
+// which recipients were rejected.
+func (s *transferService) SendBatch(
+	ctx context.Context, fromUserID int, transfers []models.BatchTransfer) (result *models.BatchResult, err error) {
+	if len(transfers) == 0 || len(transfers) > maxBatchRecipients {
+		return nil, ErrInvalidBatch
+	}
+
+	result = &models.BatchResult{Results: make([]models.BatchTransferResult, len(transfers))}
+	recipients := make([]*models.User, len(transfers))
+	seen := make(map[string]bool)
+	valid := true
+
+	for i, t := range transfers {
+		res := &result.Results[i]
+		res.ToUser = t.ToUser
+		res.Amount = t.Amount
+		res.Status = models.BatchTransferStatusNotSent
+
+		reject := func(reason string) {
+			res.Status = models.BatchTransferStatusInvalid
+			res.Error = reason
+			valid = false
+		}
+
+		if t.Amount < 1 {
+			reject("amount must be positive")
+			continue
+		}
+		if seen[t.ToUser] {
+			reject("duplicate recipient")
+			continue
+		}
+		seen[t.ToUser] = true
+
+		user, err := s.userRepo.GetByUsername(ctx, t.ToUser)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to get user by username: %w", err)
+		}
+		if user == nil {
+			reject("receiver not found")
+			continue
+		}
+		if user.ID == fromUserID {
+			reject("cannot send coins to yourself")
+			continue
+		}
+
+		recipients[i] = user
+		result.Total += t.Amount
+	}
+
+	if !valid {
+		return result, ErrInvalidBatch
+	}
+
+	fromUser, err := s.userRepo.GetByID(ctx, fromUserID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get fromUser by id: %w", err)
+	}
+	if fromUser == nil {
+		return nil, fmt.Errorf("services: fromUser not found")
+	}
+	if fromUser.Balance < result.Total {
+		return result, ErrBatchInsufficientFunds
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	batch := &models.TransferBatch{
+		SenderID:   fromUserID,
+		Total:      result.Total,
+		Recipients: len(transfers),
+	}
+
+	if err := s.transactionRepo.CreateBatch(ctx, tx, batch); err != nil {
+		return nil, fmt.Errorf("services: failed to create batch: %w", err)
+	}
+
+	hold := models.CoinHold{Type: models.CoinHoldTransferBatch, ID: batch.ID}
+	if err := s.userRepo.Hold(ctx, tx, fromUserID, result.Total, hold); err != nil {
+		if errors.Is(err, repository.ErrInsufficientBalance) {
+			return nil, ErrBatchInsufficientFunds
+		}
+		return nil, fmt.Errorf("services: failed to update balance of fromUser: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.acceptWindow)
+	for i, user := range recipients {
+		res := &result.Results[i]
+
+		if err := s.coinService.CheckTransferLimits(ctx, tx, fromUserID, user.ID, res.Amount); err != nil {
+			return nil, fmt.Errorf("services: transfer to %s: %w", user.Username, err)
+		}
+
+		flag, err := s.fraudService.ScreenTransfer(ctx, fromUserID, user.ID, res.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to screen transfer: %w", err)
+		}
+
+		if flag != nil {
+			transfer := &models.PendingTransfer{
+				SenderID:   fromUserID,
+				ReceiverID: user.ID,
+				Receiver:   user.Username,
+				Amount:     res.Amount,
+				Status:     models.PendingTransferStatusReview,
+				ExpiresAt:  expiresAt,
+				BatchID:    &batch.ID,
+			}
+
+			if err := s.pendingTransferRepo.Create(ctx, tx, transfer); err != nil {
+				return nil, fmt.Errorf("services: failed to create pending transfer: %w", err)
+			}
+
+			if err := s.holdForReview(ctx, tx, transfer, flag); err != nil {
+				return nil, err
+			}
+
+			res.Status = models.BatchTransferStatusReview
+			continue
+		}
+
+		autoAccept, err := s.userRepo.GetAutoAcceptTransfers(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("services: failed to get transfer setting: %w", err)
+		}
+
+		if !autoAccept {
+			transfer := &models.PendingTransfer{
+				SenderID:   fromUserID,
+				ReceiverID: user.ID,
+				Amount:     res.Amount,
+				Status:     models.PendingTransferStatusPending,
+				ExpiresAt:  expiresAt,
+				BatchID:    &batch.ID,
+			}
+
+			if err := s.pendingTransferRepo.Create(ctx, tx, transfer); err != nil {
+				return nil, fmt.Errorf("services: failed to create pending transfer: %w", err)
+			}
+
+			notification := &models.Notification{
+				UserID: user.ID,
+				Type:   models.NotificationTypeTransferPending,
+				Message: fmt.Sprintf("%s sent you %d coins, accept them before %s",
+					fromUser.Username, res.Amount, expiresAt.Format(time.RFC3339)),
+			}
+
+			if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+				return nil, fmt.Errorf("services: failed to notify receiver: %w", err)
+			}
+
+			res.Status = models.BatchTransferStatusPending
+			continue
+		}
+
+		if err := s.userRepo.Credit(ctx, tx, user.ID, res.Amount, models.CoinSourceTransfer); err != nil {
+			return nil, fmt.Errorf("services: failed to update balance of toUser: %w", err)
+		}
+
+		transaction := &models.Transaction{
+			SenderID:   fromUserID,
+			ReceiverID: user.ID,
+			Amount:     res.Amount,
+			Type:       models.TransactionTypeTransfer,
+			BatchID:    &batch.ID,
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+		}
+
+		res.Status = models.BatchTransferStatusSent
+	}
+
+	result.BatchID = batch.ID
+
+	return result, nil
+}
+
+func (s *transferService) List(ctx context.Context, userID int) (*models.PendingTransfers, error) {
+	incoming, err := s.pendingTransferRepo.GetIncoming(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get incoming transfers: %w", err)
+	}
+
+	outgoing, err := s.pendingTransferRepo.GetOutgoing(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get outgoing transfers: %w", err)
+	}
+
+	transfers := &models.PendingTransfers{Incoming: incoming, Outgoing: outgoing}
+	if transfers.Incoming == nil {
+		transfers.Incoming = []models.PendingTransfer{}
+	}
+	if transfers.Outgoing == nil {
+		transfers.Outgoing = []models.PendingTransfer{}
+	}
+
+	return transfers, nil
+}
+
+// Accept releases the escrowed coins to the receiver and records the
+// transfer in the ledger.
+func (s *transferService) Accept(
+	ctx context.Context, userID int, transferID int) (transfer *models.PendingTransfer, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	transfer, err = s.lockPending(ctx, tx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.Credit(ctx, tx, transfer.ReceiverID, transfer.Amount, models.CoinSourceTransfer); err != nil {
+		return nil, fmt.Errorf("services: failed to update balance of toUser: %w", err)
+	}
+
+	transaction := &models.Transaction{
+		SenderID:   transfer.SenderID,
+		ReceiverID: transfer.ReceiverID,
+		Amount:     transfer.Amount,
+		Type:       models.TransactionTypeTransfer,
+		BatchID:    transfer.BatchID,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, fmt.Errorf("services: failed to create transaction: %w", err)
+	}
+
+	transfer.Status = models.PendingTransferStatusAccepted
+	transfer.TransactionID = &transaction.ID
+
+	if err := s.pendingTransferRepo.Resolve(ctx, tx, transfer); err != nil {
+		return nil, fmt.Errorf("services: failed to resolve pending transfer: %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (s *transferService) Decline(
+	ctx context.Context, userID int, transferID int) (transfer *models.PendingTransfer, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	transfer, err = s.lockPending(ctx, tx, userID, transferID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%s declined your transfer, %d coins were returned", transfer.Receiver, transfer.Amount)
+	if err := s.giveBack(ctx, tx, transfer, models.PendingTransferStatusDeclined, message); err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+// lockPending locks a transfer that the user can still accept or decline.
+func (s *transferService) lockPending(
+	ctx context.Context, tx *sqlx.Tx, userID int, transferID int) (*models.PendingTransfer, error) {
+	transfer, err := s.pendingTransferRepo.GetByIDForUpdate(ctx, tx, transferID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to lock pending transfer: %w", err)
+	}
+	if transfer == nil || transfer.ReceiverID != userID {
+		return nil, ErrPendingTransferNotFound
+	}
+	if transfer.Status != models.PendingTransferStatusPending || !time.Now().Before(transfer.ExpiresAt) {
+		return nil, ErrTransferResolved
+	}
+	return transfer, nil
+}
+
+// giveBack returns the escrowed coins to the sender.
+func (s *transferService) giveBack(
+	ctx context.Context, tx *sqlx.Tx, transfer *models.PendingTransfer, status string, message string) error {
+	err := s.userRepo.Release(ctx, tx, transfer.SenderID, transfer.Amount, models.CoinSourceRelease, transfer.CoinHold())
+	if err != nil {
+		return fmt.Errorf("services: failed to return transfer: %w", err)
+	}
+
+	transfer.Status = status
+
+	if err := s.pendingTransferRepo.Resolve(ctx, tx, transfer); err != nil {
+		return fmt.Errorf("services: failed to resolve pending transfer: %w", err)
+	}
+
+	notification := &models.Notification{
+		UserID:  transfer.SenderID,
+		Type:    models.NotificationTypeTransferReturned,
+		Message: message,
+	}
+
+	if err := s.notificationRepo.Create(ctx, tx, notification); err != nil {
+		return fmt.Errorf("services: failed to notify sender: %w", err)
+	}
+	return nil
+}
+
+// ReturnExpired gives back every transfer that was not accepted in time,
+// each in its own transaction.
+func (s *transferService) ReturnExpired(ctx context.Context) (int, error) {
+	ids, err := s.pendingTransferRepo.GetExpiredIDs(ctx, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("services: failed to get expired transfers: %w", err)
+	}
+
+	returned := 0
+	var errs []error
+	for _, id := range ids {
+		ok, err := s.returnExpired(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ok {
+			returned++
+		}
+	}
+
+	return returned, errors.Join(errs...)
+}
+
+func (s *transferService) returnExpired(ctx context.Context, transferID int) (returned bool, err error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		} else {
+			tx.Commit()
+		}
+	}()
+
+	transfer, err := s.pendingTransferRepo.GetByIDForUpdate(ctx, tx, transferID)
+	if err != nil {
+		return false, fmt.Errorf("services: failed to lock pending transfer: %w", err)
+	}
+	if transfer == nil || transfer.Status != models.PendingTransferStatusPending ||
+		time.Now().Before(transfer.ExpiresAt) {
+		return false, nil
+	}
+
+	message := fmt.Sprintf("%s did not accept your transfer in time, %d coins were returned",
+		transfer.Receiver, transfer.Amount)
+	if err := s.giveBack(ctx, tx, transfer, models.PendingTransferStatusReturned, message); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *transferService) SetAutoAccept(ctx context.Context, userID int, autoAccept bool) error {
+	if err := s.userRepo.SetAutoAcceptTransfers(ctx, userID, autoAccept); err != nil {
+		return fmt.Errorf("services: failed to update transfer setting: %w", err)
+	}
+	return nil
+}
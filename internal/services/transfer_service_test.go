@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferService_Send_AutoAccept(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	mockCoinService := new(mocks.CoinService)
+	mockFraudService := new(mocks.FraudService)
+	transferService := NewTransferService(mockUserRepo, nil, nil, nil,
+		mockCoinService, mockFraudService, nil, time.Hour)
+	ctx := context.Background()
+
+	mockFraudService.On("ScreenTransfer", ctx, 1, 2, 30).Return(nil, nil).Once()
+	mockUserRepo.On("GetAutoAcceptTransfers", ctx, 2).Return(true, nil).Once()
+	mockCoinService.On("Send", ctx, 1, 2, 30).Return(nil).Once()
+
+	pending, err := transferService.Send(ctx, 1, 2, 30, false)
+	assert.NoError(t, err)
+	assert.Nil(t, pending)
+
+	mockUserRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+}
+
+func TestTransferService_Send_Held(t *testing.T) {
+	tests := []struct {
+		name       string
+		hold       bool
+		autoAccept bool
+	}{
+		{name: "Receiver requires acceptance", autoAccept: false},
+		{name: "Sender asks for hold", hold: true, autoAccept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlxDB, sqlMock := setupTestDB(t)
+			defer sqlxDB.Close()
+
+			sqlMock.ExpectBegin()
+			sqlMock.ExpectCommit()
+
+			mockUserRepo := new(mocks.UserRepo)
+			mockNotificationRepo := new(mocks.NotificationRepo)
+			mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+			mockCoinService := new(mocks.CoinService)
+			mockFraudService := new(mocks.FraudService)
+			transferService := NewTransferService(mockUserRepo, nil, mockNotificationRepo,
+				mockPendingTransferRepo, mockCoinService, mockFraudService, sqlxDB, time.Hour)
+			ctx := context.Background()
+
+			mockFraudService.On("ScreenTransfer", ctx, 1, 2, 30).Return(nil, nil).Once()
+			mockUserRepo.On("GetAutoAcceptTransfers", ctx, 2).Return(tt.autoAccept, nil).Maybe()
+			mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Username: "alice", Balance: 100}, nil).Once()
+			mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 30).Return(nil).Once()
+			mockUserRepo.On("Hold", ctx, mock.Anything, 1, 30, mock.Anything).Return(nil).Once()
+			mockPendingTransferRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+				return pt.SenderID == 1 && pt.ReceiverID == 2 && pt.Amount == 30 &&
+					pt.Status == models.PendingTransferStatusPending && pt.ExpiresAt.After(time.Now())
+			})).Return(nil).Once()
+			mockUserRepo.On("GetUsernameByID", ctx, 2).Return("bob", nil).Once()
+			mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+				return n.UserID == 2 && n.Type == models.NotificationTypeTransferPending
+			})).Return(nil).Once()
+
+			pending, err := transferService.Send(ctx, 1, 2, 30, tt.hold)
+			assert.NoError(t, err)
+			assert.Equal(t, "bob", pending.Receiver)
+
+			mockCoinService.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockCoinService.AssertExpectations(t)
+			mockUserRepo.AssertExpectations(t)
+			mockPendingTransferRepo.AssertExpectations(t)
+			mockNotificationRepo.AssertExpectations(t)
+			assert.NoError(t, sqlMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTransferService_Send_HeldForReview(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+	mockCoinService := new(mocks.CoinService)
+	mockFraudService := new(mocks.FraudService)
+	transferService := NewTransferService(mockUserRepo, nil, mockNotificationRepo,
+		mockPendingTransferRepo, mockCoinService, mockFraudService, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	flag := &models.FraudFlag{UserID: 1, CounterpartyID: intPtr(2), Amount: 30,
+		Rule: models.FraudRuleCircularFlow, Status: models.FraudFlagStatusOpen}
+
+	mockFraudService.On("ScreenTransfer", ctx, 1, 2, 30).Return(flag, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Username: "alice", Balance: 100}, nil).Once()
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 30).Return(nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 30, mock.Anything).Return(nil).Once()
+	mockPendingTransferRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+		return pt.Status == models.PendingTransferStatusReview
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.PendingTransfer).ID = 7
+	}).Once()
+	mockUserRepo.On("GetUsernameByID", ctx, 2).Return("bob", nil).Once()
+	mockFraudService.On("Record", ctx, mock.Anything, mock.MatchedBy(func(f *models.FraudFlag) bool {
+		return *f.PendingTransferID == 7
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeTransferHeld
+	})).Return(nil).Once()
+
+	pending, err := transferService.Send(ctx, 1, 2, 30, false)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PendingTransferStatusReview, pending.Status)
+
+	mockUserRepo.AssertNotCalled(t, "GetAutoAcceptTransfers", mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+	mockCoinService.AssertExpectations(t)
+	mockFraudService.AssertExpectations(t)
+	mockPendingTransferRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransferService_Accept(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+	transferService := NewTransferService(mockUserRepo, mockTransactionRepo, nil,
+		mockPendingTransferRepo, nil, nil, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	transfer := &models.PendingTransfer{ID: 4, SenderID: 1, ReceiverID: 2, Amount: 30,
+		Status: models.PendingTransferStatusPending, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockPendingTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 4).Return(transfer, nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, 30, models.CoinSourceTransfer).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Amount == 30 && tr.Type == models.TransactionTypeTransfer
+	})).Return(nil).Once()
+	mockPendingTransferRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+		return pt.Status == models.PendingTransferStatusAccepted && pt.TransactionID != nil
+	})).Return(nil).Once()
+
+	accepted, err := transferService.Accept(ctx, 2, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PendingTransferStatusAccepted, accepted.Status)
+
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockPendingTransferRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransferService_Accept_Expired(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectRollback()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+	transferService := NewTransferService(mockUserRepo, nil, nil, mockPendingTransferRepo, nil, nil, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	transfer := &models.PendingTransfer{ID: 4, SenderID: 1, ReceiverID: 2, Amount: 30,
+		Status: models.PendingTransferStatusPending, ExpiresAt: time.Now().Add(-time.Minute)}
+	mockPendingTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 4).Return(transfer, nil).Once()
+
+	_, err := transferService.Accept(ctx, 2, 4)
+	assert.ErrorIs(t, err, ErrTransferResolved)
+
+	mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransferService_ReturnExpired(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+	transferService := NewTransferService(mockUserRepo, nil, mockNotificationRepo,
+		mockPendingTransferRepo, nil, nil, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	transfer := &models.PendingTransfer{ID: 4, SenderID: 1, ReceiverID: 2, Receiver: "bob", Amount: 30,
+		Status: models.PendingTransferStatusPending, ExpiresAt: time.Now().Add(-time.Minute)}
+
+	mockPendingTransferRepo.On("GetExpiredIDs", ctx, mock.Anything).Return([]int{4}, nil).Once()
+	mockPendingTransferRepo.On("GetByIDForUpdate", ctx, mock.Anything, 4).Return(transfer, nil).Once()
+	mockUserRepo.On("Release", ctx, mock.Anything, 1, 30, models.CoinSourceRelease, mock.Anything).Return(nil).Once()
+	mockPendingTransferRepo.On("Resolve", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+		return pt.Status == models.PendingTransferStatusReturned
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 1 && n.Type == models.NotificationTypeTransferReturned
+	})).Return(nil).Once()
+
+	returned, err := transferService.ReturnExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, returned)
+
+	mockUserRepo.AssertExpectations(t)
+	mockNotificationRepo.AssertExpectations(t)
+	mockPendingTransferRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransferService_SendBatch(t *testing.T) {
+	sqlxDB, sqlMock := setupTestDB(t)
+	defer sqlxDB.Close()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	mockUserRepo := new(mocks.UserRepo)
+	mockTransactionRepo := new(mocks.TransactionRepo)
+	mockNotificationRepo := new(mocks.NotificationRepo)
+	mockPendingTransferRepo := new(mocks.PendingTransferRepo)
+	mockCoinService := new(mocks.CoinService)
+	mockFraudService := new(mocks.FraudService)
+	transferService := NewTransferService(mockUserRepo, mockTransactionRepo, mockNotificationRepo,
+		mockPendingTransferRepo, mockCoinService, mockFraudService, sqlxDB, time.Hour)
+	ctx := context.Background()
+
+	mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Once()
+	mockUserRepo.On("GetByUsername", ctx, "carol").Return(&models.User{ID: 3, Username: "carol"}, nil).Once()
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Username: "alice", Balance: 100}, nil).Once()
+	mockUserRepo.On("Hold", ctx, mock.Anything, 1, 40, mock.Anything).Return(nil).Once()
+	mockTransactionRepo.On("CreateBatch", ctx, mock.Anything, mock.MatchedBy(func(b *models.TransferBatch) bool {
+		return b.SenderID == 1 && b.Total == 40 && b.Recipients == 2
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(2).(*models.TransferBatch).ID = 9
+	}).Once()
+
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 2, 20).Return(nil).Once()
+	mockFraudService.On("ScreenTransfer", ctx, 1, 2, 20).Return(nil, nil).Once()
+	mockUserRepo.On("GetAutoAcceptTransfers", ctx, 2).Return(true, nil).Once()
+	mockUserRepo.On("Credit", ctx, mock.Anything, 2, 20, models.CoinSourceTransfer).Return(nil).Once()
+	mockTransactionRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(tr *models.Transaction) bool {
+		return tr.SenderID == 1 && tr.ReceiverID == 2 && tr.Amount == 20 && *tr.BatchID == 9
+	})).Return(nil).Once()
+
+	mockCoinService.On("CheckTransferLimits", ctx, mock.Anything, 1, 3, 20).Return(nil).Once()
+	mockFraudService.On("ScreenTransfer", ctx, 1, 3, 20).Return(nil, nil).Once()
+	mockUserRepo.On("GetAutoAcceptTransfers", ctx, 3).Return(false, nil).Once()
+	mockPendingTransferRepo.On("Create", ctx, mock.Anything, mock.MatchedBy(func(pt *models.PendingTransfer) bool {
+		return pt.ReceiverID == 3 && pt.Amount == 20 && *pt.BatchID == 9
+	})).Return(nil).Once()
+	mockNotificationRepo.On("Create", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+	result, err := transferService.SendBatch(ctx, 1, []models.BatchTransfer{
+		{ToUser: "bob", Amount: 20},
+		{ToUser: "carol", Amount: 20},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 9, result.BatchID)
+	assert.Equal(t, 40, result.Total)
+	assert.Equal(t, models.BatchTransferStatusSent, result.Results[0].Status)
+	assert.Equal(t, models.BatchTransferStatusPending, result.Results[1].Status)
+
+	mockCoinService.AssertExpectations(t)
+	mockUserRepo.AssertExpectations(t)
+	mockTransactionRepo.AssertExpectations(t)
+	mockPendingTransferRepo.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTransferService_SendBatch_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		transfers   []models.BatchTransfer
+		balance     int
+		expectedErr error
+		statuses    []string
+	}{
+		{
+			name: "Unknown and duplicate recipients",
+			transfers: []models.BatchTransfer{
+				{ToUser: "bob", Amount: 20}, {ToUser: "ghost", Amount: 20}, {ToUser: "bob", Amount: 5}},
+			balance:     100,
+			expectedErr: ErrInvalidBatch,
+			statuses: []string{models.BatchTransferStatusNotSent, models.BatchTransferStatusInvalid,
+				models.BatchTransferStatusInvalid},
+		},
+		{
+			name:        "Sending to yourself",
+			transfers:   []models.BatchTransfer{{ToUser: "alice", Amount: 20}},
+			balance:     100,
+			expectedErr: ErrInvalidBatch,
+			statuses:    []string{models.BatchTransferStatusInvalid},
+		},
+		{
+			name:        "Total exceeds balance",
+			transfers:   []models.BatchTransfer{{ToUser: "bob", Amount: 60}, {ToUser: "carol", Amount: 60}},
+			balance:     100,
+			expectedErr: ErrBatchInsufficientFunds,
+			statuses:    []string{models.BatchTransferStatusNotSent, models.BatchTransferStatusNotSent},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepo)
+			transferService := NewTransferService(mockUserRepo, nil, nil, nil, nil, nil, nil, time.Hour)
+			ctx := context.Background()
+
+			mockUserRepo.On("GetByUsername", ctx, "alice").Return(&models.User{ID: 1, Username: "alice"}, nil).Maybe()
+			mockUserRepo.On("GetByUsername", ctx, "bob").Return(&models.User{ID: 2, Username: "bob"}, nil).Maybe()
+			mockUserRepo.On("GetByUsername", ctx, "carol").Return(&models.User{ID: 3, Username: "carol"}, nil).Maybe()
+			mockUserRepo.On("GetByUsername", ctx, "ghost").Return(nil, nil).Maybe()
+			mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: tt.balance}, nil).Maybe()
+
+			result, err := transferService.SendBatch(ctx, 1, tt.transfers)
+			assert.ErrorIs(t, err, tt.expectedErr)
+			for i, status := range tt.statuses {
+				assert.Equal(t, status, result.Results[i].Status)
+			}
+
+			mockUserRepo.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockUserRepo.AssertNotCalled(t, "Credit", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
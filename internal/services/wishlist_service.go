@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/internal/repository"
+	"time"
+)
+
+var ErrWishlistEntryNotFound = errors.New("services: item is not in wishlist")
+
+type WishlistService interface {
+	Add(ctx context.Context, userID int, itemName string) error
+	Remove(ctx context.Context, userID int, itemName string) error
+	List(ctx context.Context, userID int) ([]models.WishlistEntry, error)
+}
+
+type wishlistService struct {
+	userRepo      repository.UserRepo
+	itemRepo      repository.ItemRepo
+	wishlistRepo  repository.WishlistRepo
+	priceRuleRepo repository.PriceRuleRepo
+}
+
+func NewWishlistService(
+	userRepo repository.UserRepo,
+	itemRepo repository.ItemRepo,
+	wishlistRepo repository.WishlistRepo,
+	priceRuleRepo repository.PriceRuleRepo,
+) WishlistService {
+	return &wishlistService{
+		userRepo:      userRepo,
+		itemRepo:      itemRepo,
+		wishlistRepo:  wishlistRepo,
+		priceRuleRepo: priceRuleRepo,
+	}
+}
+
+func (s *wishlistService) Add(ctx context.Context, userID int, itemName string) error {
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil || item.ArchivedAt != nil {
+		return ErrItemNotFound
+	}
+
+	if err := s.wishlistRepo.Add(ctx, userID, item.ID); err != nil {
+		return fmt.Errorf("services: failed to add to wishlist: %w", err)
+	}
+	return nil
+}
+
+func (s *wishlistService) Remove(ctx context.Context, userID int, itemName string) error {
+	item, err := s.itemRepo.GetItemByName(ctx, itemName)
+	if err != nil {
+		return fmt.Errorf("services: failed to get item by name: %w", err)
+	}
+	if item == nil {
+		return ErrItemNotFound
+	}
+
+	removed, err := s.wishlistRepo.Remove(ctx, userID, item.ID)
+	if err != nil {
+		return fmt.Errorf("services: failed to remove from wishlist: %w", err)
+	}
+	if !removed {
+		return ErrWishlistEntryNotFound
+	}
+	return nil
+}
+
+// List returns the wishlist with current sale prices and whether the user's
+// balance covers each item.
+func (s *wishlistService) List(ctx context.Context, userID int) ([]models.WishlistEntry, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get user by id: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("services: user not found")
+	}
+
+	entries, err := s.wishlistRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get wishlist: %w", err)
+	}
+
+	rules, err := s.priceRuleRepo.GetActive(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("services: failed to get price rules: %w", err)
+	}
+
+	for i := range entries {
+		item := models.Item{ID: entries[i].ItemID, Category: entries[i].Category}
+		entries[i].Price, _ = models.SalePrice(item, entries[i].Price, rules)
+		entries[i].Affordable = entries[i].InStock && user.Balance >= entries[i].Price
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gratefultolord/merch-store/internal/models"
+	"github.com/gratefultolord/merch-store/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWishlistService_List_Affordable(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepo)
+	mockWishlistRepo := new(mocks.WishlistRepo)
+	mockPriceRuleRepo := new(mocks.PriceRuleRepo)
+
+	wishlistService := NewWishlistService(mockUserRepo, nil, mockWishlistRepo, mockPriceRuleRepo)
+	ctx := context.Background()
+
+	itemID := 6
+	salePrice := 200
+	entries := []models.WishlistEntry{
+		{ItemID: 10, Item: "pink-hoody", Price: 500, InStock: true},
+		{ItemID: 6, Item: "hoody", Price: 300, InStock: true},
+		{ItemID: 7, Item: "umbrella", Price: 200, InStock: false},
+	}
+
+	mockUserRepo.On("GetByID", ctx, 1).Return(&models.User{ID: 1, Balance: 250}, nil).Once()
+	mockWishlistRepo.On("GetByUserID", ctx, 1).Return(entries, nil).Once()
+	mockPriceRuleRepo.On("GetActive", ctx, mock.Anything).
+		Return([]models.PriceRule{{ID: 1, ItemID: &itemID, Price: &salePrice}}, nil).Once()
+
+	wishlist, err := wishlistService.List(ctx, 1)
+	assert.NoError(t, err)
+
+	assert.False(t, wishlist[0].Affordable)
+	assert.True(t, wishlist[1].Affordable)
+	assert.Equal(t, 200, wishlist[1].Price)
+	assert.False(t, wishlist[2].Affordable)
+}
+
+func TestWishlistService_Remove_NotWishlisted(t *testing.T) {
+	mockItemRepo := new(mocks.ItemRepo)
+	mockWishlistRepo := new(mocks.WishlistRepo)
+
+	wishlistService := NewWishlistService(nil, mockItemRepo, mockWishlistRepo, nil)
+	ctx := context.Background()
+
+	mockItemRepo.On("GetItemByName", ctx, "cup").Return(&models.Item{ID: 2, Name: "cup"}, nil).Once()
+	mockWishlistRepo.On("Remove", ctx, 1, 2).Return(false, nil).Once()
+
+	err := wishlistService.Remove(ctx, 1, "cup")
+	assert.ErrorIs(t, err, ErrWishlistEntryNotFound)
+}
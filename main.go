@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"github.com/gratefultolord/merch-store/internal/config"
 	"github.com/gratefultolord/merch-store/internal/handlers"
+	"github.com/gratefultolord/merch-store/internal/jobs"
 	mw "github.com/gratefultolord/merch-store/internal/middleware"
+	"github.com/gratefultolord/merch-store/internal/models"
 	"github.com/gratefultolord/merch-store/internal/repository"
 	"github.com/gratefultolord/merch-store/internal/services"
 	"github.com/jmoiron/sqlx"
@@ -37,19 +39,115 @@ func main() {
 	userRepo := repository.NewUserRepo(db)
 	itemRepo := repository.NewItemRepo(db)
 	transactionRepo := repository.NewTransactionRepo(db)
+	orderRepo := repository.NewOrderRepo(db)
+	returnRepo := repository.NewReturnRepo(db)
+	notificationRepo := repository.NewNotificationRepo(db)
+	itemTransferRepo := repository.NewItemTransferRepo(db)
+	promoCodeRepo := repository.NewPromoCodeRepo(db)
+	priceRuleRepo := repository.NewPriceRuleRepo(db)
+	wishlistRepo := repository.NewWishlistRepo(db)
+	preorderRepo := repository.NewPreorderRepo(db)
+	auctionRepo := repository.NewAuctionRepo(db)
+	raffleRepo := repository.NewRaffleRepo(db)
+	poolRepo := repository.NewPoolRepo(db)
+	coinRequestRepo := repository.NewCoinRequestRepo(db)
+	pendingTransferRepo := repository.NewPendingTransferRepo(db)
+	scheduledTransferRepo := repository.NewScheduledTransferRepo(db)
+	issuanceRepo := repository.NewIssuanceRepo(db)
+	coinLotRepo := repository.NewCoinLotRepo(db)
+	transferLimitRepo := repository.NewTransferLimitRepo(db)
+	fraudRepo := repository.NewFraudRepo(db)
+	reversalRepo := repository.NewReversalRepo(db)
+	auditRepo := repository.NewAuditRepo(db)
+	teamRepo := repository.NewTeamRepo(db)
+	tenantRepo := repository.NewTenantRepo(db)
 
-	authService := services.NewAuthService(userRepo, cfg.JWTSecret)
-	coinService := services.NewCoinService(userRepo, transactionRepo, db)
-	inventoryService := services.NewInventoryService(userRepo, itemRepo, transactionRepo, db)
-	infoService := services.NewInfoService(userRepo, coinService)
+	auditService := services.NewAuditService(auditRepo, db)
+	authService := services.NewAuthService(
+		userRepo, tenantRepo, transactionRepo, issuanceRepo, auditService, db, cfg.JWTSecret)
+	transferLimits := models.TransferLimits{
+		MaxPerTransfer:       cfg.MaxTransferAmount,
+		MaxPerDay:            cfg.MaxTransferDaily,
+		MaxPerRecipientWeek:  cfg.MaxTransferRecipientWeekly,
+		MaxRecipientsPerHour: cfg.MaxTransferRecipientsHourly,
+	}
+	coinService := services.NewCoinService(userRepo, transactionRepo, transferLimitRepo, db, transferLimits)
+	transferLimitService := services.NewTransferLimitService(userRepo, transferLimitRepo, transferLimits)
+	inventoryService := services.NewInventoryService(
+		userRepo, itemRepo, transactionRepo, orderRepo, notificationRepo, itemTransferRepo,
+		promoCodeRepo, priceRuleRepo, db)
+	wishlistService := services.NewWishlistService(userRepo, itemRepo, wishlistRepo, priceRuleRepo)
+	fraudService := services.NewFraudService(
+		userRepo, transactionRepo, notificationRepo, pendingTransferRepo, fraudRepo, db, cfg.FraudHoldForReview)
+	allowanceService := services.NewAllowanceService(
+		userRepo, transactionRepo, issuanceRepo, coinService, fraudService, db, cfg.MonthlyGivingAllowance)
+	infoService := services.NewInfoService(
+		userRepo, notificationRepo, coinService, wishlistService,
+		preorderRepo, auctionRepo, poolRepo, pendingTransferRepo, allowanceService, coinLotRepo)
+	returnService := services.NewReturnService(
+		userRepo, itemRepo, orderRepo, returnRepo, transactionRepo, poolRepo, db,
+		time.Duration(cfg.ReturnWindowDays)*24*time.Hour)
+	promoService := services.NewPromoService(promoCodeRepo)
+	preorderService := services.NewPreorderService(
+		userRepo, itemRepo, transactionRepo, orderRepo, notificationRepo, priceRuleRepo, preorderRepo, db)
+	auctionService := services.NewAuctionService(
+		userRepo, itemRepo, transactionRepo, orderRepo, notificationRepo, auctionRepo, db)
+	raffleService := services.NewRaffleService(
+		userRepo, itemRepo, transactionRepo, orderRepo, notificationRepo, raffleRepo, db)
+	poolService := services.NewPoolService(
+		userRepo, itemRepo, transactionRepo, orderRepo, notificationRepo, priceRuleRepo, poolRepo, db)
+	coinRequestService := services.NewCoinRequestService(
+		userRepo, coinRequestRepo, notificationRepo, coinService, db,
+		time.Duration(cfg.CoinRequestTTLHours)*time.Hour, cfg.MaxOpenCoinRequests)
+	transferService := services.NewTransferService(
+		userRepo, transactionRepo, notificationRepo, pendingTransferRepo, coinService, fraudService, db,
+		time.Duration(cfg.TransferAcceptWindowHours)*time.Hour)
+	scheduledTransferService := services.NewScheduledTransferService(
+		userRepo, notificationRepo, scheduledTransferRepo, coinService, db)
+	issuanceService := services.NewIssuanceService(
+		userRepo, transactionRepo, notificationRepo, issuanceRepo, auditService, db)
+	coinExpiryService := services.NewCoinExpiryService(userRepo, transactionRepo, notificationRepo, coinLotRepo, db)
+	reversalService := services.NewReversalService(
+		userRepo, transactionRepo, notificationRepo, reversalRepo, auditService, db)
+	catalogService := services.NewCatalogService(
+		itemRepo, priceRuleRepo, wishlistRepo, notificationRepo, preorderService, auditService, db)
+	adminService := services.NewAdminService(userRepo, itemRepo, auditService, db)
+	teamService := services.NewTeamService(
+		userRepo, transactionRepo, notificationRepo, teamRepo, transferService, coinService, db)
+	orderService := services.NewOrderService(
+		userRepo, itemRepo, orderRepo, transactionRepo, notificationRepo, poolRepo, db)
 
 	authHandler := handlers.NewAuthHandler(authService)
-	sendCoinHandler := handlers.NewSendCoinHandler(coinService, userRepo)
+	sendCoinHandler := handlers.NewSendCoinHandler(transferService, allowanceService, teamService, userRepo)
 	buyHandler := handlers.NewBuyHandler(inventoryService, userRepo, itemRepo)
 	infoHandler := handlers.NewInfoHandler(infoService)
+	orderHandler := handlers.NewOrderHandler(orderService)
+	returnHandler := handlers.NewReturnHandler(returnService)
+	adminHandler := handlers.NewAdminHandler(adminService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService, userRepo)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo)
+	promoHandler := handlers.NewPromoHandler(promoService)
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	wishlistHandler := handlers.NewWishlistHandler(wishlistService)
+	preorderHandler := handlers.NewPreorderHandler(preorderService)
+	auctionHandler := handlers.NewAuctionHandler(auctionService)
+	raffleHandler := handlers.NewRaffleHandler(raffleService)
+	poolHandler := handlers.NewPoolHandler(poolService)
+	coinRequestHandler := handlers.NewCoinRequestHandler(coinRequestService)
+	transferHandler := handlers.NewTransferHandler(transferService)
+	scheduledTransferHandler := handlers.NewScheduledTransferHandler(scheduledTransferService)
+	issuanceHandler := handlers.NewIssuanceHandler(issuanceService)
+	transferLimitHandler := handlers.NewTransferLimitHandler(transferLimitService)
+	fraudHandler := handlers.NewFraudHandler(fraudService)
+	reversalHandler := handlers.NewReversalHandler(reversalService)
+	auditHandler := handlers.NewAuditHandler(auditService)
+	teamHandler := handlers.NewTeamHandler(teamService)
+	tenantHandler := handlers.NewTenantHandler()
 
 	e := echo.New()
 
+	e.Use(middleware.RequestID())
+
 	e.Use(middleware.Logger())
 
 	e.Use(middleware.Recover())
@@ -65,15 +163,114 @@ func main() {
 			http.MethodDelete},
 	}))
 
+	e.Use(mw.NewTenantMiddleware(tenantRepo, cfg.JWTSecret))
+
 	e.POST("/api/auth", authHandler.Auth)
+	e.GET("/api/tenant", tenantHandler.Current)
 
 	authGroup := e.Group("")
 	authMiddleware := mw.NewAuthMiddleware(userRepo, cfg.JWTSecret)
 	authGroup.Use(authMiddleware)
 
 	authGroup.POST("/api/sendCoin", sendCoinHandler.SendCoin)
+	authGroup.POST("/api/sendCoin/batch", sendCoinHandler.SendCoinBatch)
 	authGroup.POST("/api/buy/:item", buyHandler.Buy)
+	authGroup.POST("/api/gift/:item", buyHandler.Gift)
+	authGroup.POST("/api/inventory/transfer", inventoryHandler.Transfer)
 	authGroup.GET("/api/info", infoHandler.Info)
+	authGroup.GET("/api/items", catalogHandler.List)
+	authGroup.GET("/api/wishlist", wishlistHandler.List)
+	authGroup.POST("/api/wishlist", wishlistHandler.Add)
+	authGroup.DELETE("/api/wishlist/:item", wishlistHandler.Remove)
+	authGroup.GET("/api/preorders", preorderHandler.List)
+	authGroup.POST("/api/preorders/:item", preorderHandler.Place)
+	authGroup.POST("/api/preorders/:id/cancel", preorderHandler.Cancel)
+	authGroup.GET("/api/auctions", auctionHandler.List)
+	authGroup.POST("/api/auctions/:id/bid", auctionHandler.Bid)
+	authGroup.GET("/api/raffles", raffleHandler.List)
+	authGroup.POST("/api/raffles/:id/tickets", raffleHandler.BuyTickets)
+	authGroup.GET("/api/pools", poolHandler.List)
+	authGroup.POST("/api/pools", poolHandler.Create)
+	authGroup.POST("/api/pools/:id/contribute", poolHandler.Contribute)
+	authGroup.GET("/api/coin-requests", coinRequestHandler.List)
+	authGroup.POST("/api/coin-requests", coinRequestHandler.Create)
+	authGroup.POST("/api/coin-requests/:id/accept", coinRequestHandler.Accept)
+	authGroup.POST("/api/coin-requests/:id/decline", coinRequestHandler.Decline)
+	authGroup.GET("/api/transfers/pending", transferHandler.Pending)
+	authGroup.POST("/api/transfers/:id/accept", transferHandler.Accept)
+	authGroup.POST("/api/transfers/:id/decline", transferHandler.Decline)
+	authGroup.PUT("/api/settings/transfers", transferHandler.SetSettings)
+	authGroup.GET("/api/scheduled-transfers", scheduledTransferHandler.List)
+	authGroup.POST("/api/scheduled-transfers", scheduledTransferHandler.Create)
+	authGroup.DELETE("/api/scheduled-transfers/:id", scheduledTransferHandler.Cancel)
+	authGroup.GET("/api/scheduled-transfers/:id/runs", scheduledTransferHandler.Runs)
+	authGroup.GET("/api/orders", orderHandler.List)
+	authGroup.PUT("/api/orders/:id/delivery", orderHandler.SetDelivery)
+	authGroup.POST("/api/orders/:id/return", returnHandler.RequestReturn)
+	authGroup.POST("/api/notifications/read", notificationHandler.MarkRead)
+	authGroup.GET("/api/teams", teamHandler.List)
+	authGroup.GET("/api/teams/leaderboard", teamHandler.Leaderboard)
+	authGroup.GET("/api/teams/:id", teamHandler.Get)
+	authGroup.PUT("/api/teams/:id/members/:username", teamHandler.SetMember)
+	authGroup.DELETE("/api/teams/:id/members/:username", teamHandler.RemoveMember)
+	authGroup.POST("/api/teams/:id/send", teamHandler.Send)
+	authGroup.POST("/api/teams/:id/pot/payout", teamHandler.PayOut)
+	authGroup.GET("/api/teams/:id/budget", teamHandler.Budget)
+
+	managerGroup := authGroup.Group("", mw.NewRoleMiddleware(userRepo, models.RoleMerchManager, models.RoleAdmin))
+	managerGroup.GET("/api/returns", returnHandler.Pending)
+	managerGroup.POST("/api/returns/:id/approve", returnHandler.Approve)
+	managerGroup.POST("/api/returns/:id/reject", returnHandler.Reject)
+	managerGroup.GET("/api/manager/orders", orderHandler.ByStatus)
+	managerGroup.GET("/api/manager/orders/picklist", orderHandler.PickList)
+	managerGroup.POST("/api/manager/orders/:id/status", orderHandler.UpdateStatus)
+	managerGroup.GET("/api/manager/promo-codes", promoHandler.List)
+	managerGroup.POST("/api/manager/promo-codes", promoHandler.Create)
+	managerGroup.GET("/api/manager/price-rules", catalogHandler.PriceRules)
+	managerGroup.POST("/api/manager/price-rules", catalogHandler.CreatePriceRule)
+	managerGroup.DELETE("/api/manager/price-rules/:id", catalogHandler.DeletePriceRule)
+	managerGroup.GET("/api/manager/items/:item/price-history", catalogHandler.PriceHistory)
+	managerGroup.PUT("/api/manager/items/:item/stock", catalogHandler.SetStock)
+	managerGroup.PUT("/api/manager/items/:item/preorder", catalogHandler.SetPreorder)
+
+	adminGroup := authGroup.Group("", mw.NewRoleMiddleware(userRepo, models.RoleAdmin))
+	adminGroup.PUT("/api/admin/users/:username/role", adminHandler.SetRole)
+	adminGroup.POST("/api/admin/items/:item/archive", adminHandler.ArchiveItem)
+	adminGroup.POST("/api/admin/items/:item/unarchive", adminHandler.UnarchiveItem)
+	adminGroup.POST("/api/admin/auctions", auctionHandler.Create)
+	adminGroup.POST("/api/admin/raffles", raffleHandler.Create)
+	adminGroup.GET("/api/admin/issuances", issuanceHandler.List)
+	adminGroup.POST("/api/admin/issuances", issuanceHandler.Issue)
+	adminGroup.POST("/api/admin/adjustments", issuanceHandler.Adjust)
+	adminGroup.POST("/api/admin/grants", issuanceHandler.Grant)
+	adminGroup.GET("/api/admin/transfer-limits", transferLimitHandler.List)
+	adminGroup.PUT("/api/admin/transfer-limits/users/:username", transferLimitHandler.SetForUser)
+	adminGroup.PUT("/api/admin/transfer-limits/roles/:role", transferLimitHandler.SetForRole)
+	adminGroup.DELETE("/api/admin/transfer-limits/:id", transferLimitHandler.Delete)
+	adminGroup.GET("/api/admin/fraud-flags", fraudHandler.List)
+	adminGroup.POST("/api/admin/fraud-flags/:id/review", fraudHandler.Review)
+	adminGroup.POST("/api/admin/transactions/:id/reverse", reversalHandler.Reverse)
+	adminGroup.GET("/api/admin/reversals", reversalHandler.List)
+	adminGroup.POST("/api/admin/reversals/:id/approve", reversalHandler.Approve)
+	adminGroup.POST("/api/admin/reversals/:id/reject", reversalHandler.Reject)
+	adminGroup.GET("/api/admin/audit", auditHandler.List)
+	adminGroup.GET("/api/admin/audit/verify", auditHandler.Verify)
+	adminGroup.POST("/api/admin/teams", teamHandler.Create)
+	adminGroup.PUT("/api/admin/teams/:id/budget", teamHandler.SetBudget)
+
+	jobCtx, stopJobs := context.WithCancel(repository.WithAllTenants(context.Background()))
+	defer stopJobs()
+
+	jobInterval := time.Duration(cfg.JobIntervalSeconds) * time.Second
+	go jobs.Every(jobCtx, "close auctions", jobInterval, auctionService.CloseExpired)
+	go jobs.Every(jobCtx, "draw raffles", jobInterval, raffleService.DrawClosed)
+	go jobs.Every(jobCtx, "expire pools", jobInterval, poolService.ExpireOverdue)
+	go jobs.Every(jobCtx, "expire coin requests", jobInterval, coinRequestService.ExpireOverdue)
+	go jobs.Every(jobCtx, "return expired transfers", jobInterval, transferService.ReturnExpired)
+	go jobs.Every(jobCtx, "run scheduled transfers", jobInterval, scheduledTransferService.RunDue)
+	go jobs.Every(jobCtx, "refill giving allowances", jobInterval, allowanceService.Refill)
+	go jobs.Every(jobCtx, "expire coins", jobInterval, coinExpiryService.ExpireDue)
+	go jobs.Every(jobCtx, "detect fraud", jobInterval, fraudService.Scan)
 
 	go func() {
 		addr := fmt.Sprintf(":%s", cfg.ServerPort)
@@ -86,6 +283,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10)
 	defer cancel()
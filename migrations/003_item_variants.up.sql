@@ -0,0 +1,37 @@
+-- Создание таблицы item_variants --
+CREATE TABLE IF NOT EXISTS item_variants (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    size VARCHAR(32) NOT NULL DEFAULT '',
+    color VARCHAR(32) NOT NULL DEFAULT '',
+    price INT,
+    stock INT,
+    UNIQUE(item_id, size, color)
+);
+
+-- Вариант по умолчанию для каждого товара --
+INSERT INTO item_variants (item_id)
+SELECT id FROM items;
+
+-- Инвентарь хранится по вариантам --
+ALTER TABLE user_inventory ADD COLUMN variant_id INT REFERENCES item_variants(id) ON DELETE CASCADE;
+UPDATE user_inventory ui
+   SET variant_id = v.id
+  FROM item_variants v
+ WHERE v.item_id = ui.item_id AND v.size = '' AND v.color = '';
+ALTER TABLE user_inventory ALTER COLUMN variant_id SET NOT NULL;
+ALTER TABLE user_inventory DROP CONSTRAINT user_inventory_user_id_item_id_key;
+ALTER TABLE user_inventory ADD CONSTRAINT user_inventory_user_id_variant_id_key UNIQUE(user_id, variant_id);
+
+-- Создание таблицы orders --
+CREATE TABLE IF NOT EXISTS orders (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    item_id INT REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT REFERENCES item_variants(id) ON DELETE CASCADE,
+    transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    unit_price INT NOT NULL,
+    total INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
@@ -0,0 +1,25 @@
+-- Роли пользователей: employee, merch-manager, admin --
+ALTER TABLE users ADD COLUMN role VARCHAR(32) DEFAULT 'employee' NOT NULL;
+
+-- Тип транзакции и ссылка на исходную транзакцию --
+ALTER TABLE transactions ADD COLUMN type VARCHAR(32) DEFAULT 'transfer' NOT NULL;
+ALTER TABLE transactions ADD COLUMN reference_id INT REFERENCES transactions(id) ON DELETE SET NULL;
+UPDATE transactions SET type = 'purchase' WHERE receiver_id = -1;
+
+-- Количество возвращённых единиц заказа --
+ALTER TABLE orders ADD COLUMN refunded_quantity INT DEFAULT 0 NOT NULL;
+
+-- Создание таблицы returns --
+CREATE TABLE IF NOT EXISTS returns (
+    id SERIAL PRIMARY KEY,
+    order_id INT REFERENCES orders(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    reason TEXT DEFAULT '' NOT NULL,
+    status VARCHAR(16) DEFAULT 'requested' NOT NULL,
+    reviewer_id INT REFERENCES users(id) ON DELETE SET NULL,
+    refund_amount INT DEFAULT 0 NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP
+);
@@ -0,0 +1,17 @@
+-- Получатель и сообщение заказа (подарки) --
+ALTER TABLE orders ADD COLUMN recipient_id INT REFERENCES users(id) ON DELETE CASCADE;
+UPDATE orders SET recipient_id = user_id;
+ALTER TABLE orders ALTER COLUMN recipient_id SET NOT NULL;
+ALTER TABLE orders ADD COLUMN message TEXT DEFAULT '' NOT NULL;
+
+-- Создание таблицы notifications --
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(32) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
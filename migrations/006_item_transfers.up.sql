@@ -0,0 +1,14 @@
+-- Архивные товары нельзя купить, но можно передать --
+ALTER TABLE items ADD COLUMN archived_at TIMESTAMP;
+
+-- Создание таблицы item_transfers --
+CREATE TABLE IF NOT EXISTS item_transfers (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT REFERENCES transactions(id) ON DELETE CASCADE,
+    sender_id INT REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id INT REFERENCES users(id) ON DELETE CASCADE,
+    item_id INT REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT REFERENCES item_variants(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
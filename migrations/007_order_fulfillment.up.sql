@@ -0,0 +1,8 @@
+-- Статус выполнения заказа и способ получения --
+ALTER TABLE orders ADD COLUMN status VARCHAR(32) DEFAULT 'placed' NOT NULL;
+ALTER TABLE orders ADD COLUMN delivery_method VARCHAR(16) DEFAULT 'pickup' NOT NULL;
+ALTER TABLE orders ADD COLUMN office VARCHAR(255) DEFAULT '' NOT NULL;
+ALTER TABLE orders ADD COLUMN shipping_address TEXT DEFAULT '' NOT NULL;
+ALTER TABLE orders ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS orders_status_idx ON orders (status);
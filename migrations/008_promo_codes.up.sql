@@ -0,0 +1,40 @@
+-- Категории товаров --
+ALTER TABLE items ADD COLUMN category VARCHAR(64) DEFAULT '' NOT NULL;
+UPDATE items SET category = 'apparel' WHERE name IN ('t-shirt', 'hoody', 'pink-hoody', 'socks');
+UPDATE items SET category = 'accessories' WHERE name IN ('cup', 'pen', 'powerbank', 'umbrella', 'wallet');
+UPDATE items SET category = 'books' WHERE name = 'book';
+
+-- Создание таблицы promo_codes --
+CREATE TABLE IF NOT EXISTS promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) UNIQUE NOT NULL,
+    discount_type VARCHAR(16) NOT NULL,
+    discount_value INT NOT NULL,
+    item_ids INT[] DEFAULT '{}' NOT NULL,
+    categories TEXT[] DEFAULT '{}' NOT NULL,
+    max_uses INT,
+    per_user_limit INT,
+    used_count INT DEFAULT 0 NOT NULL,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Цена по прайсу и скидка в заказе --
+ALTER TABLE orders ADD COLUMN list_total INT;
+UPDATE orders SET list_total = total;
+ALTER TABLE orders ALTER COLUMN list_total SET NOT NULL;
+ALTER TABLE orders ADD COLUMN discount INT DEFAULT 0 NOT NULL;
+ALTER TABLE orders ADD COLUMN promo_code_id INT REFERENCES promo_codes(id) ON DELETE SET NULL;
+
+-- Создание таблицы promo_redemptions --
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    id SERIAL PRIMARY KEY,
+    promo_code_id INT NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    discount INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_code_user_idx ON promo_redemptions (promo_code_id, user_id);
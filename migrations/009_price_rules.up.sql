@@ -0,0 +1,33 @@
+-- Создание таблицы price_rules --
+CREATE TABLE IF NOT EXISTS price_rules (
+    id SERIAL PRIMARY KEY,
+    item_id INT REFERENCES items(id) ON DELETE CASCADE,
+    category VARCHAR(64),
+    price INT,
+    discount_percent INT,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK ((item_id IS NULL) <> (category IS NULL)),
+    CHECK ((price IS NULL) <> (discount_percent IS NULL)),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS price_rules_window_idx ON price_rules (starts_at, ends_at);
+
+-- Создание таблицы price_history --
+CREATE TABLE IF NOT EXISTS price_history (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT NOT NULL REFERENCES item_variants(id) ON DELETE CASCADE,
+    original_price INT NOT NULL,
+    sale_price INT NOT NULL,
+    price_rule_id INT REFERENCES price_rules(id) ON DELETE SET NULL,
+    promo_discount INT DEFAULT 0 NOT NULL,
+    charged INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS price_history_item_idx ON price_history (item_id);
@@ -0,0 +1,10 @@
+-- Создание таблицы wishlist --
+CREATE TABLE IF NOT EXISTS wishlist (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    UNIQUE(user_id, item_id)
+);
+
+CREATE INDEX IF NOT EXISTS wishlist_item_idx ON wishlist (item_id);
@@ -0,0 +1,21 @@
+-- Предзаказ товаров --
+ALTER TABLE items ADD COLUMN preorder_enabled BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- Создание таблицы preorders --
+CREATE TABLE IF NOT EXISTS preorders (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT NOT NULL REFERENCES item_variants(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    original_price INT NOT NULL,
+    unit_price INT NOT NULL,
+    amount INT NOT NULL,
+    status VARCHAR(16) DEFAULT 'placed' NOT NULL,
+    order_id INT REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS preorders_variant_status_idx ON preorders (variant_id, status);
+CREATE INDEX IF NOT EXISTS preorders_user_status_idx ON preorders (user_id, status);
@@ -0,0 +1,33 @@
+-- Создание таблицы auctions --
+CREATE TABLE IF NOT EXISTS auctions (
+    id SERIAL PRIMARY KEY,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT NOT NULL REFERENCES item_variants(id) ON DELETE CASCADE,
+    start_price INT NOT NULL,
+    increment INT NOT NULL,
+    extend_by_seconds INT DEFAULT 120 NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    status VARCHAR(16) DEFAULT 'open' NOT NULL,
+    highest_bid INT,
+    highest_bidder_id INT REFERENCES users(id) ON DELETE SET NULL,
+    winner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    order_id INT REFERENCES orders(id) ON DELETE SET NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS auctions_status_ends_at_idx ON auctions (status, ends_at);
+
+-- Создание таблицы bids --
+CREATE TABLE IF NOT EXISTS bids (
+    id SERIAL PRIMARY KEY,
+    auction_id INT NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    status VARCHAR(16) DEFAULT 'active' NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS bids_auction_idx ON bids (auction_id);
+CREATE INDEX IF NOT EXISTS bids_user_status_idx ON bids (user_id, status);
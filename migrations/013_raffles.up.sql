@@ -0,0 +1,35 @@
+-- Создание таблицы raffles --
+CREATE TABLE IF NOT EXISTS raffles (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    ticket_price INT NOT NULL,
+    max_tickets_per_user INT DEFAULT 0 NOT NULL,
+    closes_at TIMESTAMP NOT NULL,
+    prize_item_id INT REFERENCES items(id) ON DELETE SET NULL,
+    prize_variant_id INT REFERENCES item_variants(id) ON DELETE SET NULL,
+    prize_coins INT DEFAULT 0 NOT NULL,
+    seed_hash VARCHAR(64) NOT NULL,
+    seed VARCHAR(64) NOT NULL,
+    status VARCHAR(16) DEFAULT 'open' NOT NULL,
+    tickets_sold INT DEFAULT 0 NOT NULL,
+    winning_ticket INT,
+    winner_id INT REFERENCES users(id) ON DELETE SET NULL,
+    created_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    drawn_at TIMESTAMP,
+    CHECK ((prize_variant_id IS NULL) <> (prize_coins = 0))
+);
+
+CREATE INDEX IF NOT EXISTS raffles_status_closes_at_idx ON raffles (status, closes_at);
+
+-- Создание таблицы raffle_tickets --
+CREATE TABLE IF NOT EXISTS raffle_tickets (
+    id SERIAL PRIMARY KEY,
+    raffle_id INT NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    quantity INT NOT NULL,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS raffle_tickets_raffle_user_idx ON raffle_tickets (raffle_id, user_id);
@@ -0,0 +1,31 @@
+-- Создание таблицы pools --
+CREATE TABLE IF NOT EXISTS pools (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    item_id INT NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INT NOT NULL REFERENCES item_variants(id) ON DELETE CASCADE,
+    target INT NOT NULL,
+    raised INT DEFAULT 0 NOT NULL,
+    deadline TIMESTAMP NOT NULL,
+    status VARCHAR(16) DEFAULT 'open' NOT NULL,
+    creator_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id INT REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS pools_status_deadline_idx ON pools (status, deadline);
+
+-- Создание таблицы pool_contributions --
+CREATE TABLE IF NOT EXISTS pool_contributions (
+    id SERIAL PRIMARY KEY,
+    pool_id INT NOT NULL REFERENCES pools(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    status VARCHAR(16) DEFAULT 'held' NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS pool_contributions_pool_idx ON pool_contributions (pool_id);
+CREATE INDEX IF NOT EXISTS pool_contributions_user_status_idx ON pool_contributions (user_id, status);
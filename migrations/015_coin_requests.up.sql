@@ -0,0 +1,15 @@
+-- Создание таблицы coin_requests --
+CREATE TABLE IF NOT EXISTS coin_requests (
+    id SERIAL PRIMARY KEY,
+    requester_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    payer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    reason TEXT NOT NULL,
+    status VARCHAR(16) DEFAULT 'pending' NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS coin_requests_payer_status_idx ON coin_requests (payer_id, status);
+CREATE INDEX IF NOT EXISTS coin_requests_requester_status_idx ON coin_requests (requester_id, status);
@@ -0,0 +1,19 @@
+-- Настройка приема переводов --
+ALTER TABLE users ADD COLUMN IF NOT EXISTS auto_accept_transfers BOOLEAN DEFAULT TRUE NOT NULL;
+
+-- Создание таблицы pending_transfers --
+CREATE TABLE IF NOT EXISTS pending_transfers (
+    id SERIAL PRIMARY KEY,
+    sender_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    status VARCHAR(16) DEFAULT 'pending' NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS pending_transfers_receiver_status_idx ON pending_transfers (receiver_id, status);
+CREATE INDEX IF NOT EXISTS pending_transfers_sender_status_idx ON pending_transfers (sender_id, status);
+CREATE INDEX IF NOT EXISTS pending_transfers_status_expires_at_idx ON pending_transfers (status, expires_at);
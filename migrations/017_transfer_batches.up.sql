@@ -0,0 +1,14 @@
+-- Создание таблицы transfer_batches --
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    id SERIAL PRIMARY KEY,
+    sender_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    total INT NOT NULL,
+    recipients INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Переводы пакета группируются по batch_id --
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS batch_id INT REFERENCES transfer_batches(id) ON DELETE SET NULL;
+ALTER TABLE pending_transfers ADD COLUMN IF NOT EXISTS batch_id INT REFERENCES transfer_batches(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_batch_idx ON transactions (batch_id);
@@ -0,0 +1,30 @@
+-- Создание таблицы scheduled_transfers --
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id SERIAL PRIMARY KEY,
+    sender_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    receiver_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0),
+    cron VARCHAR(64),
+    catch_up VARCHAR(16) DEFAULT 'latest' NOT NULL,
+    next_run_at TIMESTAMP,
+    status VARCHAR(16) DEFAULT 'active' NOT NULL,
+    failures INT DEFAULT 0 NOT NULL,
+    last_run_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_transfers_status_next_run_idx ON scheduled_transfers (status, next_run_at);
+CREATE INDEX IF NOT EXISTS scheduled_transfers_sender_idx ON scheduled_transfers (sender_id);
+
+-- Создание таблицы scheduled_transfer_runs --
+CREATE TABLE IF NOT EXISTS scheduled_transfer_runs (
+    id SERIAL PRIMARY KEY,
+    schedule_id INT NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    scheduled_for TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_transfer_runs_schedule_idx ON scheduled_transfer_runs (schedule_id);
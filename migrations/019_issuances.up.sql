@@ -0,0 +1,22 @@
+-- Счёт эмиссии: все выпущенные монеты списываются с него --
+INSERT INTO users (id, username, password_hash, balance)
+VALUES (-2, 'issuance', '', 0)
+ON CONFLICT (id) DO NOTHING;
+
+-- Создание таблицы issuances --
+CREATE TABLE IF NOT EXISTS issuances (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(16) NOT NULL,
+    reason TEXT NOT NULL,
+    admin_id INT REFERENCES users(id) ON DELETE SET NULL,
+    period VARCHAR(7),
+    total INT NOT NULL,
+    recipients INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Ежемесячное начисление проводится один раз за период --
+CREATE UNIQUE INDEX IF NOT EXISTS issuances_grant_period_idx ON issuances (period) WHERE kind = 'grant';
+
+-- Проводки эмиссии ссылаются на операцию --
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS issuance_id INT REFERENCES issuances(id) ON DELETE SET NULL;
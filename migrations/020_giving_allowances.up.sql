@@ -0,0 +1,4 @@
+-- Бюджет на подарки: монеты, которые можно только отправить другим --
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowance INT DEFAULT 0 NOT NULL;
+-- Месяц (YYYY-MM), за который начислен бюджет; в конце месяца остаток сгорает --
+ALTER TABLE users ADD COLUMN IF NOT EXISTS allowance_period VARCHAR(7);
@@ -0,0 +1,24 @@
+-- Счёт, на который списываются сгоревшие монеты --
+INSERT INTO users (id, username, password_hash, balance)
+VALUES (-3, 'forfeiture', '', 0)
+ON CONFLICT (id) DO NOTHING;
+
+-- Создание таблицы coin_lots: партии монет с датой получения и источником --
+CREATE TABLE IF NOT EXISTS coin_lots (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    remaining INT NOT NULL,
+    source VARCHAR(32) NOT NULL,
+    earned_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP DEFAULT (CURRENT_TIMESTAMP + INTERVAL '12 months') NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS coin_lots_user_idx ON coin_lots (user_id, earned_at, id) WHERE remaining > 0;
+CREATE INDEX IF NOT EXISTS coin_lots_expiry_idx ON coin_lots (expires_at) WHERE remaining > 0;
+
+-- Текущие балансы становятся первой партией монет --
+INSERT INTO coin_lots (user_id, amount, remaining, source)
+SELECT id, balance, balance, 'opening'
+FROM users
+WHERE id > 0 AND balance > 0;
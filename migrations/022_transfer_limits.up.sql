@@ -0,0 +1,16 @@
+-- Создание таблицы transfer_limit_overrides --
+-- Переопределение лимитов для пользователя или роли; NULL означает лимит по умолчанию, 0 - без лимита --
+CREATE TABLE IF NOT EXISTS transfer_limit_overrides (
+    id SERIAL PRIMARY KEY,
+    user_id INT UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(32) UNIQUE,
+    max_per_transfer INT,
+    max_per_day INT,
+    max_per_recipient_week INT,
+    max_recipients_per_hour INT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CHECK ((user_id IS NULL) <> (role IS NULL))
+);
+
+-- Быстрый подсчёт недавних переводов отправителя --
+CREATE INDEX IF NOT EXISTS transactions_sender_time_idx ON transactions (sender_id, timestamp);
@@ -0,0 +1,25 @@
+-- Дата регистрации пользователя --
+-- Аккаунты, созданные до миграции, считаются давними --
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+UPDATE users SET created_at = TIMESTAMP 'epoch' WHERE created_at IS NULL;
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE users ALTER COLUMN created_at SET NOT NULL;
+
+-- Создание таблицы fraud_flags --
+-- Подозрительные движения монет, ожидающие проверки администратором --
+CREATE TABLE IF NOT EXISTS fraud_flags (
+    id SERIAL PRIMARY KEY,
+    rule VARCHAR(32) NOT NULL,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    counterparty_id INT REFERENCES users(id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    details TEXT NOT NULL,
+    pending_transfer_id INT REFERENCES pending_transfers(id) ON DELETE SET NULL,
+    status VARCHAR(16) DEFAULT 'open' NOT NULL,
+    reviewed_by INT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS fraud_flags_status_idx ON fraud_flags (status, id);
+CREATE INDEX IF NOT EXISTS fraud_flags_rule_user_idx ON fraud_flags (rule, user_id, created_at);
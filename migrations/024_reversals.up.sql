@@ -0,0 +1,20 @@
+-- Сторнирующая операция ссылается на исходную --
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reversal_of INT UNIQUE REFERENCES transactions(id);
+
+-- Создание таблицы reversals --
+-- Запросы на сторно; уход в минус требует одобрения второго администратора --
+CREATE TABLE IF NOT EXISTS reversals (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    allow_negative BOOLEAN DEFAULT FALSE NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    requested_by INT REFERENCES users(id) ON DELETE SET NULL,
+    approved_by INT REFERENCES users(id) ON DELETE SET NULL,
+    reversal_transaction_id INT REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    resolved_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS reversals_open_transaction_idx
+    ON reversals (transaction_id) WHERE status IN ('pending', 'completed');
@@ -0,0 +1,37 @@
+-- Создание таблицы audit_log --
+-- Журнал только для добавления; каждая запись хранит хеш предыдущей --
+-- actor_id без внешнего ключа, чтобы удаление пользователя не меняло записи --
+CREATE TABLE IF NOT EXISTS audit_log (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(64) NOT NULL,
+    actor_id INT,
+    actor_name VARCHAR(255),
+    target_type VARCHAR(32) DEFAULT '' NOT NULL,
+    target_id VARCHAR(255) DEFAULT '' NOT NULL,
+    before JSON,
+    after JSON,
+    request_id VARCHAR(64) DEFAULT '' NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_action_idx ON audit_log (action, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_target_idx ON audit_log (target_type, target_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_request_idx ON audit_log (request_id);
+
+-- Запрет изменения и удаления записей журнала --
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate
+    BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,31 @@
+-- Счёт командных копилок: монеты в копилках числятся на нём --
+INSERT INTO users (id, username, password_hash, balance)
+VALUES (-4, 'team-pots', '', 0)
+ON CONFLICT (id) DO NOTHING;
+
+-- Создание таблицы teams --
+-- budget — сколько монет менеджеры могут выдать из копилки за месяц, 0 — без ограничения --
+CREATE TABLE IF NOT EXISTS teams (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    department VARCHAR(255) DEFAULT '' NOT NULL,
+    pot INT DEFAULT 0 NOT NULL CHECK (pot >= 0),
+    budget INT DEFAULT 0 NOT NULL CHECK (budget >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Создание таблицы team_members --
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id INT REFERENCES teams(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) DEFAULT 'member' NOT NULL,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS team_members_user_idx ON team_members (user_id);
+
+-- Проводки копилки ссылаются на команду --
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS team_id INT REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS transactions_team_idx ON transactions (team_id, timestamp) WHERE team_id IS NOT NULL;
@@ -0,0 +1,31 @@
+-- Создание таблицы tenants --
+-- host — домен магазина арендатора, по нему определяется арендатор запроса --
+CREATE TABLE IF NOT EXISTS tenants (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(64) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    host VARCHAR(255) UNIQUE,
+    currency_name VARCHAR(64) DEFAULT 'coins' NOT NULL,
+    starting_balance INT DEFAULT 1000 NOT NULL CHECK (starting_balance >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+-- Арендатор по умолчанию: ему принадлежат все существующие данные --
+INSERT INTO tenants (id, slug, name)
+VALUES (1, 'default', 'Merch Store')
+ON CONFLICT (id) DO NOTHING;
+
+SELECT setval('tenants_id_seq', GREATEST((SELECT MAX(id) FROM tenants), 1));
+
+-- Пользователи, товары и проводки принадлежат арендатору --
+ALTER TABLE users ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+ALTER TABLE items ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+-- Имена пользователей и товаров уникальны в пределах арендатора --
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_key;
+ALTER TABLE users ADD CONSTRAINT users_tenant_id_username_key UNIQUE (tenant_id, username);
+ALTER TABLE items DROP CONSTRAINT IF EXISTS items_name_key;
+ALTER TABLE items ADD CONSTRAINT items_tenant_id_name_key UNIQUE (tenant_id, name);
+
+CREATE INDEX IF NOT EXISTS transactions_tenant_idx ON transactions (tenant_id, timestamp);
@@ -0,0 +1,12 @@
+-- Создание таблицы coin_lot_holds: из каких партий взяты удержанные монеты --
+-- hold_type и hold_id указывают на удержание: ставку, предзаказ, перевод, взнос или заказ --
+CREATE TABLE IF NOT EXISTS coin_lot_holds (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hold_type VARCHAR(32) NOT NULL,
+    hold_id INT NOT NULL,
+    lot_id INT NOT NULL REFERENCES coin_lots(id) ON DELETE CASCADE,
+    amount INT NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX IF NOT EXISTS coin_lot_holds_hold_idx ON coin_lot_holds (user_id, hold_type, hold_id);
@@ -0,0 +1,11 @@
+-- Операции эмиссии принадлежат арендатору --
+ALTER TABLE issuances ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+UPDATE issuances iss
+   SET tenant_id = u.tenant_id
+  FROM users u
+ WHERE u.id = iss.admin_id;
+
+-- Ежемесячное начисление проводится один раз за период у каждого арендатора --
+DROP INDEX IF EXISTS issuances_grant_period_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS issuances_grant_period_idx ON issuances (tenant_id, period) WHERE kind = 'grant';
@@ -0,0 +1,32 @@
+-- Розыгрыши, команды и журнал аудита принадлежат арендатору --
+-- Аукционы, сборы, заказы и флаги проверки относятся к арендатору через товары и пользователей --
+ALTER TABLE raffles ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+ALTER TABLE audit_log ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+UPDATE raffles r
+   SET tenant_id = u.tenant_id
+  FROM users u
+ WHERE u.id = r.created_by;
+
+UPDATE teams t
+   SET tenant_id = u.tenant_id
+  FROM team_members m
+  JOIN users u ON u.id = m.user_id
+ WHERE m.team_id = t.id;
+
+-- Журнал только для добавления: запрет изменения снимается на время переноса --
+ALTER TABLE audit_log DISABLE TRIGGER audit_log_no_update;
+
+UPDATE audit_log a
+   SET tenant_id = u.tenant_id
+  FROM users u
+ WHERE u.id = a.actor_id;
+
+ALTER TABLE audit_log ENABLE TRIGGER audit_log_no_update;
+
+-- Названия команд уникальны в пределах арендатора --
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_name_key;
+ALTER TABLE teams ADD CONSTRAINT teams_tenant_id_name_key UNIQUE (tenant_id, name);
+
+CREATE INDEX IF NOT EXISTS audit_log_tenant_idx ON audit_log (tenant_id, id);
@@ -0,0 +1,10 @@
+-- Правила цен принадлежат арендатору --
+ALTER TABLE price_rules ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+UPDATE price_rules pr
+   SET tenant_id = i.tenant_id
+  FROM items i
+ WHERE i.id = pr.item_id;
+
+DROP INDEX IF EXISTS price_rules_window_idx;
+CREATE INDEX IF NOT EXISTS price_rules_window_idx ON price_rules (tenant_id, starts_at, ends_at);
@@ -0,0 +1,6 @@
+-- Промокоды принадлежат арендатору --
+ALTER TABLE promo_codes ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+-- Код уникален в пределах арендатора --
+ALTER TABLE promo_codes DROP CONSTRAINT IF EXISTS promo_codes_code_key;
+ALTER TABLE promo_codes ADD CONSTRAINT promo_codes_tenant_id_code_key UNIQUE (tenant_id, code);
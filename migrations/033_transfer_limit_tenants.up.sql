@@ -0,0 +1,11 @@
+-- Переопределения лимитов принадлежат арендатору --
+ALTER TABLE transfer_limit_overrides ADD COLUMN IF NOT EXISTS tenant_id INT DEFAULT 1 NOT NULL REFERENCES tenants(id);
+
+UPDATE transfer_limit_overrides o
+   SET tenant_id = u.tenant_id
+  FROM users u
+ WHERE u.id = o.user_id;
+
+-- Переопределение для роли задаётся отдельно у каждого арендатора --
+ALTER TABLE transfer_limit_overrides DROP CONSTRAINT IF EXISTS transfer_limit_overrides_role_key;
+ALTER TABLE transfer_limit_overrides ADD CONSTRAINT transfer_limit_overrides_tenant_id_role_key UNIQUE (tenant_id, role);
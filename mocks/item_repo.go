@@ -7,6 +7,8 @@ import (
 
 	models "github.com/gratefultolord/merch-store/internal/models"
 	mock "github.com/stretchr/testify/mock"
+
+	sqlx "github.com/jmoiron/sqlx"
 )
 
 // ItemRepo is an autogenerated mock type for the ItemRepo type
@@ -14,6 +16,34 @@ type ItemRepo struct {
 	mock.Mock
 }
 
+// DecrementStock provides a mock function with given fields: ctx, tx, variantID, quantity
+func (_m *ItemRepo) DecrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) (bool, error) {
+	ret := _m.Called(ctx, tx, variantID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DecrementStock")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) (bool, error)); ok {
+		return rf(ctx, tx, variantID, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) bool); ok {
+		r0 = rf(ctx, tx, variantID, quantity)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int, int) error); ok {
+		r1 = rf(ctx, tx, variantID, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetAll provides a mock function with given fields: ctx
 func (_m *ItemRepo) GetAll(ctx context.Context) ([]models.Item, error) {
 	ret := _m.Called(ctx)
@@ -74,6 +104,150 @@ func (_m *ItemRepo) GetItemByName(ctx context.Context, name string) (*models.Ite
 	return r0, r1
 }
 
+// GetStock provides a mock function with given fields: ctx, tx, variantID
+func (_m *ItemRepo) GetStock(ctx context.Context, tx *sqlx.Tx, variantID int) (*int, error) {
+	ret := _m.Called(ctx, tx, variantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStock")
+	}
+
+	var r0 *int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int) (*int, error)); ok {
+		return rf(ctx, tx, variantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int) *int); ok {
+		r0 = rf(ctx, tx, variantID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int) error); ok {
+		r1 = rf(ctx, tx, variantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVariants provides a mock function with given fields: ctx, itemID
+func (_m *ItemRepo) GetVariants(ctx context.Context, itemID int) ([]models.ItemVariant, error) {
+	ret := _m.Called(ctx, itemID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVariants")
+	}
+
+	var r0 []models.ItemVariant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]models.ItemVariant, error)); ok {
+		return rf(ctx, itemID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ItemVariant); ok {
+		r0 = rf(ctx, itemID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ItemVariant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, itemID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IncrementStock provides a mock function with given fields: ctx, tx, variantID, quantity
+func (_m *ItemRepo) IncrementStock(ctx context.Context, tx *sqlx.Tx, variantID int, quantity int) error {
+	ret := _m.Called(ctx, tx, variantID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IncrementStock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) error); ok {
+		r0 = rf(ctx, tx, variantID, quantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetArchived provides a mock function with given fields: ctx, tx, itemID, archived
+func (_m *ItemRepo) SetArchived(ctx context.Context, tx *sqlx.Tx, itemID int, archived bool) error {
+	ret := _m.Called(ctx, tx, itemID, archived)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetArchived")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, bool) error); ok {
+		r0 = rf(ctx, tx, itemID, archived)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPreorderEnabled provides a mock function with given fields: ctx, tx, itemID, enabled
+func (_m *ItemRepo) SetPreorderEnabled(ctx context.Context, tx *sqlx.Tx, itemID int, enabled bool) error {
+	ret := _m.Called(ctx, tx, itemID, enabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPreorderEnabled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, bool) error); ok {
+		r0 = rf(ctx, tx, itemID, enabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetStock provides a mock function with given fields: ctx, tx, variantID, stock
+func (_m *ItemRepo) SetStock(ctx context.Context, tx *sqlx.Tx, variantID int, stock *int) (*int, error) {
+	ret := _m.Called(ctx, tx, variantID, stock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStock")
+	}
+
+	var r0 *int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, *int) (*int, error)); ok {
+		return rf(ctx, tx, variantID, stock)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, *int) *int); ok {
+		r0 = rf(ctx, tx, variantID, stock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int, *int) error); ok {
+		r1 = rf(ctx, tx, variantID, stock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewItemRepo creates a new instance of ItemRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewItemRepo(t interface {
@@ -34,6 +34,24 @@ func (_m *TransactionRepo) Create(ctx context.Context, tx *sqlx.Tx, transaction
 	return r0
 }
 
+// CreateBatch provides a mock function with given fields: ctx, tx, batch
+func (_m *TransactionRepo) CreateBatch(ctx context.Context, tx *sqlx.Tx, batch *models.TransferBatch) error {
+	ret := _m.Called(ctx, tx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, *models.TransferBatch) error); ok {
+		r0 = rf(ctx, tx, batch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetByUserID provides a mock function with given fields: ctx, userID
 func (_m *TransactionRepo) GetByUserID(ctx context.Context, userID int) ([]models.Transaction, error) {
 	ret := _m.Called(ctx, userID)
@@ -16,17 +16,17 @@ type UserRepo struct {
 	mock.Mock
 }
 
-// AddOrIncrementItemInventory provides a mock function with given fields: ctx, tx, userID, itemID, quantity
-func (_m *UserRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
-	ret := _m.Called(ctx, tx, userID, itemID, quantity)
+// AddOrIncrementItemInventory provides a mock function with given fields: ctx, tx, userID, itemID, variantID, quantity
+func (_m *UserRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error {
+	ret := _m.Called(ctx, tx, userID, itemID, variantID, quantity)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddOrIncrementItemInventory")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int) error); ok {
-		r0 = rf(ctx, tx, userID, itemID, quantity)
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int, int) error); ok {
+		r0 = rf(ctx, tx, userID, itemID, variantID, quantity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -34,17 +34,17 @@ func (_m *UserRepo) AddOrIncrementItemInventory(ctx context.Context, tx *sqlx.Tx
 	return r0
 }
 
-// AddToInventory provides a mock function with given fields: ctx, tx, userID, itemID, quantity
-func (_m *UserRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, quantity int) error {
-	ret := _m.Called(ctx, tx, userID, itemID, quantity)
+// AddToInventory provides a mock function with given fields: ctx, tx, userID, itemID, variantID, quantity
+func (_m *UserRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, variantID int, quantity int) error {
+	ret := _m.Called(ctx, tx, userID, itemID, variantID, quantity)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddToInventory")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int) error); ok {
-		r0 = rf(ctx, tx, userID, itemID, quantity)
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int, int) error); ok {
+		r0 = rf(ctx, tx, userID, itemID, variantID, quantity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -52,9 +52,9 @@ func (_m *UserRepo) AddToInventory(ctx context.Context, tx *sqlx.Tx, userID int,
 	return r0
 }
 
-// CheckInventory provides a mock function with given fields: ctx, tx, userID, itemID, existingQuantity
-func (_m *UserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, existingQuantity *int) error {
-	ret := _m.Called(ctx, tx, userID, itemID, existingQuantity)
+// CheckInventory provides a mock function with given fields: ctx, tx, userID, variantID, existingQuantity
+func (_m *UserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, existingQuantity *int) error {
+	ret := _m.Called(ctx, tx, userID, variantID, existingQuantity)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CheckInventory")
@@ -62,7 +62,7 @@ func (_m *UserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int,
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, *int) error); ok {
-		r0 = rf(ctx, tx, userID, itemID, existingQuantity)
+		r0 = rf(ctx, tx, userID, variantID, existingQuantity)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,17 +70,53 @@ func (_m *UserRepo) CheckInventory(ctx context.Context, tx *sqlx.Tx, userID int,
 	return r0
 }
 
-// Create provides a mock function with given fields: ctx, user
-func (_m *UserRepo) Create(ctx context.Context, user *models.User) error {
-	ret := _m.Called(ctx, user)
+// Create provides a mock function with given fields: ctx, tx, user
+func (_m *UserRepo) Create(ctx context.Context, tx *sqlx.Tx, user *models.User) error {
+	ret := _m.Called(ctx, tx, user)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *models.User) error); ok {
-		r0 = rf(ctx, user)
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, *models.User) error); ok {
+		r0 = rf(ctx, tx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Credit provides a mock function with given fields: ctx, tx, userID, amount, source
+func (_m *UserRepo) Credit(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string) error {
+	ret := _m.Called(ctx, tx, userID, amount, source)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Credit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, string) error); ok {
+		r0 = rf(ctx, tx, userID, amount, source)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Debit provides a mock function with given fields: ctx, tx, userID, amount
+func (_m *UserRepo) Debit(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	ret := _m.Called(ctx, tx, userID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Debit")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) error); ok {
+		r0 = rf(ctx, tx, userID, amount)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -88,6 +124,62 @@ func (_m *UserRepo) Create(ctx context.Context, user *models.User) error {
 	return r0
 }
 
+// GetAllowance provides a mock function with given fields: ctx, userID, period
+func (_m *UserRepo) GetAllowance(ctx context.Context, userID int, period string) (int, error) {
+	ret := _m.Called(ctx, userID, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllowance")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int, error)); ok {
+		return rf(ctx, userID, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int); ok {
+		r0 = rf(ctx, userID, period)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, userID, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAutoAcceptTransfers provides a mock function with given fields: ctx, userID
+func (_m *UserRepo) GetAutoAcceptTransfers(ctx context.Context, userID int) (bool, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAutoAcceptTransfers")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (bool, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByID provides a mock function with given fields: ctx, userID
 func (_m *UserRepo) GetByID(ctx context.Context, userID int) (*models.User, error) {
 	ret := _m.Called(ctx, userID)
@@ -148,6 +240,34 @@ func (_m *UserRepo) GetByUsername(ctx context.Context, username string) (*models
 	return r0, r1
 }
 
+// GetRole provides a mock function with given fields: ctx, userID
+func (_m *UserRepo) GetRole(ctx context.Context, userID int) (string, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRole")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUsernameByID provides a mock function with given fields: ctx, userID
 func (_m *UserRepo) GetUsernameByID(ctx context.Context, userID int) (string, error) {
 	ret := _m.Called(ctx, userID)
@@ -176,6 +296,208 @@ func (_m *UserRepo) GetUsernameByID(ctx context.Context, userID int) (string, er
 	return r0, r1
 }
 
+// Hold provides a mock function with given fields: ctx, tx, userID, amount, hold
+func (_m *UserRepo) Hold(ctx context.Context, tx *sqlx.Tx, userID int, amount int, hold models.CoinHold) error {
+	ret := _m.Called(ctx, tx, userID, amount, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Hold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, models.CoinHold) error); ok {
+		r0 = rf(ctx, tx, userID, amount, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockInventory provides a mock function with given fields: ctx, tx, userID, variantID
+func (_m *UserRepo) LockInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int) (int, error) {
+	ret := _m.Called(ctx, tx, userID, variantID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockInventory")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) (int, error)); ok {
+		return rf(ctx, tx, userID, variantID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) int); ok {
+		r0 = rf(ctx, tx, userID, variantID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int, int) error); ok {
+		r1 = rf(ctx, tx, userID, variantID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MoveHold provides a mock function with given fields: ctx, tx, userID, from, to
+func (_m *UserRepo) MoveHold(ctx context.Context, tx *sqlx.Tx, userID int, from models.CoinHold, to models.CoinHold) error {
+	ret := _m.Called(ctx, tx, userID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MoveHold")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, models.CoinHold, models.CoinHold) error); ok {
+		r0 = rf(ctx, tx, userID, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Overdraw provides a mock function with given fields: ctx, tx, userID, amount
+func (_m *UserRepo) Overdraw(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
+	ret := _m.Called(ctx, tx, userID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Overdraw")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int) error); ok {
+		r0 = rf(ctx, tx, userID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefillAllowances provides a mock function with given fields: ctx, amount, period
+func (_m *UserRepo) RefillAllowances(ctx context.Context, amount int, period string) (int, error) {
+	ret := _m.Called(ctx, amount, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefillAllowances")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) (int, error)); ok {
+		return rf(ctx, amount, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) int); ok {
+		r0 = rf(ctx, amount, period)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
+		r1 = rf(ctx, amount, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: ctx, tx, userID, amount, source, hold
+func (_m *UserRepo) Release(ctx context.Context, tx *sqlx.Tx, userID int, amount int, source string, hold models.CoinHold) error {
+	ret := _m.Called(ctx, tx, userID, amount, source, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, string, models.CoinHold) error); ok {
+		r0 = rf(ctx, tx, userID, amount, source, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveFromInventory provides a mock function with given fields: ctx, tx, userID, variantID, quantity
+func (_m *UserRepo) RemoveFromInventory(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, quantity int) (bool, error) {
+	ret := _m.Called(ctx, tx, userID, variantID, quantity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveFromInventory")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int) (bool, error)); ok {
+		return rf(ctx, tx, userID, variantID, quantity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int) bool); ok {
+		r0 = rf(ctx, tx, userID, variantID, quantity)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int, int, int) error); ok {
+		r1 = rf(ctx, tx, userID, variantID, quantity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetAutoAcceptTransfers provides a mock function with given fields: ctx, userID, autoAccept
+func (_m *UserRepo) SetAutoAcceptTransfers(ctx context.Context, userID int, autoAccept bool) error {
+	ret := _m.Called(ctx, userID, autoAccept)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetAutoAcceptTransfers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, bool) error); ok {
+		r0 = rf(ctx, userID, autoAccept)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SpendAllowance provides a mock function with given fields: ctx, tx, userID, period, amount
+func (_m *UserRepo) SpendAllowance(ctx context.Context, tx *sqlx.Tx, userID int, period string, amount int) (bool, error) {
+	ret := _m.Called(ctx, tx, userID, period, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SpendAllowance")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, string, int) (bool, error)); ok {
+		return rf(ctx, tx, userID, period, amount)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, string, int) bool); ok {
+		r0 = rf(ctx, tx, userID, period, amount)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *sqlx.Tx, int, string, int) error); ok {
+		r1 = rf(ctx, tx, userID, period, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateBalance provides a mock function with given fields: ctx, tx, userID, amount
 func (_m *UserRepo) UpdateBalance(ctx context.Context, tx *sqlx.Tx, userID int, amount int) error {
 	ret := _m.Called(ctx, tx, userID, amount)
@@ -212,9 +534,9 @@ func (_m *UserRepo) UpdateInventory(ctx context.Context, tx *sqlx.Tx, userID int
 	return r0
 }
 
-// UpdateInventoryQuantity provides a mock function with given fields: ctx, tx, userID, itemID, newQuantity
-func (_m *UserRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, itemID int, newQuantity int) error {
-	ret := _m.Called(ctx, tx, userID, itemID, newQuantity)
+// UpdateInventoryQuantity provides a mock function with given fields: ctx, tx, userID, variantID, newQuantity
+func (_m *UserRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, userID int, variantID int, newQuantity int) error {
+	ret := _m.Called(ctx, tx, userID, variantID, newQuantity)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateInventoryQuantity")
@@ -222,7 +544,25 @@ func (_m *UserRepo) UpdateInventoryQuantity(ctx context.Context, tx *sqlx.Tx, us
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, int, int) error); ok {
-		r0 = rf(ctx, tx, userID, itemID, newQuantity)
+		r0 = rf(ctx, tx, userID, variantID, newQuantity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: ctx, tx, userID, role
+func (_m *UserRepo) UpdateRole(ctx context.Context, tx *sqlx.Tx, userID int, role string) error {
+	ret := _m.Called(ctx, tx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *sqlx.Tx, int, string) error); ok {
+		r0 = rf(ctx, tx, userID, role)
 	} else {
 		r0 = ret.Error(0)
 	}